@@ -16,3 +16,6 @@ var SpecReadmeContent embed.FS
 
 //go:embed config
 var ConfigFS embed.FS
+
+//go:embed web
+var WebFS embed.FS
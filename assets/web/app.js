@@ -0,0 +1,130 @@
+// Read-mostly dashboard for the .spec directory served by 'specware serve'.
+(function () {
+  "use strict";
+
+  var app = document.getElementById("app");
+
+  function escapeHTML(text) {
+    return String(text == null ? "" : text).replace(/[&<>"']/g, function (c) {
+      return { "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" }[c];
+    });
+  }
+
+  function getJSON(path) {
+    return fetch(path).then(function (response) {
+      return response.json().then(function (body) {
+        if (!response.ok) {
+          throw new Error(body.error || response.statusText);
+        }
+        return body;
+      });
+    });
+  }
+
+  function progressBar(tasks) {
+    if (!tasks || !tasks.total) {
+      return "";
+    }
+    var percent = Math.round((tasks.done / tasks.total) * 100);
+    return '<div class="meta">Tasks ' + tasks.done + "/" + tasks.total + "</div>" +
+      '<div class="progress"><div style="width: ' + percent + '%"></div></div>';
+  }
+
+  function renderBoard() {
+    return getJSON("/api/board").then(function (columns) {
+      if (!columns.length) {
+        app.innerHTML = "<p>No features found in .spec/</p>";
+        return;
+      }
+      var html = '<div class="board">';
+      columns.forEach(function (column) {
+        html += '<section class="column"><h2>' + escapeHTML(column.step || "No status") + " (" + column.features.length + ")</h2>";
+        column.features.forEach(function (feature) {
+          html += '<a class="card" href="#/feature/' + encodeURIComponent(feature["short-name"]) + '">' +
+            '<div class="name">' + escapeHTML(feature.name) + "</div>" +
+            (feature.unanswered ? '<div class="meta">' + feature.unanswered + " unanswered question(s)</div>" : "") +
+            progressBar(feature.tasks) + "</a>";
+        });
+        html += "</section>";
+      });
+      app.innerHTML = html + "</div>";
+    });
+  }
+
+  function renderQuestions(questions) {
+    if (!questions || !questions.length) {
+      return "<p>No questions recorded.</p>";
+    }
+    var html = '<div class="qa">';
+    questions.forEach(function (q) {
+      html += '<div class="entry"><strong>Q' + q.number + ":</strong> " + escapeHTML(q.question) +
+        ' <em>(' + escapeHTML(q.phase) + ")</em>";
+      if (q.default) {
+        html += "<div>Default if unknown: " + escapeHTML(q.default) + "</div>";
+      }
+      html += q.answer ? "<div>Answer: " + escapeHTML(q.answer) + "</div>" : '<div class="unanswered">Unanswered</div>';
+      html += "</div>";
+    });
+    return html + "</div>";
+  }
+
+  function renderFeature(shortName, artifact) {
+    return getJSON("/api/features/" + encodeURIComponent(shortName)).then(function (feature) {
+      var selected = artifact || (feature.artifacts.length ? feature.artifacts[0].name : "");
+      var base = "#/feature/" + encodeURIComponent(shortName);
+      var sidebar = '<aside class="sidebar"><h3>' + escapeHTML(feature.name) + "</h3>" +
+        "<p>" + escapeHTML(feature["current-step"] || "No status") + "</p>" + progressBar(feature.tasks) +
+        "<h4>Artifacts</h4><ul>";
+      feature.artifacts.forEach(function (a) {
+        sidebar += '<li><a href="' + base + "/" + encodeURIComponent(a.name) + '"' +
+          (a.name === selected ? ' class="active"' : "") + ">" + escapeHTML(a.name) + "</a></li>";
+      });
+      sidebar += '<li><a href="' + base + '/qa"' + (selected === "qa" ? ' class="active"' : "") + ">Q&amp;A</a></li></ul>";
+      if (feature.relations["depends-on"] || feature.relations.blocks) {
+        sidebar += "<h4>Relations</h4><ul>";
+        (feature.relations["depends-on"] || []).forEach(function (name) {
+          sidebar += '<li>Depends on <a href="#/feature/' + encodeURIComponent(name) + '">' + escapeHTML(name) + "</a></li>";
+        });
+        (feature.relations.blocks || []).forEach(function (name) {
+          sidebar += '<li>Blocks <a href="#/feature/' + encodeURIComponent(name) + '">' + escapeHTML(name) + "</a></li>";
+        });
+        sidebar += "</ul>";
+      }
+      sidebar += "</aside>";
+
+      if (selected === "qa") {
+        app.innerHTML = '<div class="feature">' + sidebar + '<article class="document"><h1>Questions &amp; Answers</h1>' +
+          renderQuestions(feature.questions) + "</article></div>";
+        return;
+      }
+      if (!selected) {
+        app.innerHTML = '<div class="feature">' + sidebar + '<article class="document"><p>No artifacts.</p></article></div>';
+        return;
+      }
+      return getJSON("/api/features/" + encodeURIComponent(shortName) + "/artifacts/" + encodeURIComponent(selected)).then(function (doc) {
+        // The artifact HTML is rendered and escaped by the server
+        app.innerHTML = '<div class="feature">' + sidebar + '<article class="document">' + doc.html + "</article></div>";
+      });
+    });
+  }
+
+  function route() {
+    var parts = location.hash.replace(/^#\/?/, "").split("/").map(decodeURIComponent);
+    var render = parts[0] === "feature" && parts[1] ? renderFeature(parts[1], parts[2]) : renderBoard();
+    render.catch(function (err) {
+      app.innerHTML = '<p class="error">' + escapeHTML(err.message) + "</p>";
+    });
+  }
+
+  function connect() {
+    var live = document.getElementById("live");
+    var events = new EventSource("/api/events");
+    events.onopen = function () { live.classList.add("connected"); };
+    events.onerror = function () { live.classList.remove("connected"); };
+    events.addEventListener("change", route);
+  }
+
+  window.addEventListener("hashchange", route);
+  route();
+  connect();
+})();
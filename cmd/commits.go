@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var commitsCmd = &cobra.Command{
+	Use:   "commits <short-name>",
+	Short: "List commits that reference a feature",
+	Long: `Lists commits on any local branch whose message carries a "Spec:" trailer
+naming the feature, newest first. Both the directory name and the short name
+are accepted in the trailer:
+
+  Add login handler
+
+  Spec: 007-user-auth
+
+Only the local repository is read; no remote is required.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		commits, err := spec.ListFeatureCommits(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error listing feature commits: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(commits) == 0 {
+			fmt.Printf("No commits reference feature '%s'\n", shortName)
+			return
+		}
+		for _, commit := range commits {
+			fmt.Printf("%s %s %s: %s\n", commit.ShortHash(), commit.Date, commit.Author, commit.Subject)
+		}
+	},
+}
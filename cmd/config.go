@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change settings in .spec/config.json",
+	Long: `View and change settings in .spec/config.json.
+
+Keys are dotted paths into the config file. Hyphens and underscores are
+interchangeable in setting names, so 'git.track-specs' and 'git.track_specs'
+are the same key. Names you choose, such as artifact types, are used as given:
+'artifacts.threat-model.context'.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		value, err := spec.GetConfigValue(cwd, args[0])
+		if err != nil {
+			fmt.Printf("Error reading config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a config value",
+	Long: `Changes a config value in .spec/config.json.
+
+Examples:
+  specware config set git.track-specs true        # commit .spec/ and .claude/
+  specware config set git.ignore-file .git/info/exclude
+  specware config set requirements.discovery-questions 8
+
+Only the given key is changed; other settings in the file are kept as they
+are. Changing git.track-specs or git.ignore-file rewrites the specware block in
+.gitignore (or .git/info/exclude) to match.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		config, err := spec.SetConfigValue(cwd, key, value)
+		if err != nil {
+			fmt.Printf("Error updating config: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Set %s to %s\n", key, value)
+		if !strings.HasPrefix(key, "git.") {
+			return
+		}
+		if config.Git.TrackSpecs {
+			fmt.Printf("Specs are tracked by git; %s ignores only personal settings\n", config.Git.IgnoreFile)
+		} else {
+			fmt.Printf("Specs are ignored by git via %s\n", config.Git.IgnoreFile)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+	exportAll    bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [short-name]",
+	Short: "Export a feature specification as a single document",
+	Long: `Stitches requirements.md, any technical specification files and
+implementation-plan.md into a single document with a title page (including the
+feature's current step) and a table of contents.
+
+Formats:
+  html - self-contained HTML with embedded styles, no network access required
+  md   - a single PDF-ready markdown file with page breaks between artifacts
+  docx - a Word document
+
+html and md are written to stdout unless --output is given. docx is written to
+<feature>.docx unless --output is given.
+
+With --all, an HTML page is written for every feature along with an index.html
+listing them, forming a static site in the --output directory (default: spec-export).`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if exportAll {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if exportAll {
+			outputDir := exportOutput
+			if outputDir == "" {
+				outputDir = "spec-export"
+			}
+
+			createdFiles, err := spec.ExportSite(cwd, outputDir)
+			if err != nil {
+				fmt.Printf("Error exporting specifications: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Exported specifications to %s\n", outputDir)
+			fmt.Println("\nCreated files:")
+			for _, file := range createdFiles {
+				fmt.Printf("  %s\n", file)
+			}
+			return
+		}
+
+		shortName := args[0]
+		data, err := spec.ExportFeature(cwd, shortName, exportFormat)
+		if err != nil {
+			fmt.Printf("Error exporting feature: %v\n", err)
+			os.Exit(1)
+		}
+
+		output := exportOutput
+		if output == "" && exportFormat == spec.ExportDOCX {
+			featureName := shortName
+			if doc, err := spec.BuildFeatureDocument(cwd, shortName); err == nil {
+				featureName = doc.Feature.Name
+			}
+			output = featureName + ".docx"
+		}
+
+		if output == "" {
+			os.Stdout.Write(data)
+			return
+		}
+
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			fmt.Printf("Error writing %s: %v\n", output, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported feature '%s' to %s\n", shortName, filepath.Clean(output))
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", spec.ExportHTML, "output format (html|md|docx)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file, or directory with --all")
+	exportCmd.Flags().BoolVar(&exportAll, "all", false, "export every feature as a static HTML site")
+}
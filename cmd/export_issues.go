@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	exportIssuesFormat      string
+	exportIssuesOutput      string
+	exportIssuesLabels      []string
+	exportIssuesIncludeDone bool
+)
+
+var exportIssuesCmd = &cobra.Command{
+	Use:   "export-issues <short-name>",
+	Short: "Generate issue payloads from a feature's implementation plan",
+	Long: `Turns each Milestone, Phase and Step of implementation-plan.md into an issue.
+Milestones and phases come from "Milestone N:" and "Phase N:" headings and steps
+from checkbox items; the text and code snippets under each become the issue body.
+Parent issues list their children and children name their parent.
+
+Formats:
+  github   - JSON array of {key, parent, title, body, labels}
+  gitlab   - JSON array of {key, parent, title, description, labels}
+  jira-csv - CSV for the Jira importer (Epic, Story and Sub-task rows; steps
+             outside a phase become Tasks)
+
+Issues are labelled with issues.labels from .spec/config.json, any --label
+flags, the item kind and "spec:<feature>". Steps that are already checked off
+are skipped unless --include-done is given.
+
+Output goes to stdout unless --output is given. specware makes no network
+calls; pipe the payloads to your tracker's CLI, for example:
+  specware export-issues my-feature | jq -c '.[]' | while read -r issue; do
+    echo "$issue" | gh api repos/{owner}/{repo}/issues --input -
+  done`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		shortName := args[0]
+		data, err := spec.ExportIssues(cwd, shortName, exportIssuesFormat, spec.IssueOptions{
+			Labels:      exportIssuesLabels,
+			IncludeDone: exportIssuesIncludeDone,
+		})
+		if err != nil {
+			fmt.Printf("Error exporting issues: %v\n", err)
+			os.Exit(1)
+		}
+
+		if exportIssuesOutput == "" {
+			os.Stdout.Write(data)
+			return
+		}
+
+		if err := os.WriteFile(exportIssuesOutput, data, 0644); err != nil {
+			fmt.Printf("Error writing %s: %v\n", exportIssuesOutput, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported issues for feature '%s' to %s\n", shortName, filepath.Clean(exportIssuesOutput))
+	},
+}
+
+func init() {
+	exportIssuesCmd.Flags().StringVar(&exportIssuesFormat, "format", spec.IssueFormatGitHub, "output format (github|gitlab|jira-csv)")
+	exportIssuesCmd.Flags().StringVarP(&exportIssuesOutput, "output", "o", "", "output file (default: stdout)")
+	exportIssuesCmd.Flags().StringArrayVar(&exportIssuesLabels, "label", nil, "label to add to every issue (repeatable)")
+	exportIssuesCmd.Flags().BoolVar(&exportIssuesIncludeDone, "include-done", false, "also export steps that are already checked off")
+}
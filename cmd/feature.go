@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/tiwillia/specware/internal/spec"
@@ -13,6 +15,11 @@ var featureCmd = &cobra.Command{
 	Short: "Feature specification commands",
 }
 
+var (
+	newRequirementsBranch bool
+	newRequirementsType   string
+)
+
 var newRequirementsCmd = &cobra.Command{
 	Use:   "new-requirements <short-name>",
 	Short: "Create new feature specification directory",
@@ -21,7 +28,16 @@ var newRequirementsCmd = &cobra.Command{
 This command creates a directory with the pattern XXX-<short-name> where XXX is a 
 sequential number starting from 001. The directory will contain:
 - requirements.md (copied from localized or embedded template)
-- context-requirements.md (for tracking Q&A sessions and context gathering)`,
+- context-requirements.md (for tracking Q&A sessions and context gathering)
+
+With --branch, or when git.branch_on is "new-requirements" in .spec/config.json,
+a git branch named after the feature is created and checked out. The branch and
+its base commit are recorded in .spec-status.json.
+
+Use --type to pick a template set, e.g. bugfix or spike. Sets are read from
+.spec/templates/<type>/ before the embedded ones, and templates a set does not
+define fall back to the default "feature" templates. The type is recorded in
+.spec-status.json and reused by new-implementation-plan.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		shortName := args[0]
@@ -32,7 +48,7 @@ sequential number starting from 001. The directory will contain:
 			os.Exit(1)
 		}
 
-		createdFiles, err := spec.CreateNewRequirements(cwd, shortName)
+		createdFiles, err := spec.CreateNewRequirementsWithOptions(cwd, shortName, spec.RequirementsOptions{Type: newRequirementsType})
 		if err != nil {
 			fmt.Printf("Error creating feature requirements: %v\n", err)
 			os.Exit(1)
@@ -43,6 +59,15 @@ sequential number starting from 001. The directory will contain:
 		for _, file := range createdFiles {
 			fmt.Printf("  %s\n", file)
 		}
+
+		if newRequirementsBranch {
+			branch, err := spec.CreateFeatureBranch(cwd, shortName)
+			if err != nil {
+				fmt.Printf("Warning: could not create feature branch: %v\n", err)
+				return
+			}
+			fmt.Printf("\nCreated and switched to branch %s\n", branch)
+		}
 	},
 }
 
@@ -53,7 +78,10 @@ var newImplementationPlanCmd = &cobra.Command{
 
 The feature directory must already exist (created with new-requirements). This command adds:
 - implementation-plan.md (copied from localized or embedded template) 
-- context-implementation-plan.md (for tracking implementation Q&A sessions and context gathering)`,
+- context-implementation-plan.md (for tracking implementation Q&A sessions and context gathering)
+
+The templates come from the template set recorded as the feature's type by
+new-requirements.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		shortName := args[0]
@@ -78,6 +106,85 @@ The feature directory must already exist (created with new-requirements). This c
 	},
 }
 
+var (
+	newArtifactType string
+	newArtifactList bool
+)
+
+var newArtifactCmd = &cobra.Command{
+	Use:   "new <artifact-type> <short-name>",
+	Short: "Create a feature document of any artifact type",
+	Long: `Creates a feature document from the template of an artifact type. The built-in
+types are requirements, which creates the feature like new-requirements, and
+implementation-plan. More types are declared under "artifacts" in
+.spec/config.json:
+
+  "artifacts": {
+    "threat-model": {
+      "template": "threat-model.md",
+      "context": true,
+      "requires": ["requirements"],
+      "status": "Threat Modeling"
+    }
+  }
+
+The document is written to <artifact-type>.md, from the template in
+.spec/templates/ (or a pinned pack) of the feature's template set. With
+"context", a context-<artifact-type>.md file is created for its Q&A. The
+required artifacts must exist first, and "status" is set on creation, subject
+to the same checks as update-state. Use --list to show the available types.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if newArtifactList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if newArtifactList {
+			types, err := spec.ArtifactTypes(cwd)
+			if err != nil {
+				fmt.Printf("Error listing artifact types: %v\n", err)
+				os.Exit(1)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TYPE\tTEMPLATE\tCONTEXT\tREQUIRES\tSTATUS")
+			for _, t := range types {
+				requires, status := strings.Join(t.Requires, ", "), t.Status
+				if requires == "" {
+					requires = "-"
+				}
+				if status == "" {
+					status = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n", t.Name, t.Template, t.Context, requires, status)
+			}
+			w.Flush()
+			return
+		}
+
+		artifactType, shortName := args[0], args[1]
+		createdFiles, err := spec.CreateArtifact(cwd, artifactType, shortName, spec.ArtifactOptions{Type: newArtifactType})
+		if err != nil {
+			fmt.Printf("Error creating %s: %v\n", artifactType, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Created %s for feature '%s'\n", artifactType, shortName)
+		fmt.Println("\nCreated files:")
+		for _, file := range createdFiles {
+			fmt.Printf("  %s\n", file)
+		}
+	},
+}
+
+var updateStateForce bool
+
 var updateStateCmd = &cobra.Command{
 	Use:   "update-state <short-name> <status>",
 	Short: "Update the status of a feature specification",
@@ -94,7 +201,22 @@ Claude command include:
 - "Implementation Plan Q&A"
 - "Implementation Plan Generated"
 - "Implementation Plan Interactive Review"
-- "Implementation Planning Complete"`,
+- "Implementation Planning Complete"
+- "Implementation In Progress"
+- "Implementation Complete"
+
+A warning is printed when a feature advances past planning while any of the
+features it depends on (see 'specware feature link') are not yet complete.
+
+When a feature leaves a Q&A step ("Requirements Gathering", "Requirements Expert Q&A"
+or "Implementation Plan Q&A"), the recorded questions are checked against the counts
+in .spec/config.json (see 'specware feature qa-check'). Gaps are reported as warnings,
+or block the transition if validation.enforce_question_counts is true. Use --force
+to skip blocking checks.
+
+When git.branch_on is "implementation" in .spec/config.json, entering
+"Implementation In Progress" (or a later step) creates and checks out a git
+branch named after the feature.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		shortName := args[0]
@@ -106,7 +228,8 @@ Claude command include:
 			os.Exit(1)
 		}
 
-		if err := spec.UpdateFeatureStatus(cwd, shortName, status); err != nil {
+		opts := spec.StatusUpdateOptions{Force: updateStateForce}
+		if err := spec.UpdateFeatureStatusWithOptions(cwd, shortName, status, opts); err != nil {
 			fmt.Printf("Error updating feature status: %v\n", err)
 			os.Exit(1)
 		}
@@ -115,8 +238,83 @@ Claude command include:
 	},
 }
 
+var (
+	linkDependsOn []string
+	linkBlocks    []string
+	linkRemove    bool
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <short-name>",
+	Short: "Record dependencies between features",
+	Long: `Records depends-on / blocks relationships between features in the feature's
+relations.json file.
+
+  specware feature link user-auth --depends-on user-db
+  specware feature link user-db --blocks user-auth
+
+Both commands above describe the same relationship. Links that would introduce
+a dependency cycle are rejected. Use --remove to delete existing links; either
+form removes the link, whichever feature it was recorded on.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if linkRemove {
+			if err := spec.UnlinkFeatures(cwd, shortName, linkDependsOn, linkBlocks); err != nil {
+				fmt.Printf("Error unlinking features: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Updated relations for feature '%s'\n", shortName)
+			return
+		}
+
+		if err := spec.LinkFeatures(cwd, shortName, linkDependsOn, linkBlocks); err != nil {
+			fmt.Printf("Error linking features: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, other := range linkDependsOn {
+			fmt.Printf("Feature '%s' now depends on '%s'\n", shortName, other)
+		}
+		for _, other := range linkBlocks {
+			fmt.Printf("Feature '%s' now blocks '%s'\n", shortName, other)
+		}
+	},
+}
+
 func init() {
 	featureCmd.AddCommand(newRequirementsCmd)
 	featureCmd.AddCommand(newImplementationPlanCmd)
+	featureCmd.AddCommand(newArtifactCmd)
 	featureCmd.AddCommand(updateStateCmd)
+	featureCmd.AddCommand(linkCmd)
+	featureCmd.AddCommand(commitsCmd)
+	featureCmd.AddCommand(importCmd)
+	featureCmd.AddCommand(addSpecCmd)
+	featureCmd.AddCommand(specsCmd)
+	featureCmd.AddCommand(validateSpecsCmd)
+	featureCmd.AddCommand(idsCmd)
+	featureCmd.AddCommand(traceCmd)
+	featureCmd.AddCommand(snapshotCmd)
+	featureCmd.AddCommand(diffCmd)
+	featureCmd.AddCommand(sectionCmd)
+
+	newRequirementsCmd.Flags().BoolVar(&newRequirementsBranch, "branch", false, "create and check out a git branch named after the feature")
+	newRequirementsCmd.Flags().StringVar(&newRequirementsType, "type", "", "template set to use, e.g. feature, bugfix or spike (default feature)")
+
+	newArtifactCmd.Flags().StringVar(&newArtifactType, "type", "", "template set of a new feature, only for requirements (default feature)")
+	newArtifactCmd.Flags().BoolVar(&newArtifactList, "list", false, "list the available artifact types")
+
+	updateStateCmd.Flags().BoolVar(&updateStateForce, "force", false, "update the status even if blocking checks fail")
+
+	linkCmd.Flags().StringSliceVar(&linkDependsOn, "depends-on", nil, "feature(s) this feature depends on")
+	linkCmd.Flags().StringSliceVar(&linkBlocks, "blocks", nil, "feature(s) blocked by this feature")
+	linkCmd.Flags().BoolVar(&linkRemove, "remove", false, "remove the given links instead of adding them")
 }
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Render the feature dependency graph",
+	Long: `Renders the dependency graph between features recorded with 'specware feature link'.
+
+Supported formats:
+  dot     - Graphviz DOT, e.g. 'specware graph --format dot | dot -Tsvg > graph.svg'
+  mermaid - Mermaid flowchart, suitable for embedding in markdown
+
+Edges point from a dependency to the feature that depends on it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		output, err := spec.RenderDependencyGraph(cwd, graphFormat)
+		if err != nil {
+			fmt.Printf("Error rendering dependency graph: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Print(output)
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "mermaid", "output format (dot|mermaid)")
+}
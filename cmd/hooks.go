@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that check spec hygiene",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install pre-commit and commit-msg hooks",
+	Long: `Installs git hooks that check commits touching feature directories.
+
+pre-commit:
+  - Every changed feature directory must still contain requirements.md,
+    context-requirements.md and .spec-status.json
+  - .spec-status.json must be valid JSON with a current-step and no unknown keys
+
+commit-msg:
+  - When git.require_spec_trailer is true in .spec/config.json and the checked out
+    branch belongs to a feature, commits that touch files outside .spec/ must
+    carry a "Spec: NNN-short-name" trailer
+
+Existing hooks are kept: they are renamed with a .pre-specware suffix and run
+before the specware checks. Re-running install updates the specware hooks.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			executable = "specware"
+		}
+
+		installed, err := spec.InstallHooks(cwd, executable)
+		if err != nil {
+			fmt.Printf("Error installing hooks: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Installed git hooks:")
+		for _, hook := range installed {
+			fmt.Printf("  %s\n", hook)
+		}
+	},
+}
+
+var hooksRunCmd = &cobra.Command{
+	Use:   "run <hook> [args...]",
+	Short: "Run the checks for a git hook (called by the installed hooks)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		var problems []string
+		switch args[0] {
+		case spec.HookPreCommit:
+			problems, err = spec.CheckPreCommit(cwd)
+		case spec.HookCommitMsg:
+			if len(args) < 2 {
+				fmt.Println("Error: commit-msg requires the commit message file")
+				os.Exit(1)
+			}
+			problems, err = spec.CheckCommitMessageFile(cwd, args[1])
+		default:
+			fmt.Printf("Error: unknown hook %q\n", args[0])
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Printf("Error running %s checks: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		if len(problems) > 0 {
+			fmt.Printf("specware %s check failed:\n", args[0])
+			for _, problem := range problems {
+				fmt.Printf("  %s\n", problem)
+			}
+			fmt.Println("Fix the problems above or bypass the check with 'git commit --no-verify'.")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	importFrom string
+	importName string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Create a feature from an existing issue or product document",
+	Long: `Creates a new feature directory, numbered like new-requirements, seeded from an
+existing issue or product requirements document. Use "-" to read from stdin.
+
+Sources (--from):
+  markdown          - a markdown document; the first "# " heading is the title
+  github-issue-json - an issue from the GitHub API or 'gh issue view --json title,body,number,url'
+  jira-json         - an issue from the Jira REST API (plain or Atlassian Document Format description)
+
+The original document is stored as source.md. Sections titled Problem,
+Background or Motivation pre-fill the Problem Statement of requirements.md and
+sections titled Solution, Proposal, Overview or Summary pre-fill the Solution
+Overview; without such sections the whole document becomes the Problem
+Statement. The source format, file, id and URL are recorded in .spec-status.json.
+
+The short name is derived from the title unless --name is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		var data []byte
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		featureName, createdFiles, err := spec.ImportFeature(cwd, data, spec.ImportOptions{
+			Format:     importFrom,
+			SourceName: args[0],
+			ShortName:  importName,
+		})
+		if err != nil {
+			fmt.Printf("Error importing feature: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Imported feature '%s'\n", featureName)
+		fmt.Println("\nCreated files:")
+		for _, file := range createdFiles {
+			fmt.Printf("  %s\n", file)
+		}
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFrom, "from", spec.ImportMarkdown, "source format (markdown|github-issue-json|jira-json)")
+	importCmd.Flags().StringVar(&importName, "name", "", "short name for the feature (default: derived from the title)")
+}
@@ -7,7 +7,12 @@ import (
 	"github.com/tiwillia/specware/internal/spec"
 )
 
-var yesFlag bool
+var (
+	yesFlag         bool
+	trackSpecsFlag  bool
+	ignoreSpecsFlag bool
+	gitExcludeFlag  bool
+)
 
 var initCmd = &cobra.Command{
 	Use:   "init <directory>",
@@ -21,6 +26,17 @@ This command creates the following directory structure:
   .spec/config.json     - Configuration for workflow question counts
   .spec/README.md       - Documentation for the spec workflow
 
+An existing .spec/config.json is kept when init is re-run.
+
+Git tracking (recorded in .spec/config.json as git.track_specs):
+  --ignore-specs  - Ignore .spec/ and .claude/ (default for new projects)
+  --track-specs   - Commit .spec/ and .claude/, ignoring only personal settings
+  --git-exclude   - Write the rules to .git/info/exclude instead of .gitignore
+
+A marked "specware" block is managed in the ignore file; other lines are left
+untouched. Re-running init keeps the recorded choice unless a flag is given, and
+'specware config set git.track-specs true|false' changes it later.
+
 Optional modifications (user will be prompted):
   .claude/settings.local.json - Updates project permissions to allow specware
                                 commands without prompting (personal settings only)`,
@@ -28,7 +44,19 @@ Optional modifications (user will be prompted):
 	Run: func(cmd *cobra.Command, args []string) {
 		targetDir := args[0]
 
-		createdFiles, err := spec.InitProject(targetDir)
+		opts := spec.InitOptions{}
+		if trackSpecsFlag && ignoreSpecsFlag {
+			fmt.Println("Error initializing project: --track-specs and --ignore-specs cannot be used together")
+			return
+		}
+		if trackSpecsFlag || ignoreSpecsFlag {
+			opts.TrackSpecs = &trackSpecsFlag
+		}
+		if gitExcludeFlag {
+			opts.IgnoreFile = spec.IgnoreFileExclude
+		}
+
+		createdFiles, err := spec.InitProjectWithOptions(targetDir, opts)
 		if err != nil {
 			fmt.Printf("Error initializing project: %v\n", err)
 			return
@@ -40,6 +68,14 @@ Optional modifications (user will be prompted):
 			fmt.Printf("  %s\n", file)
 		}
 
+		if config, err := spec.LoadConfig(targetDir); err == nil {
+			if config.Git.TrackSpecs {
+				fmt.Printf("\nSpecs will be tracked by git; %s ignores only personal settings\n", config.Git.IgnoreFile)
+			} else {
+				fmt.Printf("\nSpecs are ignored by git via %s (use --track-specs to commit them)\n", config.Git.IgnoreFile)
+			}
+		}
+
 		// Update Claude Code settings if requested
 		if err := spec.UpdateClaudeSettings(targetDir, yesFlag); err != nil {
 			fmt.Printf("Warning: Failed to update Claude Code settings: %v\n", err)
@@ -53,4 +89,7 @@ Optional modifications (user will be prompted):
 
 func init() {
 	initCmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "automatically answer yes to all prompts")
+	initCmd.Flags().BoolVar(&trackSpecsFlag, "track-specs", false, "commit .spec/ and .claude/ to git")
+	initCmd.Flags().BoolVar(&ignoreSpecsFlag, "ignore-specs", false, "ignore .spec/ and .claude/ in git")
+	initCmd.Flags().BoolVar(&gitExcludeFlag, "git-exclude", false, "write ignore rules to .git/info/exclude instead of .gitignore")
 }
@@ -13,7 +13,9 @@ var localizeTemplatesCmd = &cobra.Command{
 	Short: "Create project-specific templates",
 	Long: `Copies embedded templates to .spec/templates/ directory for project-specific customization.
 
-This allows you to modify templates locally for your project without affecting the embedded defaults.`,
+This allows you to modify templates locally for your project without affecting the embedded defaults.
+Existing localized templates are overwritten; use 'specware templates localize <name>' to
+copy a single template instead.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cwd, err := os.Getwd()
 		if err != nil {
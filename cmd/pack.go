@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	packUser    bool
+	packJSON    bool
+	packForce   bool
+	packUpgrade bool
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Install shared bundles of templates, commands and agents",
+	Long: `Manages template packs: versioned bundles of templates, Claude commands,
+agents and configuration defaults shared across repositories.
+
+A pack is a directory or .tar.gz archive with a pack.json manifest:
+
+  pack.json      {"name": "acme", "version": "1.2.0", "description": "..."}
+  templates/     laid out like .spec/templates, including template sets
+  commands/      installed into .claude/commands
+  agents/        installed into .claude/agents
+  config.json    configuration defaults for .spec/config.json
+
+Installing pins the pack's version under "packs" in .spec/config.json. Only
+pinned packs with a matching installed version are used. Their templates are
+looked up after .spec/templates and before the built-in templates, and can
+extend built-in templates with specware:extends.`,
+}
+
+var packInstallCmd = &cobra.Command{
+	Use:   "install <path>",
+	Short: "Install a pack from a directory or .tar.gz archive",
+	Long: `Installs a pack into .spec/packs/, or with --user into the user config directory
+(e.g. ~/.config/specware/packs/) with commands and agents in ~/.claude/, so one
+installation serves every repository. An installed version of the same pack is
+replaced.
+
+Commands and agents the pack did not install, such as specware's own, are not
+overwritten unless --force is given. Replaced files are restored when the pack
+is removed.
+
+Run inside a project, the version is pinned in .spec/config.json. A pack pinned
+to another version is only replaced with --upgrade, which moves the pin. The
+pack's config.json fills in settings missing from the project configuration or
+left at the built-in default. Settings the project changed are kept, even if
+the pack's default differs.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		pack, err := spec.InstallPack(cwd, args[0], spec.PackInstallOptions{Scope: packScope(), Force: packForce, Upgrade: packUpgrade})
+		if err != nil {
+			fmt.Printf("Error installing pack: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Installed pack %s %s to %s\n", pack.Name, pack.Version, pack.Dir)
+		if len(pack.Files) > 0 {
+			fmt.Println("\nInstalled files:")
+			for _, file := range pack.Files {
+				fmt.Printf("  %s\n", file)
+			}
+		}
+		if len(pack.Replaced) > 0 {
+			fmt.Println("\nReplaced files (restored by 'specware pack remove'):")
+			for _, file := range pack.Replaced {
+				fmt.Printf("  %s\n", file)
+			}
+		}
+		if len(pack.Config) > 0 {
+			fmt.Println("\nFilled in configuration defaults:")
+			for _, key := range pack.Config {
+				fmt.Printf("  %s\n", key)
+			}
+		}
+		if len(pack.KeptConfig) > 0 {
+			fmt.Println("\nKept project settings that differ from the pack's defaults:")
+			for _, key := range pack.KeptConfig {
+				fmt.Printf("  %s\n", key)
+			}
+		}
+		if pack.Pinned != "" {
+			fmt.Printf("\nPinned %s to version %s in .spec/config.json\n", pack.Name, pack.Pinned)
+		}
+	},
+}
+
+var packListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed and pinned packs",
+	Long: `Lists the packs installed for the project and the user, and the packs pinned
+in .spec/config.json:
+
+  active           - installed with the pinned version, and used
+  unpinned         - installed but not pinned by this project
+  version-mismatch - installed with a different version than pinned
+  missing          - pinned but not installed`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		packs, err := spec.ListPacks(cwd)
+		if err != nil {
+			fmt.Printf("Error listing packs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if packJSON {
+			data, err := json.MarshalIndent(packs, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding packs: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(packs) == 0 {
+			fmt.Println("No packs installed")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "PACK\tVERSION\tPINNED\tSCOPE\tSTATE")
+		for _, p := range packs {
+			version, pinned, scope := p.Version, p.Pinned, p.Scope
+			if version == "" {
+				version = "-"
+			}
+			if pinned == "" {
+				pinned = "-"
+			}
+			if scope == "" {
+				scope = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, version, pinned, scope, p.State)
+		}
+		w.Flush()
+	},
+}
+
+var packRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Uninstall a pack and remove its pin",
+	Long: `Removes an installed pack with the commands and agents it installed, restoring
+the files it replaced, and its pin in .spec/config.json. The project
+installation is removed if there is one, otherwise the user's; use --user to
+remove the user installation. Configuration defaults the pack filled in are
+removed from .spec/config.json, or reset to the built-in default they
+replaced, unless they were changed since.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		pack, err := spec.RemovePack(cwd, args[0], packScope())
+		if err != nil {
+			fmt.Printf("Error removing pack: %v\n", err)
+			os.Exit(1)
+		}
+
+		if pack.Dir == "" {
+			fmt.Printf("Removed the pin of pack %s\n", pack.Name)
+			return
+		}
+		fmt.Printf("Removed pack %s %s from %s\n", pack.Name, pack.Version, pack.Dir)
+	},
+}
+
+// packScope returns the pack scope selected by --user
+func packScope() string {
+	if packUser {
+		return spec.PackScopeUser
+	}
+	return ""
+}
+
+func init() {
+	packCmd.AddCommand(packInstallCmd)
+	packCmd.AddCommand(packListCmd)
+	packCmd.AddCommand(packRemoveCmd)
+
+	packInstallCmd.Flags().BoolVar(&packUser, "user", false, "install for the current user instead of the project")
+	packInstallCmd.Flags().BoolVar(&packForce, "force", false, "replace commands and agents the pack did not install")
+	packInstallCmd.Flags().BoolVar(&packUpgrade, "upgrade", false, "install a different version than the one pinned")
+	packListCmd.Flags().BoolVar(&packJSON, "json", false, "output the packs as JSON")
+	packRemoveCmd.Flags().BoolVar(&packUser, "user", false, "remove the user installation")
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	qaPhase      string
+	qaCategory   string
+	qaQuestion   string
+	qaDefault    string
+	qaReason     string
+	qaUseDefault bool
+	qaAll        bool
+
+	qaCheckCategory string
+)
+
+var qaCmd = &cobra.Command{
+	Use:   "qa",
+	Short: "Record questions and answers in feature context files",
+	Long: `Manages the "### QN:" question entries in context-requirements.md and
+context-implementation-plan.md.
+
+Questions are numbered sequentially across both phases, so implementation plan
+questions continue from the last requirements question.`,
+}
+
+var qaAddCmd = &cobra.Command{
+	Use:   "add <short-name>",
+	Short: "Add a question to a feature context file",
+	Long: `Appends a correctly numbered question to the context file for the given phase:
+
+  specware feature qa add user-auth --phase requirements \
+    --question "Will users log in with email?" --default yes \
+    --reason "most features use email login"
+
+Phases:
+  requirements        - context-requirements.md
+  implementation-plan - context-implementation-plan.md
+
+Each question is also recorded with a category matching the question counts in
+.spec/config.json (discovery or expert for requirements, plan or testing for
+implementation-plan). If --category is omitted, expert is used while the feature
+is in "Requirements Expert Q&A", otherwise discovery; implementation plan
+questions are plan questions until the configured number was asked, and testing
+questions after that.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		entry, err := spec.AddQuestion(cwd, shortName, spec.QuestionInput{
+			Phase:    qaPhase,
+			Category: qaCategory,
+			Question: qaQuestion,
+			Default:  qaDefault,
+			Reason:   qaReason,
+		})
+		if err != nil {
+			fmt.Printf("Error adding question: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Added %s to %s\n", entry.ID(), entry.File)
+	},
+}
+
+var qaAnswerCmd = &cobra.Command{
+	Use:   "answer <short-name> <question-id> [answer...]",
+	Short: "Record the answer to a question",
+	Long: `Records the answer to a question, replacing any previously recorded answer:
+
+  specware feature qa answer user-auth Q3 yes
+  specware feature qa answer user-auth Q4 --use-default`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+		id := args[1]
+		answer := strings.Join(args[2:], " ")
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		entry, err := spec.AnswerQuestion(cwd, shortName, id, answer, qaUseDefault)
+		if err != nil {
+			fmt.Printf("Error answering question: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Recorded answer to %s in %s\n", entry.ID(), entry.File)
+	},
+}
+
+var qaListCmd = &cobra.Command{
+	Use:   "list <short-name>",
+	Short: "List unanswered questions",
+	Long:  `Lists questions that do not yet have an answer. Use --all to include answered questions.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries, err := spec.ListQuestions(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error listing questions: %v\n", err)
+			os.Exit(1)
+		}
+
+		shown := 0
+		for _, entry := range entries {
+			if entry.Answered() && !qaAll {
+				continue
+			}
+			shown++
+			fmt.Printf("%s [%s] %s\n", entry.ID(), entry.Phase, entry.Question)
+			if entry.Default != "" {
+				fmt.Printf("    Default if unknown: %s\n", entry.Default)
+			}
+			if entry.Answered() {
+				fmt.Printf("    Answer: %s\n", entry.Answer)
+			}
+		}
+
+		if shown == 0 {
+			if qaAll {
+				fmt.Printf("No questions recorded for feature '%s'\n", shortName)
+			} else {
+				fmt.Printf("No unanswered questions for feature '%s'\n", shortName)
+			}
+		}
+	},
+}
+
+var qaCheckCmd = &cobra.Command{
+	Use:   "qa-check <short-name>",
+	Short: "Check that the configured number of questions were asked",
+	Long: `Compares the "### QN:" entries in context-requirements.md and
+context-implementation-plan.md against the question counts in .spec/config.json:
+
+  requirements.discovery_questions     - discovery questions (requirements)
+  requirements.expert_questions        - expert questions (requirements)
+  implementation.plan_questions        - plan questions (implementation-plan)
+  implementation.testing_questions     - testing questions (implementation-plan)
+
+Every question must also have an answer or a recorded default. Exits with a
+non-zero status if any gap is found. Use --category to check a single category.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		var categories []string
+		if qaCheckCategory != "" {
+			categories = append(categories, qaCheckCategory)
+		}
+		results, err := spec.CheckQuestions(cwd, shortName, categories...)
+		if err != nil {
+			fmt.Printf("Error checking questions: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := false
+		for _, result := range results {
+			mark := "OK  "
+			if !result.Passed() {
+				mark = "GAP "
+				failed = true
+			}
+			fmt.Printf("%s %s\n", mark, result)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	featureCmd.AddCommand(qaCmd)
+	qaCmd.AddCommand(qaAddCmd)
+	qaCmd.AddCommand(qaAnswerCmd)
+	qaCmd.AddCommand(qaListCmd)
+	featureCmd.AddCommand(qaCheckCmd)
+
+	qaAddCmd.Flags().StringVar(&qaPhase, "phase", spec.QAPhaseRequirements, "Q&A phase (requirements|implementation-plan)")
+	qaAddCmd.Flags().StringVar(&qaCategory, "category", "", "question category (discovery|expert|plan|testing)")
+	qaAddCmd.Flags().StringVar(&qaQuestion, "question", "", "yes/no question to ask")
+	qaAddCmd.Flags().StringVar(&qaDefault, "default", "", "default answer if unknown (e.g. yes or no)")
+	qaAddCmd.Flags().StringVar(&qaReason, "reason", "", "why the default makes sense")
+	qaAddCmd.MarkFlagRequired("question")
+
+	qaAnswerCmd.Flags().BoolVar(&qaUseDefault, "use-default", false, "record the question's default as the answer")
+
+	qaListCmd.Flags().BoolVar(&qaAll, "all", false, "include answered questions")
+
+	qaCheckCmd.Flags().StringVar(&qaCheckCategory, "category", "", "only check one category (discovery|expert|plan|testing)")
+}
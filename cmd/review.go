@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	reviewSection string
+	reviewFile    string
+	reviewText    string
+	reviewBy      string
+	reviewPhase   string
+	reviewAll     bool
+	reviewJSON    bool
+	reviewDoc     string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Record review comments and approvals for features",
+	Long: `Manages review feedback stored in each feature's reviews.json.
+
+Comments refer to a section of an artifact and stay open until resolved.
+Approvals are recorded per phase (requirements or implementation-plan), and
+single sections can be marked approved to track an interactive review.
+
+Setting review.required_approvals in .spec/config.json makes update-state refuse
+to enter "Implementation Planning" until the requirements, and "Implementation
+In Progress" until the implementation plan, have that many approvals.
+
+Reviewer names default to the git user.name, or $USER.`,
+}
+
+var reviewCommentCmd = &cobra.Command{
+	Use:   "comment <short-name>",
+	Short: "Comment on a section of a feature",
+	Long: `Records a review comment:
+
+  specware review comment user-auth --section "Acceptance Criteria" \
+    --text "How long is a reset link valid?"
+
+The section must match a heading. Without --file, requirements.md, then
+implementation-plan.md and the other markdown artifacts are searched for it.
+Each comment gets an ID such as 001-3 that is used to resolve it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		by := reviewBy
+		if by == "" {
+			by = spec.DefaultReviewer(cwd)
+		}
+		comment, err := spec.AddReviewComment(cwd, shortName, spec.CommentOptions{
+			Section: reviewSection,
+			File:    reviewFile,
+			Text:    reviewText,
+			By:      by,
+		})
+		if err != nil {
+			fmt.Printf("Error adding review comment: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Added comment %s on %s\n", comment.ID, commentLocation(comment))
+	},
+}
+
+var reviewListCmd = &cobra.Command{
+	Use:   "list <short-name>",
+	Short: "List review comments and approvals of a feature",
+	Long: `Lists the unresolved review comments of a feature followed by its approvals.
+Use --all to include resolved comments.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		reviews, err := spec.ListReviews(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error listing reviews: %v\n", err)
+			os.Exit(1)
+		}
+
+		if reviewJSON {
+			data, err := json.MarshalIndent(reviews, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding reviews: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		comments := reviews.Unresolved()
+		if reviewAll {
+			comments = reviews.Comments
+		}
+		if len(comments) == 0 {
+			fmt.Printf("No open review comments for feature '%s'\n", shortName)
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tSECTION\tBY\tCOMMENT")
+			for _, c := range comments {
+				by := c.By
+				if by == "" {
+					by = "-"
+				}
+				text := truncate(c.Text, 60)
+				if c.Resolved {
+					text = "(resolved) " + text
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.ID, commentLocation(c), by, text)
+			}
+			w.Flush()
+		}
+
+		for _, phase := range []string{spec.QAPhaseRequirements, spec.QAPhaseImplementationPlan} {
+			if approvers := reviews.Approvers(phase); len(approvers) > 0 {
+				fmt.Printf("\nApproved %s: %s\n", phase, strings.Join(approvers, ", "))
+			}
+		}
+	},
+}
+
+var reviewResolveCmd = &cobra.Command{
+	Use:   "resolve <id>",
+	Short: "Mark a review comment as resolved",
+	Long: `Marks a review comment as resolved. Comment IDs start with the feature number,
+e.g. 001-3, so the feature does not need to be given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		by := reviewBy
+		if by == "" {
+			by = spec.DefaultReviewer(cwd)
+		}
+		comment, err := spec.ResolveReviewComment(cwd, args[0], by)
+		if err != nil {
+			fmt.Printf("Error resolving comment: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Resolved comment %s on %s\n", comment.ID, commentLocation(comment))
+	},
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve <short-name>",
+	Short: "Approve the requirements or implementation plan of a feature",
+	Long: `Records an approval of a feature phase:
+
+  specware review approve user-auth --by alice
+
+The phase defaults to requirements until the feature reaches "Implementation
+Planning", and to implementation-plan afterwards. Approving again updates the
+existing approval.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		by := reviewBy
+		if by == "" {
+			by = spec.DefaultReviewer(cwd)
+		}
+		approval, err := spec.ApproveFeature(cwd, shortName, by, reviewPhase)
+		if err != nil {
+			fmt.Printf("Error approving feature: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Recorded approval of the %s of feature '%s' by %s\n", approval.Phase, shortName, approval.By)
+	},
+}
+
+var reviewProgressCmd = &cobra.Command{
+	Use:   "progress <short-name>",
+	Short: "Show which sections of a document have been approved",
+	Long: `Shows the review state of every section of a feature document:
+
+  pending  - not reviewed yet
+  approved - approved with 'specware review mark', unchanged since
+  changed  - approved, but the content changed afterwards
+
+The document defaults to requirements until the feature reaches "Implementation
+Planning", and to the implementation plan afterwards. Use the first section that
+is not approved to resume an interactive review.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		progress, err := spec.GetReviewProgress(cwd, shortName, reviewDoc)
+		if err != nil {
+			fmt.Printf("Error getting review progress: %v\n", err)
+			os.Exit(1)
+		}
+
+		if reviewJSON {
+			data, err := json.MarshalIndent(progress, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding review progress: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(progress.Sections) == 0 {
+			fmt.Printf("No sections found in %s\n", progress.File)
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "SECTION\tSTATE\tBY\tUPDATED")
+		for _, s := range progress.Sections {
+			by, updated := s.By, s.Updated
+			if by == "" {
+				by = "-"
+			}
+			if updated == "" {
+				updated = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Section, s.State, by, updated)
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d of %d sections of %s approved\n", progress.Approved(), len(progress.Sections), progress.File)
+		if next, ok := progress.NextPending(); ok {
+			fmt.Printf("Next: %s (line %d)\n", next.Section, next.Line)
+		}
+	},
+}
+
+var reviewMarkCmd = &cobra.Command{
+	Use:   "mark <short-name> <section> approved|pending",
+	Short: "Record the review state of a section",
+	Long: `Records that a section of a feature document was approved, or resets it to
+pending:
+
+  specware review mark user-auth "Acceptance Criteria" approved
+
+The section is a heading title or, when titles repeat, a path such as
+"Milestone 1 > Phase 2". An approval is withdrawn automatically when the
+section's content changes afterwards.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		by := reviewBy
+		if by == "" {
+			by = spec.DefaultReviewer(cwd)
+		}
+		record, err := spec.MarkSection(cwd, shortName, reviewDoc, args[1], args[2], by)
+		if err != nil {
+			fmt.Printf("Error marking section: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Marked %s > %s as %s\n", record.File, record.Section, record.State)
+	},
+}
+
+// commentLocation describes the artifact and section a comment refers to
+func commentLocation(c spec.ReviewComment) string {
+	if c.Section == "" {
+		return c.File
+	}
+	return fmt.Sprintf("%s > %s", c.File, c.Section)
+}
+
+func init() {
+	reviewCmd.AddCommand(reviewCommentCmd)
+	reviewCmd.AddCommand(reviewListCmd)
+	reviewCmd.AddCommand(reviewResolveCmd)
+	reviewCmd.AddCommand(reviewApproveCmd)
+	reviewCmd.AddCommand(reviewProgressCmd)
+	reviewCmd.AddCommand(reviewMarkCmd)
+
+	reviewCommentCmd.Flags().StringVar(&reviewSection, "section", "", "heading of the section commented on")
+	reviewCommentCmd.Flags().StringVar(&reviewFile, "file", "", "artifact commented on (default: the one containing --section)")
+	reviewCommentCmd.Flags().StringVar(&reviewText, "text", "", "comment text (required)")
+	reviewCommentCmd.Flags().StringVar(&reviewBy, "by", "", "reviewer name")
+	reviewCommentCmd.MarkFlagRequired("text")
+
+	reviewListCmd.Flags().BoolVar(&reviewAll, "all", false, "include resolved comments")
+	reviewListCmd.Flags().BoolVar(&reviewJSON, "json", false, "output comments and approvals as JSON")
+
+	reviewResolveCmd.Flags().StringVar(&reviewBy, "by", "", "name of the person resolving the comment")
+
+	reviewApproveCmd.Flags().StringVar(&reviewBy, "by", "", "reviewer name")
+	reviewApproveCmd.Flags().StringVar(&reviewPhase, "phase", "", "phase approved: requirements or implementation-plan")
+
+	reviewProgressCmd.Flags().StringVar(&reviewDoc, "doc", "", "document to show: requirements, plan or another markdown artifact")
+	reviewProgressCmd.Flags().BoolVar(&reviewJSON, "json", false, "output the review progress as JSON")
+
+	reviewMarkCmd.Flags().StringVar(&reviewDoc, "doc", "", "document containing the section: requirements, plan or another markdown artifact")
+	reviewMarkCmd.Flags().StringVar(&reviewBy, "by", "", "reviewer name")
+}
@@ -22,5 +22,17 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(localizeTemplatesCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(packCmd)
 	rootCmd.AddCommand(featureCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(exportIssuesCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(hooksCmd)
 }
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	searchIn     string
+	searchStatus string
+	searchJSON   bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search across all feature specifications",
+	Long: `Performs a case-insensitive full-text search across requirements, implementation
+plans, context files and technical specification artifacts in .spec/.
+
+Matches are grouped by feature and by the markdown section heading they appear
+under, and ranked so that features and sections matching more of the query
+(and matching in headings) are listed first. Multiple words in the query are
+matched individually, with a bonus for lines containing the whole phrase.
+
+Use --in to restrict the search to requirements, plan, context or spec files,
+or to the documents of artifact types configured in .spec/config.json, and
+--status to restrict it to features at a given current-step.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := strings.Join(args, " ")
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		results, err := spec.SearchSpecs(cwd, query, spec.SearchOptions{
+			In:     searchIn,
+			Status: searchStatus,
+		})
+		if err != nil {
+			fmt.Printf("Error searching specifications: %v\n", err)
+			os.Exit(1)
+		}
+
+		if searchJSON {
+			if results == nil {
+				results = []spec.SearchResult{}
+			}
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				fmt.Printf("Error formatting results: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(results) == 0 {
+			fmt.Printf("No matches found for '%s'\n", query)
+			return
+		}
+
+		for _, result := range results {
+			if result.Status != "" {
+				fmt.Printf("%s (%s)\n", result.Feature, result.Status)
+			} else {
+				fmt.Println(result.Feature)
+			}
+			for _, section := range result.Sections {
+				if section.Heading != "" {
+					fmt.Printf("  %s > %s\n", section.File, section.Heading)
+				} else {
+					fmt.Printf("  %s\n", section.File)
+				}
+				for _, match := range section.Matches {
+					fmt.Printf("    %d: %s\n", match.Line, match.Text)
+				}
+			}
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	searchCmd.Flags().StringVar(&searchIn, "in", "", "only search one artifact kind (requirements|plan|context|spec|document)")
+	searchCmd.Flags().StringVar(&searchStatus, "status", "", "only search features with this current-step")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "output results as JSON")
+}
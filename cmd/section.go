@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var sectionWithHeading bool
+
+var sectionCmd = &cobra.Command{
+	Use:   "section",
+	Short: "Read and replace sections of feature documents",
+	Long: `Reads and writes single sections of a feature's markdown documents, delimited
+by their headings, so a section can be shown or edited without touching the rest
+of the file.
+
+<doc> is requirements, plan, or the name of another markdown artifact such as
+context-requirements. <heading> is a heading title, matched ignoring case. When
+a title repeats, give its path as shown by 'section list', e.g.
+"Milestone 1 > Phase 2".`,
+}
+
+var sectionListCmd = &cobra.Command{
+	Use:   "list <short-name> <doc>",
+	Short: "List the headings of a feature document",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		sections, err := spec.ListSections(cwd, args[0], args[1])
+		if err != nil {
+			fmt.Printf("Error listing sections: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(sections) == 0 {
+			fmt.Printf("No headings found in %s\n", args[1])
+			return
+		}
+		for _, s := range sections {
+			fmt.Printf("%4d  %s%s %s\n", s.Line, strings.Repeat("  ", s.Level-1), strings.Repeat("#", s.Level), s.Title)
+		}
+	},
+}
+
+var sectionGetCmd = &cobra.Command{
+	Use:   "get <short-name> <doc> <heading>",
+	Short: "Print a section of a feature document verbatim",
+	Long: `Prints the content below a heading exactly as written in the file, including
+nested sub-sections. Use --with-heading to include the heading line.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		content, err := spec.GetSection(cwd, args[0], args[1], args[2], sectionWithHeading)
+		if err != nil {
+			fmt.Printf("Error reading section: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(content)
+	},
+}
+
+var sectionSetCmd = &cobra.Command{
+	Use:   "set <short-name> <doc> <heading>",
+	Short: "Replace a section of a feature document with content from stdin",
+	Long: `Replaces the content below a heading, including nested sub-sections, with the
+content read from stdin:
+
+  specware feature section set user-auth requirements "Problem Statement" <<'EOF'
+  Users cannot recover their accounts without contacting support.
+  EOF
+
+The heading and everything outside the section are left byte-for-byte
+unchanged.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Printf("Error reading stdin: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := spec.SetSection(cwd, args[0], args[1], args[2], content); err != nil {
+			fmt.Printf("Error updating section: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Updated section '%s' of %s for feature '%s'\n", args[2], args[1], args[0])
+	},
+}
+
+func init() {
+	sectionCmd.AddCommand(sectionListCmd)
+	sectionCmd.AddCommand(sectionGetCmd)
+	sectionCmd.AddCommand(sectionSetCmd)
+
+	sectionGetCmd.Flags().BoolVar(&sectionWithHeading, "with-heading", false, "include the heading line")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/server"
+)
+
+var (
+	servePort int
+	serveAPI  bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local web dashboard for the project's specifications",
+	Long: `Starts a web dashboard for the .spec directory, listening on localhost only.
+
+The dashboard shows:
+  - A board of features grouped by current step
+  - Per-feature pages rendering the markdown artifacts
+  - Recorded Q&A and implementation plan task progress
+
+Pages refresh automatically when files under .spec/ change.
+
+A read-only JSON API is always available under /api. Pass --api to also enable
+the endpoints that modify specifications (new requirements and implementation
+plans, status updates, Q&A and feature links). These accept JSON requests only.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := server.New(cwd, server.Options{Port: servePort, EnableWrites: serveAPI})
+		if err := s.Check(); err != nil {
+			fmt.Printf("Error starting dashboard: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Serving specware dashboard at http://%s/ (press Ctrl+C to stop)\n", s.Addr())
+		if err := s.ListenAndServe(); err != nil {
+			fmt.Printf("Error serving dashboard: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "localhost port to listen on")
+	serveCmd.Flags().BoolVar(&serveAPI, "api", false, "enable JSON API endpoints that modify specifications")
+}
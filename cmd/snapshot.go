@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	snapshotMessage string
+	snapshotList    bool
+	diffJSON        bool
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <short-name>",
+	Short: "Store a copy of a feature's artifacts for later comparison",
+	Long: `Copies the requirements, context, technical spec and implementation plan files
+of a feature to .snapshots/<timestamp>/ in the feature directory. Compare the
+current files with a snapshot using 'specware feature diff'.
+
+A snapshot is also taken automatically whenever update-state changes the
+feature's status, unless nothing changed since the latest snapshot.
+
+With --list, the existing snapshots are listed instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if snapshotList {
+			snapshots, err := spec.ListSnapshots(cwd, shortName)
+			if err != nil {
+				fmt.Printf("Error listing snapshots: %v\n", err)
+				os.Exit(1)
+			}
+			if len(snapshots) == 0 {
+				fmt.Printf("No snapshots for feature '%s'\n", shortName)
+				return
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "SNAPSHOT\tSTATUS\tREASON")
+			for _, s := range snapshots {
+				status, reason := s.Status, s.Reason
+				if status == "" {
+					status = "-"
+				}
+				if reason == "" {
+					reason = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", s.ID, status, reason)
+			}
+			w.Flush()
+			return
+		}
+
+		snapshot, err := spec.CreateSnapshot(cwd, shortName, snapshotMessage)
+		if err != nil {
+			fmt.Printf("Error creating snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created snapshot %s of feature '%s' (%d files)\n", snapshot.ID, shortName, len(snapshot.Files))
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <short-name> [snapshot]",
+	Short: "Show what changed in a feature since a snapshot",
+	Long: `Compares requirements.md and implementation-plan.md with a snapshot taken by
+'specware feature snapshot' or by a status change, section by section. Each
+changed section is shown under its heading path, e.g.
+"Implementation Plan > Milestone 1 > Phase 2", with its added (+) and removed
+(-) lines.
+
+Without [snapshot] the latest snapshot is used. A snapshot may be given by its
+ID or a unique prefix of it; see 'specware feature snapshot --list'.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+		snapshotID := ""
+		if len(args) == 2 {
+			snapshotID = args[1]
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		diff, err := spec.DiffFeature(cwd, shortName, snapshotID)
+		if err != nil {
+			fmt.Printf("Error comparing feature: %v\n", err)
+			os.Exit(1)
+		}
+
+		if diffJSON {
+			data, err := json.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding diff: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("Changes to feature '%s' since snapshot %s\n", shortName, diff.Snapshot.ID)
+		if len(diff.Files) == 0 {
+			fmt.Println("\nNo changes")
+			return
+		}
+		for _, file := range diff.Files {
+			fmt.Printf("\n=== %s\n", file.Name)
+			for _, section := range file.Sections {
+				path := section.Path
+				if path == "" {
+					path = "(before first heading)"
+				}
+				fmt.Printf("\n## %s (%s)\n", path, section.Change)
+				for _, line := range section.Lines {
+					if line.Op == "..." {
+						fmt.Println("  ...")
+						continue
+					}
+					fmt.Printf("%s %s\n", line.Op, line.Text)
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	snapshotCmd.Flags().StringVarP(&snapshotMessage, "message", "m", "", "reason recorded with the snapshot")
+	snapshotCmd.Flags().BoolVar(&snapshotList, "list", false, "list the feature's snapshots")
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "output the diff as JSON")
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+// techSpecTypesHelp lists the technical spec types for command help
+func techSpecTypesHelp() string {
+	var b strings.Builder
+	for _, t := range spec.TechSpecTypes {
+		fmt.Fprintf(&b, "  %-14s - %s (default: %s)\n", t.Name, t.Description, t.DefaultName)
+	}
+	return b.String()
+}
+
+var addSpecCmd = &cobra.Command{
+	Use:   "add-spec <short-name> <type> [name]",
+	Short: "Create a technical specification from a template",
+	Long: `Creates a technical specification in the feature directory from the template for
+its type and registers it in specs.json. Types:
+
+` + techSpecTypesHelp() + `
+The template's extension is added when [name] has none. Templates are read from
+.spec/templates/specs/ when localized with 'specware localize-templates'.`,
+	Args: cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+		name := ""
+		if len(args) == 3 {
+			name = args[2]
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		createdFile, err := spec.AddTechSpec(cwd, shortName, args[1], name)
+		if err != nil {
+			fmt.Printf("Error adding technical spec: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Added %s spec to feature '%s'\n", args[1], shortName)
+		fmt.Println("\nCreated files:")
+		fmt.Printf("  %s\n", createdFile)
+	},
+}
+
+var specsCmd = &cobra.Command{
+	Use:   "specs <short-name>",
+	Short: "List the technical specifications of a feature",
+	Long: `Lists the technical specifications registered in the feature's specs.json,
+followed by any other technical spec files in the feature directory that were
+not created with add-spec.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries, err := spec.ListTechSpecs(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error listing technical specs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(entries) == 0 {
+			fmt.Printf("No technical specs for feature '%s'\n", shortName)
+			return
+		}
+		for _, entry := range entries {
+			switch {
+			case !entry.Registered:
+				fmt.Printf("%-14s %s (unregistered)\n", "-", entry.Name)
+			case !entry.Exists:
+				fmt.Printf("%-14s %s (missing)\n", entry.Type, entry.Name)
+			default:
+				fmt.Printf("%-14s %s\n", entry.Type, entry.Name)
+			}
+		}
+	},
+}
+
+var validateSpecsCmd = &cobra.Command{
+	Use:   "validate-specs <short-name>",
+	Short: "Validate the technical specifications of a feature",
+	Long: `Checks the technical specifications in the feature directory without network
+access:
+
+  YAML / JSON  - syntax, with the line of the first error
+  OpenAPI      - openapi 3.x version, info, paths, operation responses, declared
+                 path parameters, unique operationIds and local $refs
+  JSON Schema  - well-formed keywords (type, required, properties, ...) and local $refs
+  Mermaid      - known diagram type and balanced subgraph/end, block/end and {}
+                 in .mmd files and mermaid code blocks of markdown files
+
+The kind of a file comes from specs.json (see add-spec) or from its extension
+and content. Exits with a non-zero status if any problem is found.
+
+When validation.enforce_valid_specs is true in .spec/config.json, update-state
+refuses to enter "Implementation Planning" until the specs are valid (use
+--force to override); otherwise problems are printed as warnings.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		results, err := spec.ValidateTechSpecs(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error validating technical specs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(results) == 0 {
+			fmt.Printf("No technical specs to validate for feature '%s'\n", shortName)
+			return
+		}
+
+		failed := false
+		for _, result := range results {
+			if result.Valid() {
+				fmt.Printf("OK   %s (%s)\n", result.File, result.Kind)
+				continue
+			}
+			failed = true
+			fmt.Printf("FAIL %s (%s)\n", result.File, result.Kind)
+			for _, problem := range result.Problems {
+				fmt.Printf("       %s\n", problem)
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
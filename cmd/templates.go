@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	templatesType    string
+	templatesJSON    bool
+	templatesExtends bool
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Inspect and customize the templates used for feature artifacts",
+	Long: `Lists, compares, localizes and resets the templates used to create feature
+artifacts. Template names are paths below .spec/templates/, e.g. requirements or
+bugfix/requirements; .md is added when there is no extension.
+
+A localized template in .spec/templates/ can extend the embedded one instead of
+replacing it, so upstream improvements are kept. Start the file with an extends
+directive, then add, replace or remove named sections:
+
+  <!-- specware:extends -->
+
+  <!-- specware:add after "Technical Requirements" -->
+  ## Security Review
+  Threats, authentication and handling of sensitive data.
+
+  <!-- specware:remove "Dependencies" -->
+
+  <!-- specware:replace "Constraints" -->
+  Budget and deadline limitations only.
+
+The base defaults to the template being overridden; give a name such as
+"<!-- specware:extends requirements.md -->" to extend another template. Content
+that does not start with a heading replaces only the section's body, including
+its sub-sections.`,
+}
+
+var templatesRenderCmd = &cobra.Command{
+	Use:   "render <name>",
+	Short: "Show the effective content of a template",
+	Long: `Prints a template as it is used when creating artifacts, with localized
+overrides and inheritance applied:
+
+  specware templates render requirements
+  specware templates render implementation-plan --type bugfix`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		content, err := spec.RenderTemplate(cwd, templatesType, args[0])
+		if err != nil {
+			fmt.Printf("Error rendering template: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(content))
+	},
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and whether they are localized",
+	Long: `Lists the built-in and localized templates with their state:
+
+  embedded   - the built-in template is used
+  localized  - a copy in .spec/templates/ identical to the built-in template
+  overridden - a copy in .spec/templates/ that differs from or extends it
+  custom     - a template in .spec/templates/ with no built-in counterpart
+  pack       - provided by a pinned pack (see 'specware pack')`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		templates, err := spec.ListTemplates(cwd)
+		if err != nil {
+			fmt.Printf("Error listing templates: %v\n", err)
+			os.Exit(1)
+		}
+
+		if templatesJSON {
+			data, err := json.MarshalIndent(templates, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding templates: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "TEMPLATE\tSTATE")
+		for _, t := range templates {
+			state := t.State
+			if t.Extends {
+				state += " (extends)"
+			}
+			fmt.Fprintf(w, "%s\t%s\n", t.Name, state)
+		}
+		w.Flush()
+	},
+}
+
+var templatesDiffCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Compare a localized template with the built-in version",
+	Long: `Shows how the effective content of a template, with any inheritance applied,
+differs from the built-in version:
+
+  specware templates diff requirements
+  specware templates diff bugfix/implementation-plan`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		diff, err := spec.DiffTemplate(cwd, args[0])
+		if err != nil {
+			fmt.Printf("Error comparing template: %v\n", err)
+			os.Exit(1)
+		}
+
+		if templatesJSON {
+			data, err := json.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding diff: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(diff.Lines) == 0 {
+			fmt.Printf("Template %s is %s and matches the built-in version\n", diff.Name, diff.State)
+			return
+		}
+		fmt.Printf("--- built-in %s\n+++ .spec/templates/%s\n", diff.Name, diff.Name)
+		for _, line := range diff.Lines {
+			if line.Op == "..." {
+				fmt.Println("  ...")
+				continue
+			}
+			fmt.Printf("%s %s\n", line.Op, line.Text)
+		}
+	},
+}
+
+var templatesLocalizeCmd = &cobra.Command{
+	Use:   "localize <name>",
+	Short: "Copy a single built-in template to .spec/templates/",
+	Long: `Copies one built-in template to .spec/templates/ for customization, leaving
+other localized templates untouched. A template that is already localized is
+not overwritten.
+
+With --extends, a file extending the built-in template is created instead of a
+full copy, so only the sections you add, replace or remove are maintained
+locally.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		file, err := spec.LocalizeTemplate(cwd, args[0], templatesExtends)
+		if err != nil {
+			fmt.Printf("Error localizing template: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created %s\n", file)
+	},
+}
+
+var templatesResetCmd = &cobra.Command{
+	Use:   "reset <name>",
+	Short: "Remove a localized template to use the built-in one again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		file, err := spec.ResetTemplate(cwd, args[0])
+		if err != nil {
+			fmt.Printf("Error resetting template: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed %s\n", file)
+	},
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesRenderCmd)
+	templatesCmd.AddCommand(templatesDiffCmd)
+	templatesCmd.AddCommand(templatesLocalizeCmd)
+	templatesCmd.AddCommand(templatesResetCmd)
+
+	templatesListCmd.Flags().BoolVar(&templatesJSON, "json", false, "output the templates as JSON")
+	templatesRenderCmd.Flags().StringVar(&templatesType, "type", "", "template set to render from (default feature)")
+	templatesDiffCmd.Flags().BoolVar(&templatesJSON, "json", false, "output the diff as JSON")
+	templatesLocalizeCmd.Flags().BoolVar(&templatesExtends, "extends", false, "create a file extending the built-in template instead of a copy")
+}
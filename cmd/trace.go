@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var traceJSON bool
+
+var idsCmd = &cobra.Command{
+	Use:   "ids <short-name>",
+	Short: "Assign IDs to requirements and acceptance criteria",
+	Long: `Gives every top-level bullet under the Functional Requirements, Technical
+Requirements and Acceptance Criteria sections of requirements.md a stable ID:
+
+  - FR-1: Users can reset their password by email
+  - AC-3: A reset link expires after 24 hours
+
+Existing IDs are never changed, so IDs can be referenced from the implementation
+plan and tests. New bullets are numbered after the highest ID of their section.
+Running the command again only numbers bullets that were added since.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		requirements, assigned, err := spec.AssignRequirementIDs(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error assigning requirement IDs: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(requirements) == 0 {
+			fmt.Printf("No requirements found for feature '%s'\n", shortName)
+			return
+		}
+		for _, req := range requirements {
+			fmt.Printf("%-6s %s\n", req.ID, req.Text)
+		}
+		fmt.Printf("\nAssigned %d new ID(s) in requirements.md\n", assigned)
+	},
+}
+
+var traceCmd = &cobra.Command{
+	Use:   "trace <short-name>",
+	Short: "Show which plan steps cover each requirement",
+	Long: `Builds a traceability report between requirements.md and implementation-plan.md.
+A milestone, phase or step covers a requirement when its title or body mentions
+the requirement's ID (see 'specware feature ids'), e.g.
+
+  - [ ] Step 3: Add the reset endpoint (FR-2, AC-1)
+
+The report lists every requirement with the plan items covering it, followed by
+uncovered requirements, requirements without an ID and IDs referenced by the
+plan that do not exist in requirements.md.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		report, err := spec.TraceRequirements(cwd, shortName)
+		if err != nil {
+			fmt.Printf("Error tracing requirements: %v\n", err)
+			os.Exit(1)
+		}
+
+		if traceJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(report.Requirements) == 0 {
+			fmt.Printf("No requirements found for feature '%s'\n", shortName)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tREQUIREMENT\tCOVERED BY")
+		for _, req := range report.Requirements {
+			id := req.ID
+			if id == "" {
+				id = "-"
+			}
+			coveredBy := "-"
+			if len(req.CoveredBy) > 0 {
+				coveredBy = strings.Join(req.CoveredBy, "; ")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", id, truncate(req.Text, 50), coveredBy)
+		}
+		w.Flush()
+
+		if !report.HasPlan {
+			fmt.Printf("\nNo implementation-plan.md yet; create one with 'specware feature new-implementation-plan %s'\n", shortName)
+		}
+		if uncovered := report.Uncovered(); len(uncovered) > 0 {
+			var ids []string
+			for _, req := range uncovered {
+				ids = append(ids, req.ID)
+			}
+			fmt.Printf("\nUncovered: %s\n", strings.Join(ids, ", "))
+		}
+		if report.Unnumbered > 0 {
+			fmt.Printf("\n%d requirement(s) have no ID; run 'specware feature ids %s'\n", report.Unnumbered, shortName)
+		}
+		for _, ref := range report.Unknown {
+			fmt.Printf("\nUnknown ID %s referenced by: %s\n", ref.ID, strings.Join(ref.ReferencedBy, "; "))
+		}
+	},
+}
+
+// truncate shortens text to at most n runes, marking the cut with "..."
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "output the report as JSON")
+}
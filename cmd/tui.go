@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/tui"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and update features in an interactive terminal UI",
+	Long: `Opens an interactive terminal UI listing all features with their current step.
+
+From the feature list you can:
+  - Open a feature and scroll through its artifacts (h/l to switch, j/k to scroll)
+  - Toggle implementation plan checkboxes (t, then space)
+  - Change the feature status (s), with the same checks as 'feature update-state'
+
+When stdin or stdout is not a terminal, a plain text table of features is
+printed instead.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !tui.IsTerminal(int(os.Stdin.Fd())) || !tui.IsTerminal(int(os.Stdout.Fd())) {
+			if err := tui.WriteTable(os.Stdout, cwd); err != nil {
+				fmt.Printf("Error listing features: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := tui.Run(cwd, os.Stdin, os.Stdout); err != nil {
+			fmt.Printf("Error running terminal UI: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var (
+	verifyRun         bool
+	verifyTestCommand string
+	verifyJSON        bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <short-name>",
+	Short: "Check that acceptance criteria are covered by tests",
+	Long: `Lists the bullets under "Acceptance Criteria" in requirements.md and searches
+the project for tests referencing them. A criterion is identified by its
+AC-n prefix (see 'specware feature ids'), or by its position when it has none.
+
+Tests reference criteria with a comment in any language:
+
+  // spec: 007-user-auth AC-3, AC-4
+
+or with Ginkgo labels:
+
+  It("expires reset links", Label("user-auth", "AC-3"), func() { ... })
+
+A label belongs to the feature it names in the same Label call, or to the
+feature of a "spec: <feature>" comment in its file. A label naming no feature
+in a file without such a comment counts only when no other feature has a
+criterion with the same ID; otherwise it is reported as ambiguous.
+
+The feature may be given by its directory name or short name. .spec/, hidden
+directories, vendor/ and node_modules/ are not scanned.
+
+With --run, the test command from --test-command or verify.test_command is run
+in the project root and its result is mapped onto the covered criteria. If the
+command contains {ac} it runs once per criterion with {ac} replaced by the ID,
+e.g. "ginkgo -r --label-filter={ac}"; otherwise it runs once and its result
+applies to every covered criterion. {feature} is replaced by the feature name.
+
+Exits with status 1 when a criterion is uncovered or its tests fail.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		shortName := args[0]
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error getting current directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		report, err := spec.VerifyFeature(cwd, shortName, spec.VerifyOptions{
+			RunTests:    verifyRun,
+			TestCommand: verifyTestCommand,
+		})
+		if err != nil {
+			fmt.Printf("Error verifying feature: %v\n", err)
+			os.Exit(1)
+		}
+
+		if verifyJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Printf("Error encoding report: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			if len(report.Criteria) == 0 {
+				fmt.Printf("No acceptance criteria found for feature '%s'\n", shortName)
+				return
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tSTATUS\tCRITERION\tTESTS")
+			for _, c := range report.Criteria {
+				tests := "-"
+				if len(c.References) > 0 {
+					var refs []string
+					for _, ref := range c.References {
+						refs = append(refs, fmt.Sprintf("%s:%d", ref.File, ref.Line))
+					}
+					tests = strings.Join(refs, ", ")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.ID, c.Status, truncate(c.Text, 50), tests)
+			}
+			w.Flush()
+
+			if len(report.Skipped) > 0 {
+				fmt.Printf("\nWarning: could not scan %s\n", strings.Join(report.Skipped, ", "))
+			}
+			if len(report.Ambiguous) > 0 {
+				var refs []string
+				for _, ref := range report.Ambiguous {
+					refs = append(refs, fmt.Sprintf("%s (%s:%d)", ref.ID, ref.File, ref.Line))
+				}
+				fmt.Printf("\nWarning: not counted, other features have the same criteria; name the feature in the Label: %s\n",
+					strings.Join(refs, ", "))
+			}
+			for _, run := range report.Runs {
+				if !run.Passed {
+					fmt.Printf("\nFailed: %s (%s)\n%s\n", run.Command, strings.Join(run.Criteria, ", "), run.Output)
+				}
+			}
+
+			covered := 0
+			for _, c := range report.Criteria {
+				if c.Status != spec.CoverageUncovered {
+					covered++
+				}
+			}
+			fmt.Printf("\n%d of %d acceptance criteria covered by tests\n", covered, len(report.Criteria))
+		}
+
+		if !report.Complete() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyRun, "run", false, "run the test command and map results to criteria")
+	verifyCmd.Flags().StringVar(&verifyTestCommand, "test-command", "", "test command to run instead of verify.test_command")
+	verifyCmd.Flags().BoolVar(&verifyJSON, "json", false, "output the report as JSON")
+}
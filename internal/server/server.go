@@ -0,0 +1,577 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiwillia/specware/assets"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+// Options configures the dashboard server
+type Options struct {
+	// Port is the localhost port to listen on
+	Port int
+	// EnableWrites exposes the JSON endpoints that modify specifications
+	EnableWrites bool
+	// PollInterval is how often the .spec directory is checked for changes
+	PollInterval time.Duration
+}
+
+// Server serves the web dashboard and JSON API for a project's .spec directory
+type Server struct {
+	targetDir string
+	opts      Options
+	mux       *http.ServeMux
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// FeatureSummary is a feature as shown on the dashboard board
+type FeatureSummary struct {
+	spec.Feature
+	Tasks      spec.TaskProgress `json:"tasks"`
+	Unanswered int               `json:"unanswered"`
+}
+
+// BoardColumn groups the features sharing a current step
+type BoardColumn struct {
+	Step     string           `json:"step"`
+	Features []FeatureSummary `json:"features"`
+}
+
+// FeatureDetail is everything the dashboard shows on a feature page
+type FeatureDetail struct {
+	FeatureSummary
+	Artifacts []spec.Artifact       `json:"artifacts"`
+	Questions []spec.QAEntry        `json:"questions"`
+	TaskList  []spec.PlanTask       `json:"task-list"`
+	Relations spec.FeatureRelations `json:"relations"`
+}
+
+// ArtifactContent is a single artifact with its rendered HTML
+type ArtifactContent struct {
+	spec.Artifact
+	Content string `json:"content"`
+	HTML    string `json:"html"`
+}
+
+// New creates a dashboard server for the project in targetDir
+func New(targetDir string, opts Options) *Server {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	s := &Server{
+		targetDir:   targetDir,
+		opts:        opts,
+		mux:         http.NewServeMux(),
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	s.routes()
+	return s
+}
+
+// Addr returns the localhost address the server listens on
+func (s *Server) Addr() string {
+	return net.JoinHostPort("127.0.0.1", fmt.Sprint(s.opts.Port))
+}
+
+// Handler returns the HTTP handler, rejecting requests addressed to any host
+// other than localhost to guard against DNS rebinding
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLocalHost(r.Host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe watches the .spec directory for changes and serves the
+// dashboard on localhost until the server fails
+func (s *Server) ListenAndServe() error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.watch(stop)
+
+	server := &http.Server{
+		Addr:              s.Addr(),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// routes registers the dashboard, static asset and API handlers
+func (s *Server) routes() {
+	static, _ := fs.Sub(assets.WebFS, "web")
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	s.mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, static, "index.html")
+	})
+
+	s.mux.HandleFunc("GET /api/features", s.handleListFeatures)
+	s.mux.HandleFunc("GET /api/board", s.handleBoard)
+	s.mux.HandleFunc("GET /api/features/{name}", s.handleGetFeature)
+	s.mux.HandleFunc("GET /api/features/{name}/artifacts/{file}", s.handleGetArtifact)
+	s.mux.HandleFunc("GET /api/events", s.handleEvents)
+
+	if s.opts.EnableWrites {
+		s.mux.HandleFunc("POST /api/features", s.handleNewRequirements)
+		s.mux.HandleFunc("POST /api/features/{name}/implementation-plan", s.handleNewImplementationPlan)
+		s.mux.HandleFunc("POST /api/features/{name}/status", s.handleUpdateStatus)
+		s.mux.HandleFunc("POST /api/features/{name}/questions", s.handleAddQuestion)
+		s.mux.HandleFunc("POST /api/features/{name}/questions/{id}/answer", s.handleAnswerQuestion)
+		s.mux.HandleFunc("POST /api/features/{name}/links", s.handleLink)
+	}
+}
+
+// isLocalHost reports whether a Host header names the loopback interface
+func isLocalHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// readJSON decodes a JSON request body. Requiring the JSON content type keeps
+// other sites from submitting cross-origin forms to the write endpoints.
+func readJSON(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("expected Content-Type application/json"))
+		return false
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// summarize gathers the task progress and unanswered question count of a feature
+func (s *Server) summarize(feature spec.Feature) (FeatureSummary, error) {
+	summary := FeatureSummary{Feature: feature}
+	tasks, err := spec.ListPlanTasks(s.targetDir, feature.ShortName)
+	if err != nil {
+		return summary, err
+	}
+	summary.Tasks = spec.PlanProgress(tasks)
+
+	questions, err := spec.ListQuestions(s.targetDir, feature.ShortName)
+	if err != nil {
+		return summary, err
+	}
+	for _, q := range questions {
+		if !q.Answered() {
+			summary.Unanswered++
+		}
+	}
+	return summary, nil
+}
+
+// listSummaries returns the summary of every feature
+func (s *Server) listSummaries() ([]FeatureSummary, error) {
+	features, err := spec.ListFeatures(s.targetDir)
+	if err != nil {
+		return nil, err
+	}
+	summaries := []FeatureSummary{}
+	for _, feature := range features {
+		summary, err := s.summarize(feature)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// findFeature returns the feature with the given short name
+func (s *Server) findFeature(shortName string) (spec.Feature, error) {
+	features, err := spec.ListFeatures(s.targetDir)
+	if err != nil {
+		return spec.Feature{}, err
+	}
+	for _, feature := range features {
+		if feature.ShortName == shortName {
+			return feature, nil
+		}
+	}
+	return spec.Feature{}, fmt.Errorf("feature %s not found", shortName)
+}
+
+func (s *Server) handleListFeatures(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.listSummaries()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// BuildBoard groups feature summaries by current step, ordering columns by the
+// workflow and placing unrecognized steps after the known ones
+func BuildBoard(summaries []FeatureSummary) []BoardColumn {
+	columns := []BoardColumn{}
+	index := make(map[string]int)
+	for _, summary := range summaries {
+		// Known steps are shown under their canonical name so that
+		// "requirements-gathering" and "Requirements Gathering" share a column
+		step := summary.Status
+		if i := spec.StepIndex(step); i >= 0 {
+			step = spec.WorkflowSteps[i]
+		}
+		i, ok := index[step]
+		if !ok {
+			i = len(columns)
+			index[step] = i
+			columns = append(columns, BoardColumn{Step: step})
+		}
+		columns[i].Features = append(columns[i].Features, summary)
+	}
+
+	order := func(step string) int {
+		if i := spec.StepIndex(step); i >= 0 {
+			return i
+		}
+		return len(spec.WorkflowSteps)
+	}
+	for i := 1; i < len(columns); i++ {
+		for j := i; j > 0 && order(columns[j].Step) < order(columns[j-1].Step); j-- {
+			columns[j], columns[j-1] = columns[j-1], columns[j]
+		}
+	}
+	return columns
+}
+
+func (s *Server) handleBoard(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.listSummaries()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, BuildBoard(summaries))
+}
+
+func (s *Server) handleGetFeature(w http.ResponseWriter, r *http.Request) {
+	shortName := r.PathValue("name")
+	feature, err := s.findFeature(shortName)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	detail := FeatureDetail{}
+	if detail.FeatureSummary, err = s.summarize(feature); err == nil {
+		detail.Artifacts, err = spec.ListArtifacts(s.targetDir, shortName)
+	}
+	if err == nil {
+		detail.Questions, err = spec.ListQuestions(s.targetDir, shortName)
+	}
+	if err == nil {
+		detail.TaskList, err = spec.ListPlanTasks(s.targetDir, shortName)
+	}
+	if err == nil {
+		detail.Relations, err = spec.GetFeatureRelations(s.targetDir, shortName)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request) {
+	shortName, name := r.PathValue("name"), r.PathValue("file")
+	content, err := spec.ReadArtifact(s.targetDir, shortName, name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	artifact := ArtifactContent{Content: string(content)}
+	artifacts, _ := spec.ListArtifacts(s.targetDir, shortName)
+	for _, a := range artifacts {
+		if a.Name == name {
+			artifact.Artifact = a
+		}
+	}
+
+	markdown := string(content)
+	if !strings.HasSuffix(name, ".md") {
+		markdown = fmt.Sprintf("# %s\n\n```\n%s\n```\n", name, strings.TrimRight(markdown, "\n"))
+	}
+	artifact.HTML = spec.RenderMarkdownHTML(markdown)
+	writeJSON(w, http.StatusOK, artifact)
+}
+
+// handleEvents streams a "change" server-sent event whenever a file in the
+// .spec directory changes
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	changes := s.subscribe()
+	defer s.unsubscribe(changes)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+			fmt.Fprint(w, "event: change\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// subscribe registers a channel notified on every .spec change
+func (s *Server) subscribe() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	s.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, ch)
+}
+
+// notify signals every subscriber without blocking on slow clients
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// watch polls the .spec directory and notifies subscribers when it changes
+func (s *Server) watch(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	last := Fingerprint(s.targetDir)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if current := Fingerprint(s.targetDir); current != last {
+				last = current
+				s.notify()
+			}
+		}
+	}
+}
+
+// Fingerprint hashes the names, sizes and modification times of every file in
+// the .spec directory so changes can be detected by polling
+func Fingerprint(targetDir string) uint64 {
+	h := fnv.New64a()
+	filepath.WalkDir(filepath.Join(targetDir, ".spec"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return h.Sum64()
+}
+
+func (s *Server) handleNewRequirements(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ShortName string `json:"short-name"`
+		Type      string `json:"type"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	var output bytes.Buffer
+	opts := spec.RequirementsOptions{Type: req.Type, Output: &output}
+	files, err := spec.CreateNewRequirementsWithOptions(s.targetDir, req.ShortName, opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, struct {
+		Files    []string `json:"files"`
+		Warnings []string `json:"warnings"`
+	}{files, splitMessages(output.String())})
+}
+
+func (s *Server) handleNewImplementationPlan(w http.ResponseWriter, r *http.Request) {
+	var req struct{}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	files, err := spec.CreateNewImplementationPlan(s.targetDir, r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string][]string{"files": files})
+}
+
+func (s *Server) handleUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Status string `json:"status"`
+		Force  bool   `json:"force"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Status) == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("status cannot be empty"))
+		return
+	}
+	shortName := r.PathValue("name")
+	var output bytes.Buffer
+	opts := spec.StatusUpdateOptions{Force: req.Force, Output: &output}
+	if err := spec.UpdateFeatureStatusWithOptions(s.targetDir, shortName, req.Status, opts); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		ShortName   string   `json:"short-name"`
+		CurrentStep string   `json:"current-step"`
+		Warnings    []string `json:"warnings"`
+	}{shortName, req.Status, splitMessages(output.String())})
+}
+
+// splitMessages splits the messages written while changing a feature into
+// one entry per message; indented lines continue the previous message
+func splitMessages(output string) []string {
+	messages := []string{}
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, " ") && len(messages) > 0:
+			messages[len(messages)-1] += "\n" + line
+		default:
+			messages = append(messages, line)
+		}
+	}
+	return messages
+}
+
+func (s *Server) handleAddQuestion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Phase    string `json:"phase"`
+		Category string `json:"category"`
+		Question string `json:"question"`
+		Default  string `json:"default"`
+		Reason   string `json:"reason"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	entry, err := spec.AddQuestion(s.targetDir, r.PathValue("name"), spec.QuestionInput{
+		Phase:    req.Phase,
+		Category: req.Category,
+		Question: req.Question,
+		Default:  req.Default,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (s *Server) handleAnswerQuestion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Answer     string `json:"answer"`
+		UseDefault bool   `json:"use-default"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	entry, err := spec.AnswerQuestion(s.targetDir, r.PathValue("name"), r.PathValue("id"), req.Answer, req.UseDefault)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) handleLink(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DependsOn []string `json:"depends-on"`
+		Blocks    []string `json:"blocks"`
+		Remove    bool     `json:"remove"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	shortName := r.PathValue("name")
+	var err error
+	if req.Remove {
+		err = spec.UnlinkFeatures(s.targetDir, shortName, req.DependsOn, req.Blocks)
+	} else {
+		err = spec.LinkFeatures(s.targetDir, shortName, req.DependsOn, req.Blocks)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	relations, err := spec.GetFeatureRelations(s.targetDir, shortName)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, relations)
+}
+
+// Check returns an error if the project has not been initialized
+func (s *Server) Check() error {
+	if _, err := os.Stat(filepath.Join(s.targetDir, ".spec")); os.IsNotExist(err) {
+		return fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	}
+	return nil
+}
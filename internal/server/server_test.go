@@ -0,0 +1,179 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/server"
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Server", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-server-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	do := func(s *server.Server, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Host = "localhost:8080"
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("serves the embedded dashboard", func() {
+		s := server.New(tempDir, server.Options{})
+		rec := do(s, "GET", "/", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("/static/app.js"))
+
+		rec = do(s, "GET", "/static/app.js", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("rejects requests for non-local hosts", func() {
+		s := server.New(tempDir, server.Options{})
+		req := httptest.NewRequest("GET", "/api/features", nil)
+		req.Host = "attacker.example.com"
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("groups features by current step on the board", func() {
+		Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Requirements Expert Q&A")).To(Succeed())
+		_, err := spec.CreateNewRequirements(tempDir, "billing")
+		Expect(err).NotTo(HaveOccurred())
+
+		s := server.New(tempDir, server.Options{})
+		rec := do(s, "GET", "/api/board", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var columns []server.BoardColumn
+		Expect(json.Unmarshal(rec.Body.Bytes(), &columns)).To(Succeed())
+		var steps []string
+		for _, column := range columns {
+			steps = append(steps, column.Step)
+		}
+		Expect(steps).To(Equal([]string{"Requirements Gathering", "Requirements Expert Q&A", "Not Started"}))
+	})
+
+	It("returns feature details with tasks and questions", func() {
+		_, err := spec.AddQuestion(tempDir, "user-auth", spec.QuestionInput{Phase: "requirements", Question: "Use SSO?"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		featureDir := filepath.Join(tempDir, ".spec", "001-user-auth")
+		plan := "# Plan\n\n- [x] Step 1: schema\n- [ ] Step 2: handlers\n"
+		Expect(os.WriteFile(filepath.Join(featureDir, "implementation-plan.md"), []byte(plan), 0644)).To(Succeed())
+
+		s := server.New(tempDir, server.Options{})
+		rec := do(s, "GET", "/api/features/user-auth", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var detail server.FeatureDetail
+		Expect(json.Unmarshal(rec.Body.Bytes(), &detail)).To(Succeed())
+		Expect(detail.Tasks).To(Equal(spec.TaskProgress{Done: 1, Total: 2}))
+		Expect(detail.Unanswered).To(Equal(1))
+		Expect(detail.Questions).To(HaveLen(1))
+		Expect(detail.Artifacts[0].Name).To(Equal("requirements.md"))
+
+		rec = do(s, "GET", "/api/features/missing", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("renders artifacts and refuses files outside the feature", func() {
+		s := server.New(tempDir, server.Options{})
+		rec := do(s, "GET", "/api/features/user-auth/artifacts/requirements.md", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var artifact server.ArtifactContent
+		Expect(json.Unmarshal(rec.Body.Bytes(), &artifact)).To(Succeed())
+		Expect(artifact.Kind).To(Equal(spec.ArtifactRequirements))
+		Expect(artifact.HTML).To(ContainSubstring("<h1"))
+
+		rec = do(s, "GET", "/api/features/user-auth/artifacts/.spec-status.json", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+		rec = do(s, "GET", "/api/features/user-auth/artifacts/..%2F..%2Fconfig.json", "")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	Context("write endpoints", func() {
+		It("are not registered unless enabled", func() {
+			s := server.New(tempDir, server.Options{})
+			rec := do(s, "POST", "/api/features/user-auth/status", `{"status": "Requirements Complete"}`)
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("update the feature status", func() {
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Requirements Gathering")).To(Succeed())
+			s := server.New(tempDir, server.Options{EnableWrites: true})
+			rec := do(s, "POST", "/api/features/user-auth/status", `{"status": "Requirements Complete", "force": true}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var response struct {
+				Warnings []string `json:"warnings"`
+			}
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Warnings).To(ConsistOf(And(
+				HavePrefix("Warning: feature user-auth is leaving 'Requirements Gathering' with incomplete questions:\n  "),
+				ContainSubstring("discovery"),
+			)))
+
+			features, err := spec.ListFeatures(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(features).To(ContainElement(HaveField("Status", "Requirements Complete")))
+		})
+
+		It("require a JSON content type", func() {
+			s := server.New(tempDir, server.Options{EnableWrites: true})
+			req := httptest.NewRequest("POST", "/api/features", strings.NewReader("short-name=billing"))
+			req.Host = "127.0.0.1:8080"
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusUnsupportedMediaType))
+		})
+
+		It("add and answer questions", func() {
+			s := server.New(tempDir, server.Options{EnableWrites: true})
+			rec := do(s, "POST", "/api/features/user-auth/questions", `{"phase": "requirements", "question": "Use SSO?", "default": "no"}`)
+			Expect(rec.Code).To(Equal(http.StatusCreated))
+
+			rec = do(s, "POST", "/api/features/user-auth/questions/Q1/answer", `{"use-default": true}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			questions, err := spec.ListQuestions(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(questions[0].Answer).To(Equal("No (default used)"))
+		})
+	})
+
+	It("changes its fingerprint when spec files change", func() {
+		before := server.Fingerprint(tempDir)
+		_, err := spec.CreateNewRequirements(tempDir, "billing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Fingerprint(tempDir)).NotTo(Equal(before))
+	})
+})
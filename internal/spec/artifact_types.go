@@ -0,0 +1,344 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in artifact types
+const (
+	ArtifactTypeRequirements       = "requirements"
+	ArtifactTypeImplementationPlan = "implementation-plan"
+)
+
+var artifactTypeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ArtifactTypeConfig declares a kind of feature document created with
+// "feature new". The document is written to <name>.md in the feature
+// directory.
+type ArtifactTypeConfig struct {
+	// Title names the document in messages and its context file; defaults to
+	// the type name in title case
+	Title string `json:"title,omitempty"`
+	// Template is the template path below .spec/templates; defaults to <name>.md
+	Template string `json:"template,omitempty"`
+	// Context creates a context-<name>.md file for the document's Q&A
+	Context bool `json:"context"`
+	// Requires lists the artifact types that must exist before this one
+	Requires []string `json:"requires,omitempty"`
+	// Status is set as the feature's status when the document is created
+	Status string `json:"status,omitempty"`
+}
+
+// ArtifactType is a built-in or configured artifact type
+type ArtifactType struct {
+	Name string `json:"name"`
+	ArtifactTypeConfig
+	BuiltIn bool `json:"built-in"`
+}
+
+// FileName returns the name of the document created for the type
+func (t ArtifactType) FileName() string {
+	return t.Name + ".md"
+}
+
+// builtInArtifactTypes are the types available without configuration
+var builtInArtifactTypes = []ArtifactType{
+	{
+		Name:               ArtifactTypeRequirements,
+		ArtifactTypeConfig: ArtifactTypeConfig{Title: "Requirements", Template: "requirements.md", Context: true},
+		BuiltIn:            true,
+	},
+	{
+		Name: ArtifactTypeImplementationPlan,
+		ArtifactTypeConfig: ArtifactTypeConfig{
+			Title:    "Implementation Plan",
+			Template: "implementation-plan.md",
+			Context:  true,
+			Requires: []string{ArtifactTypeRequirements},
+		},
+		BuiltIn: true,
+	},
+}
+
+// artifactTypes returns the built-in types followed by the configured ones in
+// name order. Configured types named like a built-in one override its
+// settings.
+func artifactTypes(config Config) []ArtifactType {
+	var types []ArtifactType
+	for _, builtIn := range builtInArtifactTypes {
+		if configured, ok := config.Artifacts[builtIn.Name]; ok {
+			builtIn.ArtifactTypeConfig = mergeArtifactType(builtIn.ArtifactTypeConfig, configured)
+		}
+		types = append(types, builtIn)
+	}
+
+	var names []string
+	for name := range config.Artifacts {
+		if !isBuiltInArtifactType(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := ArtifactType{Name: name, ArtifactTypeConfig: config.Artifacts[name]}
+		if t.Title == "" {
+			t.Title = artifactTitle(name)
+		}
+		if t.Template == "" {
+			t.Template = t.FileName()
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+// mergeArtifactType applies the configured settings of a built-in type.
+// Built-in types always have a context file.
+func mergeArtifactType(builtIn, configured ArtifactTypeConfig) ArtifactTypeConfig {
+	if configured.Title != "" {
+		builtIn.Title = configured.Title
+	}
+	if configured.Template != "" {
+		builtIn.Template = configured.Template
+	}
+	if configured.Requires != nil {
+		builtIn.Requires = configured.Requires
+	}
+	builtIn.Status = configured.Status
+	return builtIn
+}
+
+// artifactDocuments returns the file names of the documents of configured
+// artifact types, which are not technical specs
+func artifactDocuments(config Config) map[string]bool {
+	documents := make(map[string]bool)
+	for _, t := range artifactTypes(config) {
+		if !t.BuiltIn {
+			documents[t.FileName()] = true
+		}
+	}
+	return documents
+}
+
+// isBuiltInArtifactType reports whether a type name is built in
+func isBuiltInArtifactType(name string) bool {
+	for _, t := range builtInArtifactTypes {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactTitle turns a type name such as "rollout-plan" into "Rollout Plan"
+func artifactTitle(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// isReservedArtifactName reports whether a type name would clash with other
+// files of a feature: context files, the import source and technical specs
+func isReservedArtifactName(name string) bool {
+	fileName := name + ".md"
+	if strings.HasPrefix(name, "context-") || fileName == SourceFileName {
+		return true
+	}
+	for _, t := range TechSpecTypes {
+		if name == t.Name || fileName == t.DefaultName {
+			return true
+		}
+	}
+	return false
+}
+
+// validateArtifactTypes checks the artifact types declared in config.json
+func validateArtifactTypes(config Config) error {
+	known := make(map[string]bool)
+	for _, t := range artifactTypes(config) {
+		known[t.Name] = true
+	}
+	for name, t := range config.Artifacts {
+		if !artifactTypeNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid artifact type %q: use lowercase letters, numbers and hyphens", name)
+		}
+		if isReservedArtifactName(name) {
+			return fmt.Errorf("artifact type %q is reserved", name)
+		}
+		if t.Template != "" {
+			if _, err := templateFileName(t.Template); err != nil {
+				return fmt.Errorf("artifact type %s: %w", name, err)
+			}
+		}
+		if name == ArtifactTypeRequirements && len(t.Requires) > 0 {
+			return fmt.Errorf("artifact type %s cannot require other artifacts", name)
+		}
+		for _, required := range t.Requires {
+			if !known[required] {
+				return fmt.Errorf("artifact type %s requires unknown artifact type %q", name, required)
+			}
+			if required == name {
+				return fmt.Errorf("artifact type %s cannot require itself", name)
+			}
+		}
+	}
+	return nil
+}
+
+// ArtifactTypes returns the built-in artifact types and those declared under
+// "artifacts" in .spec/config.json
+func ArtifactTypes(targetDir string) ([]ArtifactType, error) {
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	return artifactTypes(config), nil
+}
+
+// findArtifactType returns an artifact type by name
+func findArtifactType(config Config, name string) (ArtifactType, error) {
+	types := artifactTypes(config)
+	var names []string
+	for _, t := range types {
+		if t.Name == name {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return ArtifactType{}, fmt.Errorf("unknown artifact type %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ArtifactOptions controls how a feature document is created
+type ArtifactOptions struct {
+	// Type selects the template set of a new feature; only used for
+	// requirements, later documents use the set recorded for the feature
+	Type string
+	// Output receives warnings and progress messages; defaults to os.Stdout
+	Output io.Writer
+}
+
+// output returns the writer for messages, defaulting to os.Stdout
+func (o ArtifactOptions) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
+}
+
+// CreateArtifact creates a feature document of the given artifact type from
+// its template. Requirements create the feature; other types are added to an
+// existing feature once their required artifacts exist, using the feature's
+// template set, and may move the feature to a new status.
+func CreateArtifact(targetDir, artifactType, shortName string, opts ArtifactOptions) ([]string, error) {
+	if err := ValidateFeatureName(shortName); err != nil {
+		return nil, err
+	}
+	specDir := filepath.Join(targetDir, ".spec")
+	if _, err := os.Stat(specDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateArtifactTypes(config); err != nil {
+		return nil, err
+	}
+	t, err := findArtifactType(config, artifactType)
+	if err != nil {
+		return nil, err
+	}
+	if t.Name == ArtifactTypeRequirements {
+		return createRequirements(targetDir, shortName, t, opts)
+	}
+	if opts.Type != "" {
+		return nil, fmt.Errorf("the template set can only be chosen when creating requirements")
+	}
+
+	featureDir, err := findFeatureDirectory(specDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	artifactPath := filepath.Join(featureDir, t.FileName())
+	if _, err := os.Stat(artifactPath); err == nil {
+		return nil, fmt.Errorf("%s already exists for feature %s", strings.ToLower(t.Title), shortName)
+	}
+	if _, err := os.Stat(filepath.Join(featureDir, "context-"+t.FileName())); t.Context && err == nil {
+		return nil, fmt.Errorf("context-%s already exists for feature %s", t.FileName(), shortName)
+	}
+	for _, required := range t.Requires {
+		requiredType, err := findArtifactType(config, required)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(featureDir, requiredType.FileName())); err != nil {
+			return nil, fmt.Errorf("%s requires %s; run 'specware feature new %s %s' first",
+				t.FileName(), requiredType.FileName(), requiredType.Name, shortName)
+		}
+	}
+
+	// Use the template set the requirements were created from
+	statusData, err := readFeatureStatus(featureDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	content, err := getSetTemplate(targetDir, statusData.Type, t.Template)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template %s for %s not found; add it to .spec/templates/", t.Template, t.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s template %s: %w", strings.ToLower(t.Title), t.Template, err)
+	}
+	var contextContent string
+	if t.Context {
+		contextTemplate, err := getSetTemplate(targetDir, statusData.Type, "context.md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get context template: %w", err)
+		}
+		contextContent = strings.Replace(string(contextTemplate), "[Feature Name]", t.Title, 1)
+	}
+
+	// Write the documents, then move to the configured status; if a
+	// blocking transition check fails, the documents are removed again
+	var createdFiles, createdPaths []string
+	removeCreated := func() {
+		for _, path := range createdPaths {
+			os.Remove(path)
+		}
+	}
+	featureName := filepath.Base(featureDir)
+	if err := os.WriteFile(artifactPath, content, 0644); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", t.FileName(), err)
+	}
+	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, t.FileName()))
+	createdPaths = append(createdPaths, artifactPath)
+	if t.Context {
+		contextName := "context-" + t.FileName()
+		contextPath := filepath.Join(featureDir, contextName)
+		if err := os.WriteFile(contextPath, []byte(contextContent), 0644); err != nil {
+			removeCreated()
+			return nil, fmt.Errorf("failed to create %s: %w", contextName, err)
+		}
+		createdFiles = append(createdFiles, filepath.Join(".spec", featureName, contextName))
+		createdPaths = append(createdPaths, contextPath)
+	}
+	if t.Status != "" {
+		if err := UpdateFeatureStatusWithOptions(targetDir, shortName, t.Status, StatusUpdateOptions{Output: opts.output()}); err != nil {
+			removeCreated()
+			return nil, err
+		}
+	}
+	return createdFiles, nil
+}
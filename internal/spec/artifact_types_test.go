@@ -0,0 +1,196 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Artifact types", func() {
+	var (
+		tempDir    string
+		featureDir string
+	)
+
+	writeArtifacts := func(artifacts map[string]spec.ArtifactTypeConfig) {
+		config, err := spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		config.Artifacts = artifacts
+		Expect(spec.SaveConfig(tempDir, config)).To(Succeed())
+	}
+
+	writeTemplate := func(name, content string) {
+		path := filepath.Join(tempDir, ".spec", "templates", name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-artifact-types-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		featureDir = filepath.Join(tempDir, ".spec", "001-user-auth")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("creates the built-in types through the generic command", func() {
+		files, err := spec.CreateArtifact(tempDir, "requirements", "user-auth", spec.ArtifactOptions{Type: "spike"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(ContainElement(filepath.Join(".spec", "001-user-auth", "context-requirements.md")))
+		content, err := os.ReadFile(filepath.Join(featureDir, "requirements.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("# Spike"))
+
+		_, err = spec.CreateArtifact(tempDir, "implementation-plan", "user-auth", spec.ArtifactOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		content, err = os.ReadFile(filepath.Join(featureDir, "implementation-plan.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("# Investigation Plan"))
+
+		_, err = spec.CreateArtifact(tempDir, "implementation-plan", "user-auth", spec.ArtifactOptions{Type: "bugfix"})
+		Expect(err).To(MatchError(ContainSubstring("only be chosen when creating requirements")))
+	})
+
+	It("creates configured types with context, prerequisites and status", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{
+			"threat-model": {Context: true, Requires: []string{"implementation-plan"}, Status: "Threat Modeling"},
+		})
+		writeTemplate("threat-model.md", "# Threat Model: [Feature Name]\n")
+		_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.CreateArtifact(tempDir, "threat-model", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring("threat-model.md requires implementation-plan.md")))
+		Expect(filepath.Join(featureDir, "threat-model.md")).NotTo(BeAnExistingFile())
+
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		files, err := spec.CreateArtifact(tempDir, "threat-model", "user-auth", spec.ArtifactOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal([]string{
+			filepath.Join(".spec", "001-user-auth", "threat-model.md"),
+			filepath.Join(".spec", "001-user-auth", "context-threat-model.md"),
+		}))
+
+		context, err := os.ReadFile(filepath.Join(featureDir, "context-threat-model.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(context)).To(ContainSubstring("# Context: Threat Model"))
+
+		data, err := os.ReadFile(filepath.Join(featureDir, ".spec-status.json"))
+		Expect(err).NotTo(HaveOccurred())
+		var status spec.FeatureStatus
+		Expect(json.Unmarshal(data, &status)).To(Succeed())
+		Expect(status.CurrentStep).To(Equal("Threat Modeling"))
+
+		_, err = spec.CreateArtifact(tempDir, "threat-model", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring("threat model already exists")))
+	})
+
+	It("removes the documents again when the status change is blocked", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"threat-model": {Context: true, Status: "Threat Modeling"}})
+		writeTemplate("threat-model.md", "# Threat Model: [Feature Name]\n")
+		_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Requirements Gathering")).To(Succeed())
+		_, err = spec.SetConfigValue(tempDir, "validation.enforce-question-counts", "true")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.CreateArtifact(tempDir, "threat-model", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring("cannot leave 'Requirements Gathering'")))
+		Expect(filepath.Join(featureDir, "threat-model.md")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(featureDir, "context-threat-model.md")).NotTo(BeAnExistingFile())
+	})
+
+	It("does not treat documents of configured types as technical specs", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"threat-model": {}})
+		writeTemplate("threat-model.md", "# Threat Model\n\nSpoofing of session tokens\n")
+		_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateArtifact(tempDir, "threat-model", "user-auth", spec.ArtifactOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		specs, err := spec.ListTechSpecs(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(specs).To(BeEmpty())
+
+		results, err := spec.SearchSpecs(tempDir, "spoofing", spec.SearchOptions{In: spec.ArtifactSpec})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+		results, err = spec.SearchSpecs(tempDir, "spoofing", spec.SearchOptions{In: spec.ArtifactDocument})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Sections[0].File).To(Equal("threat-model.md"))
+
+		artifacts, err := spec.ListArtifacts(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).To(ContainElement(spec.Artifact{Name: "threat-model.md", Kind: spec.ArtifactDocument}))
+
+		doc, err := spec.BuildFeatureDocument(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Parts[len(doc.Parts)-1].Title).To(Equal("Threat Model"))
+	})
+
+	It("uses the feature's template set and falls back to the top-level template", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"adr": {Title: "ADR"}})
+		writeTemplate("adr.md", "# ADR\n")
+		writeTemplate("bugfix/adr.md", "# Bugfix ADR\n")
+		_, err := spec.CreateNewRequirementsWithOptions(tempDir, "user-auth", spec.RequirementsOptions{Type: "bugfix"})
+		Expect(err).NotTo(HaveOccurred())
+
+		files, err := spec.CreateArtifact(tempDir, "adr", "user-auth", spec.ArtifactOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		content, err := os.ReadFile(filepath.Join(featureDir, "adr.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Bugfix ADR\n"))
+
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"rollout-plan": {}})
+		_, err = spec.CreateArtifact(tempDir, "rollout-plan", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring("template rollout-plan.md for rollout-plan not found")))
+	})
+
+	It("lists built-in and configured types", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{
+			"rollout-plan":        {Requires: []string{"implementation-plan"}},
+			"implementation-plan": {Status: "Implementation Planning"},
+		})
+		types, err := spec.ArtifactTypes(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(types).To(HaveLen(3))
+		Expect(types[0].Name).To(Equal("requirements"))
+		Expect(types[1].Status).To(Equal("Implementation Planning"))
+		Expect(types[1].Context).To(BeTrue())
+		Expect(types[1].Requires).To(Equal([]string{"requirements"}))
+		Expect(types[2].Title).To(Equal("Rollout Plan"))
+		Expect(types[2].Template).To(Equal("rollout-plan.md"))
+
+		_, err = spec.CreateArtifact(tempDir, "runbook", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring(`unknown artifact type "runbook"`)))
+	})
+
+	It("rejects invalid declarations", func() {
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"runbook": {Requires: []string{"rollout"}}})
+		_, err := spec.SetConfigValue(tempDir, "review.required_approvals", "1")
+		Expect(err).To(MatchError(ContainSubstring(`requires unknown artifact type "rollout"`)))
+
+		for _, name := range []string{"context-notes", "source", "erd", "mermaid", "diagram", "cli-reference"} {
+			writeArtifacts(map[string]spec.ArtifactTypeConfig{name: {}})
+			_, err = spec.SetConfigValue(tempDir, "review.required_approvals", "1")
+			Expect(err).To(MatchError(ContainSubstring("is reserved")), name)
+		}
+
+		writeArtifacts(map[string]spec.ArtifactTypeConfig{"runbook": {Template: "../../x"}})
+		_, err = spec.CreateArtifact(tempDir, "runbook", "user-auth", spec.ArtifactOptions{})
+		Expect(err).To(MatchError(ContainSubstring(`artifact type runbook: invalid template name "../../x"`)))
+	})
+})
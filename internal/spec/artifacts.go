@@ -0,0 +1,90 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Artifact is a specification file within a feature directory
+type Artifact struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// artifactOrder controls the display order of artifact kinds
+var artifactOrder = map[string]int{
+	ArtifactRequirements: 0,
+	ArtifactContext:      1,
+	ArtifactSpec:         2,
+	ArtifactPlan:         3,
+	ArtifactDocument:     4,
+}
+
+// ListArtifacts returns the specification artifacts of a feature, ordered by
+// kind and then by name
+func ListArtifacts(targetDir, shortName string) ([]Artifact, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature directory: %w", err)
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	documents := artifactDocuments(config)
+
+	var artifacts []Artifact
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if kind := artifactKind(entry.Name(), documents); kind != "" {
+			artifacts = append(artifacts, Artifact{Name: entry.Name(), Kind: kind})
+		}
+	}
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		if artifactOrder[artifacts[i].Kind] != artifactOrder[artifacts[j].Kind] {
+			return artifactOrder[artifacts[i].Kind] < artifactOrder[artifacts[j].Kind]
+		}
+		return artifacts[i].Name < artifacts[j].Name
+	})
+	return artifacts, nil
+}
+
+// ReadArtifact returns the content of a single artifact of a feature. Only
+// files directly inside the feature directory can be read.
+func ReadArtifact(targetDir, shortName, name string) ([]byte, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != filepath.Base(name) || artifactKind(name, nil) == "" {
+		return nil, fmt.Errorf("%s is not an artifact of feature %s", name, shortName)
+	}
+
+	content, err := os.ReadFile(filepath.Join(featureDir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s not found for feature %s", name, shortName)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// GetFeatureRelations returns the relations recorded in a feature's relations.json
+func GetFeatureRelations(targetDir, shortName string) (FeatureRelations, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return FeatureRelations{}, err
+	}
+	return readFeatureRelations(featureDir)
+}
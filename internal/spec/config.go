@@ -0,0 +1,383 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Config represents the workflow configuration stored in .spec/config.json
+type Config struct {
+	Requirements   RequirementsConfig   `json:"requirements"`
+	Implementation ImplementationConfig `json:"implementation"`
+	Validation     ValidationConfig     `json:"validation"`
+	Git            GitConfig            `json:"git"`
+	Issues         IssuesConfig         `json:"issues"`
+	Verify         VerifyConfig         `json:"verify"`
+	Review         ReviewConfig         `json:"review"`
+	// Artifacts declares feature document types created with "feature new"
+	Artifacts map[string]ArtifactTypeConfig `json:"artifacts,omitempty"`
+	// Packs pins the version of each template pack the project uses
+	Packs map[string]string `json:"packs,omitempty"`
+}
+
+// RequirementsConfig holds question counts for the requirements phase
+type RequirementsConfig struct {
+	DiscoveryQuestions int `json:"discovery_questions"`
+	ExpertQuestions    int `json:"expert_questions"`
+}
+
+// ImplementationConfig holds question counts for the implementation planning phase
+type ImplementationConfig struct {
+	PlanQuestions    int `json:"plan_questions"`
+	TestingQuestions int `json:"testing_questions"`
+}
+
+// ValidationConfig controls which checks block status transitions
+type ValidationConfig struct {
+	EnforceQuestionCounts bool `json:"enforce_question_counts"`
+	// EnforceValidSpecs refuses to enter implementation planning while the
+	// feature's technical specs fail validate-specs
+	EnforceValidSpecs bool `json:"enforce_valid_specs"`
+}
+
+// Git branch creation points for GitConfig.BranchOn
+const (
+	BranchOnNever           = "never"
+	BranchOnNewRequirements = "new-requirements"
+	BranchOnImplementation  = "implementation"
+)
+
+// GitConfig controls the optional git integration
+type GitConfig struct {
+	BranchOn     string `json:"branch_on"`
+	BranchPrefix string `json:"branch_prefix"`
+	TrackSpecs   bool   `json:"track_specs"`
+	IgnoreFile   string `json:"ignore_file"`
+	// RequireSpecTrailer makes the commit-msg hook require a "Spec:" trailer
+	// for code commits on a feature branch
+	RequireSpecTrailer bool `json:"require_spec_trailer"`
+}
+
+// IssuesConfig controls issues generated by export-issues
+type IssuesConfig struct {
+	// Labels are applied to every exported issue
+	Labels []string `json:"labels"`
+}
+
+// VerifyConfig controls how verify runs a project's tests
+type VerifyConfig struct {
+	// TestCommand is run by "verify --run"; {ac} and {feature} are replaced
+	// by the criterion ID and feature name
+	TestCommand string `json:"test_command"`
+}
+
+// ReviewConfig controls the review subsystem
+type ReviewConfig struct {
+	// RequiredApprovals is the number of approvals the requirements need
+	// before implementation planning, and the plan before implementation.
+	// Zero disables the check.
+	RequiredApprovals int `json:"required_approvals"`
+}
+
+// DefaultConfig returns the configuration used when .spec/config.json is
+// missing or omits a value
+func DefaultConfig() Config {
+	return Config{
+		Requirements: RequirementsConfig{
+			DiscoveryQuestions: 5,
+			ExpertQuestions:    4,
+		},
+		Implementation: ImplementationConfig{
+			PlanQuestions:    5,
+			TestingQuestions: 2,
+		},
+		Git: GitConfig{
+			BranchOn:     BranchOnNever,
+			BranchPrefix: "feature/",
+			IgnoreFile:   IgnoreFileGitignore,
+		},
+		Issues: IssuesConfig{
+			Labels: []string{"specware"},
+		},
+	}
+}
+
+// LoadConfig reads .spec/config.json, falling back to defaults for missing values
+func LoadConfig(targetDir string) (Config, error) {
+	config := DefaultConfig()
+	data, err := os.ReadFile(filepath.Join(targetDir, ".spec", "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("failed to read config.json: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse config.json: %w", err)
+	}
+	return config, nil
+}
+
+// SaveConfig writes the configuration to .spec/config.json
+func SaveConfig(targetDir string, config Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, ".spec", "config.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config.json: %w", err)
+	}
+	return nil
+}
+
+// configPath splits a dotted config key such as "git.track-specs" or
+// "artifacts.threat-model.context" into its JSON field names and returns the
+// type of the setting. Hyphens are accepted in place of underscores in field
+// names, while map keys such as artifact type names are kept as given.
+func configPath(key string) ([]string, reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	var path []string
+	for _, segment := range strings.Split(strings.TrimSpace(key), ".") {
+		switch {
+		case segment == "":
+			return nil, nil, fmt.Errorf("unknown config key %q", key)
+		case t.Kind() == reflect.Struct:
+			name := strings.ReplaceAll(segment, "-", "_")
+			field, ok := configStructField(t, name)
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown config key %q", key)
+			}
+			path = append(path, name)
+			t = field.Type
+		case t.Kind() == reflect.Map:
+			path = append(path, segment)
+			t = t.Elem()
+		default:
+			return nil, nil, fmt.Errorf("unknown config key %q", key)
+		}
+	}
+	return path, t, nil
+}
+
+// configStructField returns the field of a config struct with a JSON name
+func configStructField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// lookupConfigValue returns the value at a path in a decoded config document
+func lookupConfigValue(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, field := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[field]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// readConfigDocument reads .spec/config.json as a generic JSON document, so
+// the settings it contains can be told apart from defaults
+func readConfigDocument(targetDir string) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	data, err := os.ReadFile(filepath.Join(targetDir, ".spec", "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("failed to read config.json: %w", err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config.json: %w", err)
+	}
+	return doc, nil
+}
+
+// writeConfigDocument writes a generic JSON document to .spec/config.json,
+// keeping keys the Config struct does not know
+func writeConfigDocument(targetDir string, doc map[string]interface{}) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, ".spec", "config.json"), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config.json: %w", err)
+	}
+	return nil
+}
+
+// decodeConfigDocument decodes a generic JSON document as a config, falling
+// back to defaults for missing values
+func decodeConfigDocument(doc map[string]interface{}) (Config, error) {
+	config := DefaultConfig()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return config, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return config, nil
+}
+
+// configDocument decodes a config as a generic JSON document
+func configDocument(config Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return doc, nil
+}
+
+// defaultConfigDocument returns the built-in defaults as a generic JSON document
+func defaultConfigDocument() map[string]interface{} {
+	doc, _ := configDocument(DefaultConfig())
+	return doc
+}
+
+// GetConfigValue returns the value of a dotted config key, e.g.
+// "requirements.discovery-questions", formatted for display
+func GetConfigValue(targetDir, key string) (string, error) {
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return "", err
+	}
+	doc, err := configDocument(config)
+	if err != nil {
+		return "", err
+	}
+	path, _, err := configPath(key)
+	if err != nil {
+		return "", err
+	}
+	value, ok := lookupConfigValue(doc, path)
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+	if _, isObject := value.(map[string]interface{}); isObject {
+		data, _ := json.MarshalIndent(value, "", "  ")
+		return string(data), nil
+	}
+	if list, isList := value.([]interface{}); isList {
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ","), nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// SetConfigValue sets a dotted config key, parsing the value according to the
+// type of the setting, and saves .spec/config.json. Only that key is changed
+// in the file; other settings, including keys specware does not know, are
+// kept as they are. Changing git.track_specs or git.ignore_file rewrites the
+// ignore rules to match.
+func SetConfigValue(targetDir, key, value string) (Config, error) {
+	if _, err := os.Stat(filepath.Join(targetDir, ".spec")); os.IsNotExist(err) {
+		return Config{}, fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	}
+	doc, err := readConfigDocument(targetDir)
+	if err != nil {
+		return Config{}, err
+	}
+	path, t, err := configPath(key)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var parsed interface{}
+	switch {
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s expects true or false, got %q", key, value)
+		}
+		parsed = b
+	case t.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("%s expects a non-negative number, got %q", key, value)
+		}
+		parsed = n
+	case t.Kind() == reflect.String:
+		parsed = value
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		// Lists are given comma-separated, e.g. "specware,backend"
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		parsed = list
+	default:
+		return Config{}, fmt.Errorf("%s is a section; set one of its keys instead", key)
+	}
+
+	// Create the sections leading to the key, e.g. for a new artifact type
+	object := doc
+	for _, field := range path[:len(path)-1] {
+		child, ok := object[field].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			object[field] = child
+		}
+		object = child
+	}
+	object[path[len(path)-1]] = parsed
+
+	updated, err := decodeConfigDocument(doc)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := validateConfig(updated); err != nil {
+		return Config{}, err
+	}
+
+	if path[0] == "git" && (path[len(path)-1] == "track_specs" || path[len(path)-1] == "ignore_file") {
+		if _, err := ApplyGitIgnore(targetDir, updated.Git); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := writeConfigDocument(targetDir, doc); err != nil {
+		return Config{}, err
+	}
+	return updated, nil
+}
+
+// validateConfig checks settings that only accept a fixed set of values
+func validateConfig(config Config) error {
+	if err := validateArtifactTypes(config); err != nil {
+		return err
+	}
+	switch config.Git.BranchOn {
+	case BranchOnNever, BranchOnNewRequirements, BranchOnImplementation:
+	default:
+		return fmt.Errorf("git.branch_on must be %s, %s or %s", BranchOnNever, BranchOnNewRequirements, BranchOnImplementation)
+	}
+	switch config.Git.IgnoreFile {
+	case IgnoreFileGitignore, IgnoreFileExclude:
+	default:
+		return fmt.Errorf("git.ignore_file must be %s or %s", IgnoreFileGitignore, IgnoreFileExclude)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package spec
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// docxContentTypes, docxRels, docxDocumentRels and docxStyles are the fixed
+// parts of the minimal WordprocessingML package written by RenderFeatureDOCX
+const (
+	docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`
+
+	docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+	docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri"/><w:sz w:val="22"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:before="2400" w:after="240"/></w:pPr><w:rPr><w:b/><w:sz w:val="56"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="30"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading4"><w:name w:val="heading 4"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="3"/></w:pPr><w:rPr><w:b/><w:sz w:val="24"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading5"><w:name w:val="heading 5"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="4"/></w:pPr><w:rPr><w:b/><w:i/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading6"><w:name w:val="heading 6"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="5"/></w:pPr><w:rPr><w:i/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0"/><w:shd w:val="clear" w:color="auto" w:fill="F6F8FA"/></w:pPr><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas"/><w:sz w:val="18"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:pPr><w:ind w:left="720"/></w:pPr><w:rPr><w:i/><w:color w:val="59636E"/></w:rPr></w:style>
+</w:styles>`
+)
+
+// docxWriter accumulates WordprocessingML body content
+type docxWriter struct {
+	body strings.Builder
+}
+
+// escape escapes text for inclusion in XML
+func (w *docxWriter) escape(text string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// run writes a single text run with the given formatting
+func (w *docxWriter) run(span mdSpan) string {
+	var props strings.Builder
+	if span.Bold {
+		props.WriteString("<w:b/>")
+	}
+	if span.Italic {
+		props.WriteString("<w:i/>")
+	}
+	if span.Code {
+		props.WriteString(`<w:rFonts w:ascii="Consolas" w:hAnsi="Consolas"/>`)
+	}
+	if span.Link != "" {
+		props.WriteString(`<w:color w:val="0969DA"/><w:u w:val="single"/>`)
+	}
+	rPr := ""
+	if props.Len() > 0 {
+		rPr = "<w:rPr>" + props.String() + "</w:rPr>"
+	}
+	return fmt.Sprintf(`<w:r>%s<w:t xml:space="preserve">%s</w:t></w:r>`, rPr, w.escape(span.Text))
+}
+
+// paragraph writes a paragraph of inline markdown with optional style and indent
+func (w *docxWriter) paragraph(style, prefix, text string, indent int) {
+	w.body.WriteString("<w:p>")
+	if style != "" || indent > 0 {
+		w.body.WriteString("<w:pPr>")
+		if style != "" {
+			fmt.Fprintf(&w.body, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if indent > 0 {
+			fmt.Fprintf(&w.body, `<w:ind w:left="%d" w:hanging="360"/>`, indent)
+		}
+		w.body.WriteString("</w:pPr>")
+	}
+	if prefix != "" {
+		w.body.WriteString(w.run(mdSpan{Text: prefix}))
+	}
+	for _, span := range parseInline(text) {
+		w.body.WriteString(w.run(span))
+	}
+	w.body.WriteString("</w:p>")
+}
+
+// plain writes a paragraph of literal text in the given style
+func (w *docxWriter) plain(style, text string) {
+	fmt.Fprintf(&w.body, `<w:p><w:pPr><w:pStyle w:val="%s"/></w:pPr>%s</w:p>`, style, w.run(mdSpan{Text: text}))
+}
+
+// pageBreak starts a new page
+func (w *docxWriter) pageBreak() {
+	w.body.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+}
+
+// blocks writes parsed markdown blocks
+func (w *docxWriter) blocks(blocks []mdBlock) {
+	for _, block := range blocks {
+		switch block.Kind {
+		case blockHeading:
+			w.paragraph(fmt.Sprintf("Heading%d", block.Level), "", block.Text, 0)
+		case blockParagraph:
+			w.paragraph("", "", block.Text, 0)
+		case blockCode:
+			for _, line := range strings.Split(block.Text, "\n") {
+				w.plain("Code", line)
+			}
+		case blockQuote:
+			for _, child := range block.Children {
+				if child.Kind == blockParagraph {
+					w.paragraph("Quote", "", child.Text, 0)
+				} else {
+					w.blocks([]mdBlock{child})
+				}
+			}
+		case blockRule:
+			w.body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="D0D7DE"/></w:pBdr></w:pPr></w:p>`)
+		case blockList:
+			counters := make(map[int]int)
+			for _, item := range block.Items {
+				prefix := "• "
+				if item.Ordered {
+					counters[item.Indent]++
+					prefix = fmt.Sprintf("%d. ", counters[item.Indent])
+				}
+				if item.Task {
+					if item.Checked {
+						prefix += "☑ "
+					} else {
+						prefix += "☐ "
+					}
+				}
+				w.paragraph("", prefix, item.Text, 360*(item.Indent+1))
+			}
+		case blockTable:
+			w.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/><w:tblBorders>`)
+			for _, side := range []string{"top", "left", "bottom", "right", "insideH", "insideV"} {
+				fmt.Fprintf(&w.body, `<w:%s w:val="single" w:sz="4" w:space="0" w:color="D0D7DE"/>`, side)
+			}
+			w.body.WriteString(`</w:tblBorders></w:tblPr>`)
+			rows := append([][]string{block.Header}, block.Rows...)
+			for r, row := range rows {
+				w.body.WriteString("<w:tr>")
+				for _, cell := range row {
+					w.body.WriteString("<w:tc>")
+					if r == 0 {
+						w.paragraph("", "", "**"+cell+"**", 0)
+					} else {
+						w.paragraph("", "", cell, 0)
+					}
+					w.body.WriteString("</w:tc>")
+				}
+				w.body.WriteString("</w:tr>")
+			}
+			w.body.WriteString("</w:tbl>")
+		}
+	}
+}
+
+// RenderFeatureDOCX renders the document as a Word (.docx) file
+func RenderFeatureDOCX(doc *FeatureDocument) ([]byte, error) {
+	w := &docxWriter{}
+
+	w.plain("Title", doc.Title)
+	for _, field := range doc.metadata() {
+		w.paragraph("", "", fmt.Sprintf("**%s:** %s", field[0], field[1]), 0)
+	}
+	w.pageBreak()
+
+	w.plain("Heading1", "Table of Contents")
+	for _, entry := range doc.tableOfContents() {
+		w.paragraph("", "", entry.Title, 360*entry.Level)
+	}
+
+	for _, part := range doc.Parts {
+		w.pageBreak()
+		w.blocks(parseMarkdownBlocks(part.markdown()))
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		w.body.String() +
+		`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440"/></w:sectPr></w:body></w:document>`
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/document.xml", document},
+	}
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+		if _, err := f.Write([]byte(file.content)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize docx archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,317 @@
+package spec
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Supported export formats
+const (
+	ExportMarkdown = "md"
+	ExportHTML     = "html"
+	ExportDOCX     = "docx"
+)
+
+// ExportPart is one source artifact included in an exported document
+type ExportPart struct {
+	Title    string
+	File     string
+	Content  string
+	Markdown bool
+}
+
+// FeatureDocument is a feature's specification stitched into a single document
+type FeatureDocument struct {
+	Feature   Feature
+	Title     string
+	Generated time.Time
+	Parts     []ExportPart
+}
+
+// tocEntry is a table of contents entry pointing at a heading anchor
+type tocEntry struct {
+	Level  int
+	Title  string
+	Anchor string
+}
+
+// specLanguages maps technical specification file extensions to code block languages
+var specLanguages = map[string]string{
+	".yaml":    "yaml",
+	".yml":     "yaml",
+	".json":    "json",
+	".mmd":     "mermaid",
+	".mermaid": "mermaid",
+	".sql":     "sql",
+	".proto":   "protobuf",
+	".graphql": "graphql",
+}
+
+// BuildFeatureDocument collects requirements.md, any technical specification
+// artifacts, implementation-plan.md and the documents of configured artifact
+// types for a feature, in that order
+func BuildFeatureDocument(targetDir, shortName string) (*FeatureDocument, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	return buildFeatureDocument(featureDir, config)
+}
+
+// buildFeatureDocument builds the document for a resolved feature directory
+func buildFeatureDocument(featureDir string, config Config) (*FeatureDocument, error) {
+	name := filepath.Base(featureDir)
+	num, shortName, _ := parseFeatureDirName(name)
+	doc := &FeatureDocument{
+		Feature:   Feature{Number: num, ShortName: shortName, Name: name, Dir: featureDir},
+		Title:     "Feature Specification: " + name,
+		Generated: time.Now(),
+	}
+	if status, err := readFeatureStatus(featureDir); err == nil {
+		doc.Feature.Status = status.CurrentStep
+	}
+
+	entries, err := os.ReadDir(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature directory: %w", err)
+	}
+	documents := artifactDocuments(config)
+	var specFiles []string
+	for _, entry := range entries {
+		if !entry.IsDir() && artifactKind(entry.Name(), documents) == ArtifactSpec {
+			specFiles = append(specFiles, entry.Name())
+		}
+	}
+	sort.Strings(specFiles)
+
+	addPart := func(title, file string) error {
+		content, err := os.ReadFile(filepath.Join(featureDir, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		doc.Parts = append(doc.Parts, ExportPart{
+			Title:    title,
+			File:     file,
+			Content:  string(content),
+			Markdown: strings.HasSuffix(file, ".md"),
+		})
+		return nil
+	}
+
+	if err := addPart("Requirements", "requirements.md"); err != nil {
+		return nil, err
+	}
+	for _, file := range specFiles {
+		if err := addPart("Technical Specification: "+file, file); err != nil {
+			return nil, err
+		}
+	}
+	if err := addPart("Implementation Plan", "implementation-plan.md"); err != nil {
+		return nil, err
+	}
+	for _, t := range artifactTypes(config) {
+		if documents[t.FileName()] {
+			if err := addPart(t.Title, t.FileName()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(doc.Parts) == 0 {
+		return nil, fmt.Errorf("feature %s has no specification artifacts to export", name)
+	}
+	return doc, nil
+}
+
+// markdown returns the part as markdown, wrapping non-markdown artifacts in a
+// titled code block
+func (p ExportPart) markdown() string {
+	if p.Markdown {
+		return strings.TrimRight(p.Content, "\n") + "\n"
+	}
+	lang := specLanguages[strings.ToLower(filepath.Ext(p.File))]
+	return fmt.Sprintf("# %s\n\n```%s\n%s\n```\n", p.Title, lang, strings.TrimRight(p.Content, "\n"))
+}
+
+// metadata returns the title page fields in display order
+func (d *FeatureDocument) metadata() [][2]string {
+	status := d.Feature.Status
+	if status == "" {
+		status = "Unknown"
+	}
+	return [][2]string{
+		{"Feature", d.Feature.Name},
+		{"Current step", status},
+		{"Exported", d.Generated.Format("2006-01-02")},
+	}
+}
+
+// tableOfContents lists the level 1 and 2 headings of every part. Anchors are
+// generated with the same slugger sequence used when rendering the document.
+func (d *FeatureDocument) tableOfContents() []tocEntry {
+	slugger := newHeadingSlugger()
+	slugger.slug(d.Title)
+	slugger.slug("Table of Contents")
+
+	var toc []tocEntry
+	for _, part := range d.Parts {
+		for _, block := range parseMarkdownBlocks(part.markdown()) {
+			if block.Kind != blockHeading {
+				continue
+			}
+			anchor := slugger.slug(block.Text)
+			if block.Level <= 2 {
+				toc = append(toc, tocEntry{Level: block.Level, Title: block.Text, Anchor: anchor})
+			}
+		}
+	}
+	return toc
+}
+
+// RenderFeatureMarkdown renders the document as a single PDF-ready markdown
+// file with a title page, table of contents and page breaks between parts
+func RenderFeatureMarkdown(doc *FeatureDocument) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", doc.Title)
+	for _, field := range doc.metadata() {
+		fmt.Fprintf(&b, "**%s:** %s  \n", field[0], field[1])
+	}
+
+	b.WriteString("\n## Table of Contents\n\n")
+	for _, entry := range doc.tableOfContents() {
+		fmt.Fprintf(&b, "%s- [%s](#%s)\n", strings.Repeat("  ", entry.Level-1), entry.Title, entry.Anchor)
+	}
+
+	for _, part := range doc.Parts {
+		b.WriteString("\n<div style=\"page-break-before: always;\"></div>\n\n")
+		b.WriteString(part.markdown())
+	}
+	return b.String()
+}
+
+// exportStyles is the stylesheet embedded in exported HTML documents
+const exportStyles = `body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; max-width: 60em; margin: 0 auto; padding: 2em; color: #1f2328; }
+h1, h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; border-radius: 6px; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 90%; }
+:not(pre) > code { background: #eff1f3; padding: .1em .3em; border-radius: 4px; }
+blockquote { color: #59636e; border-left: .25em solid #d0d7de; margin: 0; padding: 0 1em; }
+table { border-collapse: collapse; } th, td { border: 1px solid #d0d7de; padding: .3em .7em; }
+li > input[type=checkbox] { margin-right: .4em; }
+.title-page { min-height: 40vh; display: flex; flex-direction: column; justify-content: center; }
+.title-page dl { display: grid; grid-template-columns: max-content auto; gap: .3em 1em; }
+.title-page dt { font-weight: bold; } .title-page dd { margin: 0; }
+nav.toc ul { list-style: none; padding-left: 1.2em; }
+section.part { page-break-before: always; margin-top: 3em; }
+@media print { body { max-width: none; } }
+`
+
+// RenderFeatureHTML renders the document as self-contained HTML with no
+// external resources
+func RenderFeatureHTML(doc *FeatureDocument) string {
+	slugger := newHeadingSlugger()
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", html.EscapeString(doc.Title), exportStyles)
+
+	fmt.Fprintf(&b, "<header class=\"title-page\">\n<h1 id=\"%s\">%s</h1>\n<dl>\n", slugger.slug(doc.Title), html.EscapeString(doc.Title))
+	for _, field := range doc.metadata() {
+		fmt.Fprintf(&b, "<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(field[0]), html.EscapeString(field[1]))
+	}
+	b.WriteString("</dl>\n</header>\n")
+
+	fmt.Fprintf(&b, "<nav class=\"toc\">\n<h2 id=\"%s\">Table of Contents</h2>\n<ul>\n", slugger.slug("Table of Contents"))
+	for _, entry := range doc.tableOfContents() {
+		fmt.Fprintf(&b, "<li style=\"margin-left: %dem\"><a href=\"#%s\">%s</a></li>\n", entry.Level-1, entry.Anchor, html.EscapeString(entry.Title))
+	}
+	b.WriteString("</ul>\n</nav>\n")
+
+	for _, part := range doc.Parts {
+		fmt.Fprintf(&b, "<section class=\"part\" data-file=\"%s\">\n", html.EscapeString(part.File))
+		b.WriteString(renderBlocksHTML(parseMarkdownBlocks(part.markdown()), slugger))
+		b.WriteString("</section>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// ExportFeature renders a feature's specification in the given format
+func ExportFeature(targetDir, shortName, format string) ([]byte, error) {
+	doc, err := BuildFeatureDocument(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case ExportMarkdown:
+		return []byte(RenderFeatureMarkdown(doc)), nil
+	case ExportHTML:
+		return []byte(RenderFeatureHTML(doc)), nil
+	case ExportDOCX:
+		return RenderFeatureDOCX(doc)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q (expected html, md or docx)", format)
+	}
+}
+
+// ExportSite writes an HTML page for every feature plus an index.html listing
+// them to outputDir, returning the paths of the created files
+func ExportSite(targetDir, outputDir string) ([]string, error) {
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var createdFiles []string
+	var index strings.Builder
+	index.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&index, "<title>Feature Specifications</title>\n<style>\n%s</style>\n</head>\n<body>\n", exportStyles)
+	index.WriteString("<h1>Feature Specifications</h1>\n<table>\n<thead><tr><th>Feature</th><th>Current step</th></tr></thead>\n<tbody>\n")
+
+	for _, feature := range features {
+		doc, err := buildFeatureDocument(feature.Dir, config)
+		if err != nil {
+			// Features without artifacts (such as the example spec) are listed but not linked
+			fmt.Fprintf(&index, "<tr><td>%s</td><td>%s</td></tr>\n", html.EscapeString(feature.Name), html.EscapeString(feature.Status))
+			continue
+		}
+
+		pageName := feature.Name + ".html"
+		pagePath := filepath.Join(outputDir, pageName)
+		if err := os.WriteFile(pagePath, []byte(RenderFeatureHTML(doc)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", pageName, err)
+		}
+		createdFiles = append(createdFiles, pagePath)
+
+		fmt.Fprintf(&index, "<tr><td><a href=\"%s\">%s</a></td><td>%s</td></tr>\n",
+			html.EscapeString(pageName), html.EscapeString(feature.Name), html.EscapeString(feature.Status))
+	}
+	index.WriteString("</tbody>\n</table>\n</body>\n</html>\n")
+
+	indexPath := filepath.Join(outputDir, "index.html")
+	if err := os.WriteFile(indexPath, []byte(index.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write index.html: %w", err)
+	}
+	createdFiles = append(createdFiles, indexPath)
+	return createdFiles, nil
+}
@@ -0,0 +1,125 @@
+package spec_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Export", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		featureDir := filepath.Join(tempDir, ".spec", "001-user-auth")
+		Expect(os.WriteFile(filepath.Join(featureDir, "openapi.yaml"), []byte("openapi: 3.0.0\n"), 0644)).To(Succeed())
+		Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Plan Generated")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("BuildFeatureDocument", func() {
+		It("should order requirements, technical specs and the implementation plan", func() {
+			doc, err := spec.BuildFeatureDocument(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+
+			var files []string
+			for _, part := range doc.Parts {
+				files = append(files, part.File)
+			}
+			Expect(files).To(Equal([]string{"requirements.md", "openapi.yaml", "implementation-plan.md"}))
+			Expect(doc.Feature.Status).To(Equal("Implementation Plan Generated"))
+		})
+	})
+
+	Describe("ExportFeature", func() {
+		It("should export markdown with a title page and table of contents", func() {
+			data, err := spec.ExportFeature(tempDir, "user-auth", spec.ExportMarkdown)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := string(data)
+			Expect(content).To(HavePrefix("# Feature Specification: 001-user-auth"))
+			Expect(content).To(ContainSubstring("**Current step:** Implementation Plan Generated"))
+			Expect(content).To(ContainSubstring("## Table of Contents"))
+			Expect(content).To(ContainSubstring("  - [Acceptance Criteria](#acceptance-criteria)"))
+			Expect(content).To(ContainSubstring("# Technical Specification: openapi.yaml\n\n```yaml\nopenapi: 3.0.0\n```"))
+		})
+
+		It("should export self-contained HTML", func() {
+			data, err := spec.ExportFeature(tempDir, "user-auth", spec.ExportHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := string(data)
+			Expect(content).To(HavePrefix("<!DOCTYPE html>"))
+			Expect(content).To(ContainSubstring("<style>"))
+			Expect(content).NotTo(ContainSubstring("<script"))
+			Expect(content).NotTo(ContainSubstring("<link"))
+			Expect(content).To(ContainSubstring(`<h2 id="acceptance-criteria">Acceptance Criteria</h2>`))
+			Expect(content).To(ContainSubstring(`<a href="#acceptance-criteria">Acceptance Criteria</a>`))
+			Expect(content).To(ContainSubstring(`<input type="checkbox" disabled> Step 1`))
+		})
+
+		It("should export a valid docx archive", func() {
+			data, err := spec.ExportFeature(tempDir, "user-auth", spec.ExportDOCX)
+			Expect(err).NotTo(HaveOccurred())
+
+			archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			Expect(err).NotTo(HaveOccurred())
+
+			var names []string
+			var document string
+			for _, file := range archive.File {
+				names = append(names, file.Name)
+				if file.Name == "word/document.xml" {
+					r, err := file.Open()
+					Expect(err).NotTo(HaveOccurred())
+					content, err := io.ReadAll(r)
+					Expect(err).NotTo(HaveOccurred())
+					document = string(content)
+				}
+			}
+			Expect(names).To(ContainElements("[Content_Types].xml", "word/document.xml", "word/styles.xml"))
+			Expect(document).To(ContainSubstring("Feature Specification: 001-user-auth"))
+			Expect(document).To(ContainSubstring(`<w:pStyle w:val="Heading2"/>`))
+		})
+
+		It("should reject unknown formats", func() {
+			_, err := spec.ExportFeature(tempDir, "user-auth", "pdf")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported export format"))
+		})
+	})
+
+	Describe("ExportSite", func() {
+		It("should write a page per feature and an index", func() {
+			outputDir := filepath.Join(tempDir, "site")
+			createdFiles, err := spec.ExportSite(tempDir, outputDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdFiles).To(ContainElement(filepath.Join(outputDir, "001-user-auth.html")))
+
+			index, err := os.ReadFile(filepath.Join(outputDir, "index.html"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(index)).To(ContainSubstring(`<a href="001-user-auth.html">001-user-auth</a>`))
+			Expect(string(index)).To(ContainSubstring("000-example-spec"))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature describes a feature specification directory under .spec
+type Feature struct {
+	Number    int    `json:"number"`
+	ShortName string `json:"short-name"`
+	Name      string `json:"name"`
+	Dir       string `json:"-"`
+	Status    string `json:"current-step"`
+}
+
+// WorkflowSteps lists the suggested feature statuses in workflow order
+var WorkflowSteps = []string{
+	"Requirements Gathering",
+	"Requirements Context Gathering",
+	"Requirements Expert Q&A",
+	"Requirements Complete",
+	"Requirements Interactive Review",
+	"Implementation Planning",
+	"Implementation Plan Q&A",
+	"Implementation Plan Generated",
+	"Implementation Plan Interactive Review",
+	"Implementation Planning Complete",
+	"Implementation In Progress",
+	"Implementation Complete",
+}
+
+// normalizeStep folds a status into a comparable form so that
+// "Requirements Gathering" and "requirements-gathering" are treated alike
+func normalizeStep(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(status)
+}
+
+// StepIndex returns the position of a status in WorkflowSteps, or -1 if unknown
+func StepIndex(status string) int {
+	normalized := normalizeStep(status)
+	for i, step := range WorkflowSteps {
+		if normalizeStep(step) == normalized {
+			return i
+		}
+	}
+	return -1
+}
+
+// stepsEqual reports whether two statuses refer to the same workflow step
+func stepsEqual(a, b string) bool {
+	return normalizeStep(a) == normalizeStep(b)
+}
+
+// resolveFeatureDir validates the short name and locates its feature directory
+func resolveFeatureDir(targetDir, shortName string) (string, error) {
+	if err := ValidateFeatureName(shortName); err != nil {
+		return "", err
+	}
+
+	specDir := filepath.Join(targetDir, ".spec")
+	if _, err := os.Stat(specDir); os.IsNotExist(err) {
+		return "", fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	}
+
+	return findFeatureDirectory(specDir, shortName)
+}
+
+// parseFeatureDirName splits a directory name of the form NNN-short-name
+func parseFeatureDirName(name string) (int, string, bool) {
+	if len(name) < 5 || name[3] != '-' {
+		return 0, "", false
+	}
+	num, err := strconv.Atoi(name[:3])
+	if err != nil {
+		return 0, "", false
+	}
+	return num, name[4:], true
+}
+
+// ListFeatures returns all feature directories under .spec ordered by number
+func ListFeatures(targetDir string) ([]Feature, error) {
+	specDir := filepath.Join(targetDir, ".spec")
+	entries, err := os.ReadDir(specDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf(".spec directory not found. Run 'specware init' first")
+		}
+		return nil, fmt.Errorf("failed to read spec directory: %w", err)
+	}
+
+	var features []Feature
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		num, shortName, ok := parseFeatureDirName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		featureDir := filepath.Join(specDir, entry.Name())
+		feature := Feature{
+			Number:    num,
+			ShortName: shortName,
+			Name:      entry.Name(),
+			Dir:       featureDir,
+		}
+		if status, err := readFeatureStatus(featureDir); err == nil {
+			feature.Status = status.CurrentStep
+		}
+		features = append(features, feature)
+	}
+
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Number < features[j].Number
+	})
+	return features, nil
+}
+
+// readFeatureStatus reads .spec-status.json from a feature directory
+func readFeatureStatus(featureDir string) (FeatureStatus, error) {
+	var status FeatureStatus
+	data, err := os.ReadFile(filepath.Join(featureDir, ".spec-status.json"))
+	if err != nil {
+		return status, err
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return status, fmt.Errorf("failed to parse .spec-status.json: %w", err)
+	}
+	return status, nil
+}
+
+// writeFeatureStatus writes .spec-status.json to a feature directory
+func writeFeatureStatus(featureDir string, status FeatureStatus) error {
+	jsonData, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status data: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, ".spec-status.json"), jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	return nil
+}
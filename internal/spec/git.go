@@ -0,0 +1,179 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// specTrailerPattern matches "Spec: 007-user-auth" commit message trailers
+var specTrailerPattern = regexp.MustCompile(`(?im)^Spec:[ \t]*(\S+)[ \t]*$`)
+
+// FeatureCommit is a commit whose message references a feature
+type FeatureCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c FeatureCommit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// runGit runs a git command in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsGitRepository reports whether dir is inside a git work tree
+func IsGitRepository(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// FeatureBranchName returns the branch name used for a feature directory
+func FeatureBranchName(config Config, featureName string) string {
+	return config.Git.BranchPrefix + featureName
+}
+
+// CreateFeatureBranch creates and switches to a branch named after the
+// feature, recording the branch and the commit it started from in
+// .spec-status.json. It returns the name of the created branch.
+func CreateFeatureBranch(targetDir, shortName string) (string, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return "", err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return "", err
+	}
+	return createFeatureBranch(targetDir, featureDir, config)
+}
+
+// createFeatureBranch performs CreateFeatureBranch for a resolved feature directory
+func createFeatureBranch(targetDir, featureDir string, config Config) (string, error) {
+	if !IsGitRepository(targetDir) {
+		return "", fmt.Errorf("%s is not a git repository", targetDir)
+	}
+
+	statusData, err := readFeatureStatus(featureDir)
+	if err != nil {
+		return "", err
+	}
+	if statusData.Branch != "" {
+		return "", fmt.Errorf("feature already has branch %s", statusData.Branch)
+	}
+
+	branch := FeatureBranchName(config, filepath.Base(featureDir))
+	if _, err := runGit(targetDir, "check-ref-format", "--branch", branch); err != nil {
+		return "", fmt.Errorf("invalid branch name %q", branch)
+	}
+	if _, err := runGit(targetDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return "", fmt.Errorf("branch %s already exists", branch)
+	}
+
+	// A repository without commits has no base commit; the branch is still created
+	baseCommit, _ := runGit(targetDir, "rev-parse", "--verify", "--quiet", "HEAD")
+	if _, err := runGit(targetDir, "checkout", "-b", branch); err != nil {
+		return "", err
+	}
+
+	statusData.Branch = branch
+	statusData.BaseCommit = baseCommit
+	if err := writeFeatureStatus(featureDir, statusData); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+// createConfiguredBranch creates the feature branch if the configuration asks
+// for one at the given point. Failures are reported as warnings so that git
+// problems never block spec changes. Messages are written to out.
+func createConfiguredBranch(targetDir, featureDir, trigger string, out io.Writer) {
+	config, err := LoadConfig(targetDir)
+	if err != nil || config.Git.BranchOn != trigger {
+		return
+	}
+	branch, err := createFeatureBranch(targetDir, featureDir, config)
+	if err != nil {
+		fmt.Fprintf(out, "Warning: could not create feature branch: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "Created and switched to branch %s\n", branch)
+}
+
+// enteringImplementation reports whether a status change moves a feature
+// into the implementation steps
+func enteringImplementation(from, to string) bool {
+	return enteringStep("Implementation In Progress", from, to)
+}
+
+// enteringStep reports whether a status change moves a feature from before
+// the given workflow step to it or beyond
+func enteringStep(step, from, to string) bool {
+	start := StepIndex(step)
+	return StepIndex(to) >= start && StepIndex(from) < start
+}
+
+// ListFeatureCommits returns the commits in any local branch whose message
+// has a "Spec:" trailer naming the feature, either as "007-user-auth" or
+// "user-auth", newest first
+func ListFeatureCommits(targetDir, shortName string) ([]FeatureCommit, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsGitRepository(targetDir) {
+		return nil, fmt.Errorf("%s is not a git repository", targetDir)
+	}
+	if _, err := runGit(targetDir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// No commits yet
+		return nil, nil
+	}
+
+	out, err := runGit(targetDir, "log", "--branches", "--date=short", "--format=%H%x1f%an%x1f%ad%x1f%s%x1f%B%x1e")
+	if err != nil {
+		return nil, err
+	}
+
+	featureName := filepath.Base(featureDir)
+	var commits []FeatureCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		for _, m := range specTrailerPattern.FindAllStringSubmatch(fields[4], -1) {
+			if strings.EqualFold(m[1], featureName) || strings.EqualFold(m[1], shortName) {
+				commits = append(commits, FeatureCommit{
+					Hash:    fields[0],
+					Author:  fields[1],
+					Date:    fields[2],
+					Subject: fields[3],
+				})
+				break
+			}
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,141 @@
+package spec_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Git integration", func() {
+	var tempDir string
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", tempDir}, args...)...)
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		return string(out)
+	}
+
+	commit := func(message string) {
+		git("commit", "--allow-empty", "-q", "-m", message)
+	}
+
+	readStatus := func(featureName string) spec.FeatureStatus {
+		data, err := os.ReadFile(filepath.Join(tempDir, ".spec", featureName, ".spec-status.json"))
+		Expect(err).NotTo(HaveOccurred())
+		var status spec.FeatureStatus
+		Expect(json.Unmarshal(data, &status)).To(Succeed())
+		return status
+	}
+
+	setBranchOn := func(branchOn string) {
+		config := spec.DefaultConfig()
+		config.Git.BranchOn = branchOn
+		data, err := json.Marshal(config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(tempDir, ".spec", "config.json"), data, 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-git-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		git("init", "-q", "-b", "main")
+		git("config", "user.name", "Test User")
+		git("config", "user.email", "test@example.com")
+		commit("Initial commit")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("CreateFeatureBranch", func() {
+		It("creates a branch and records it with the base commit", func() {
+			_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			head := git("rev-parse", "HEAD")
+
+			branch, err := spec.CreateFeatureBranch(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(branch).To(Equal("feature/001-user-auth"))
+			Expect(git("branch", "--show-current")).To(Equal("feature/001-user-auth\n"))
+
+			status := readStatus("001-user-auth")
+			Expect(status.Branch).To(Equal("feature/001-user-auth"))
+			Expect(status.BaseCommit + "\n").To(Equal(head))
+			Expect(status.CurrentStep).To(Equal("requirements-gathering"))
+
+			_, err = spec.CreateFeatureBranch(tempDir, "user-auth")
+			Expect(err).To(MatchError(ContainSubstring("already has branch")))
+		})
+
+		It("fails outside a git repository", func() {
+			Expect(os.RemoveAll(filepath.Join(tempDir, ".git"))).To(Succeed())
+			_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = spec.CreateFeatureBranch(tempDir, "user-auth")
+			Expect(err).To(MatchError(ContainSubstring("not a git repository")))
+		})
+
+		It("creates the branch on new-requirements when configured", func() {
+			setBranchOn(spec.BranchOnNewRequirements)
+			var output bytes.Buffer
+			_, err := spec.CreateNewRequirementsWithOptions(tempDir, "user-auth", spec.RequirementsOptions{Output: &output})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readStatus("001-user-auth").Branch).To(Equal("feature/001-user-auth"))
+			Expect(output.String()).To(Equal("Created and switched to branch feature/001-user-auth\n"))
+		})
+
+		It("creates the branch when entering implementation when configured", func() {
+			setBranchOn(spec.BranchOnImplementation)
+			_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Implementation Planning", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+			Expect(readStatus("001-user-auth").Branch).To(BeEmpty())
+
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")).To(Succeed())
+			status := readStatus("001-user-auth")
+			Expect(status.Branch).To(Equal("feature/001-user-auth"))
+			Expect(status.CurrentStep).To(Equal("Implementation In Progress"))
+		})
+	})
+
+	Describe("ListFeatureCommits", func() {
+		It("lists commits with a Spec trailer for the feature on any branch", func() {
+			_, err := spec.CreateNewRequirements(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.CreateNewRequirements(tempDir, "billing")
+			Expect(err).NotTo(HaveOccurred())
+
+			commit("Add login form\n\nSpec: 001-user-auth")
+			commit("Add invoices\n\nSpec: 002-billing")
+			git("checkout", "-q", "-b", "other")
+			commit("Add logout\n\nSome detail\n\nSpec: user-auth")
+			git("checkout", "-q", "main")
+			commit("Mention Spec: 001-user-auth in the subject only")
+
+			commits, err := spec.ListFeatureCommits(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			var subjects []string
+			for _, c := range commits {
+				subjects = append(subjects, c.Subject)
+				Expect(c.Author).To(Equal("Test User"))
+				Expect(c.ShortHash()).To(HaveLen(7))
+			}
+			Expect(subjects).To(ConsistOf("Add login form", "Add logout"))
+		})
+	})
+})
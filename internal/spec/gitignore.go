@@ -0,0 +1,130 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files that can hold the specware ignore block. .gitignore is shared with
+// the team while .git/info/exclude only applies to the local clone.
+const (
+	IgnoreFileGitignore = ".gitignore"
+	IgnoreFileExclude   = ".git/info/exclude"
+)
+
+// Markers delimiting the block of ignore rules managed by specware
+const (
+	ignoreBlockStart = "# BEGIN specware"
+	ignoreBlockEnd   = "# END specware"
+)
+
+// ignoreEntries returns the rules written to the ignore block. Personal
+// Claude Code settings are never committed, even when specs are tracked.
+func ignoreEntries(trackSpecs bool) []string {
+	if trackSpecs {
+		return []string{".claude/settings.local.json"}
+	}
+	return []string{".spec/", ".claude/"}
+}
+
+// ApplyGitIgnore writes the ignore block matching the git tracking choice to
+// the configured ignore file and removes any block from the other file. It
+// returns the path of the updated file relative to targetDir.
+func ApplyGitIgnore(targetDir string, config GitConfig) (string, error) {
+	ignoreFile := config.IgnoreFile
+	if ignoreFile == "" {
+		ignoreFile = IgnoreFileGitignore
+	}
+
+	gitignorePath := filepath.Join(targetDir, IgnoreFileGitignore)
+	excludePath := ""
+	if IsGitRepository(targetDir) {
+		// Ask git so that worktrees, whose .git is a file, are handled too
+		path, err := runGit(targetDir, "rev-parse", "--git-path", "info/exclude")
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(targetDir, path)
+		}
+		excludePath = path
+	}
+
+	var target, targetPath, otherPath string
+	switch ignoreFile {
+	case IgnoreFileGitignore:
+		target, targetPath, otherPath = IgnoreFileGitignore, gitignorePath, excludePath
+	case IgnoreFileExclude:
+		if excludePath == "" {
+			return "", fmt.Errorf("%s requires %s to be a git repository", IgnoreFileExclude, targetDir)
+		}
+		target, targetPath, otherPath = IgnoreFileExclude, excludePath, gitignorePath
+	default:
+		return "", fmt.Errorf("unsupported ignore file %q (expected %s or %s)", ignoreFile, IgnoreFileGitignore, IgnoreFileExclude)
+	}
+
+	if err := writeIgnoreBlock(targetPath, ignoreEntries(config.TrackSpecs)); err != nil {
+		return "", err
+	}
+	// Without a repository there is no exclude file to clean up
+	if otherPath != "" {
+		if err := writeIgnoreBlock(otherPath, nil); err != nil {
+			return "", err
+		}
+	}
+	return target, nil
+}
+
+// writeIgnoreBlock replaces the specware block in an ignore file with the
+// given entries, appending a new block if there is none. With no entries the
+// block is removed. Lines outside the block are left untouched.
+func writeIgnoreBlock(path string, entries []string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if os.IsNotExist(err) && len(entries) == 0 {
+		return nil
+	}
+
+	var kept []string
+	inBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		switch strings.TrimSpace(line) {
+		case ignoreBlockStart:
+			inBlock = true
+			continue
+		case ignoreBlockEnd:
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			kept = append(kept, line)
+		}
+	}
+
+	updated := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if len(entries) > 0 {
+		if updated != "" {
+			updated += "\n\n"
+		}
+		block := append(append([]string{ignoreBlockStart}, entries...), ignoreBlockEnd)
+		updated += strings.Join(block, "\n")
+	}
+	if updated != "" {
+		updated += "\n"
+	}
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package spec_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Git ignore management", func() {
+	var tempDir string
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(tempDir, path))
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	gitInit := func() {
+		out, err := exec.Command("git", "-C", tempDir, "init", "-q").CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-gitignore-test-*")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("InitProjectWithOptions", func() {
+		It("ignores specs in .gitignore by default, keeping existing rules", func() {
+			Expect(os.WriteFile(filepath.Join(tempDir, ".gitignore"), []byte("node_modules/\n"), 0644)).To(Succeed())
+
+			createdFiles, err := spec.InitProject(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdFiles).To(ContainElement(".gitignore"))
+			Expect(readFile(".gitignore")).To(Equal("node_modules/\n\n# BEGIN specware\n.spec/\n.claude/\n# END specware\n"))
+
+			config, err := spec.LoadConfig(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Git.TrackSpecs).To(BeFalse())
+			Expect(config.Git.IgnoreFile).To(Equal(spec.IgnoreFileGitignore))
+		})
+
+		It("records --track-specs and keeps it on re-init", func() {
+			track := true
+			_, err := spec.InitProjectWithOptions(tempDir, spec.InitOptions{TrackSpecs: &track})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readFile(".gitignore")).To(Equal("# BEGIN specware\n.claude/settings.local.json\n# END specware\n"))
+
+			_, err = spec.InitProject(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			config, err := spec.LoadConfig(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Git.TrackSpecs).To(BeTrue())
+			Expect(readFile(".gitignore")).To(Equal("# BEGIN specware\n.claude/settings.local.json\n# END specware\n"))
+		})
+
+		It("keeps the existing configuration on re-init", func() {
+			_, err := spec.InitProject(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.SetConfigValue(tempDir, "review.required-approvals", "2")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.SetConfigValue(tempDir, "verify.test-command", "make test")
+			Expect(err).NotTo(HaveOccurred())
+			config, err := spec.LoadConfig(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			config.Packs = map[string]string{"acme": "1.0.0"}
+			config.Artifacts = map[string]spec.ArtifactTypeConfig{"threat-model": {Context: true}}
+			Expect(spec.SaveConfig(tempDir, config)).To(Succeed())
+
+			createdFiles, err := spec.InitProject(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createdFiles).NotTo(ContainElement(filepath.Join(".spec", "config.json")))
+			config, err = spec.LoadConfig(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Review.RequiredApprovals).To(Equal(2))
+			Expect(config.Verify.TestCommand).To(Equal("make test"))
+			Expect(config.Packs).To(Equal(map[string]string{"acme": "1.0.0"}))
+			Expect(config.Artifacts).To(HaveKey("threat-model"))
+		})
+
+		It("writes to .git/info/exclude for personal use", func() {
+			gitInit()
+			_, err := spec.InitProjectWithOptions(tempDir, spec.InitOptions{IgnoreFile: spec.IgnoreFileExclude})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readFile(".git/info/exclude")).To(HaveSuffix("# BEGIN specware\n.spec/\n.claude/\n# END specware\n"))
+			Expect(filepath.Join(tempDir, ".gitignore")).NotTo(BeAnExistingFile())
+		})
+
+		It("requires a git repository for .git/info/exclude", func() {
+			_, err := spec.InitProjectWithOptions(tempDir, spec.InitOptions{IgnoreFile: spec.IgnoreFileExclude})
+			Expect(err).To(MatchError(ContainSubstring("to be a git repository")))
+			Expect(filepath.Join(tempDir, ".spec")).NotTo(BeADirectory())
+		})
+	})
+
+	Describe("SetConfigValue", func() {
+		BeforeEach(func() {
+			gitInit()
+			_, err := spec.InitProject(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("flips git.track-specs and rewrites the ignore block", func() {
+			config, err := spec.SetConfigValue(tempDir, "git.track-specs", "true")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Git.TrackSpecs).To(BeTrue())
+			Expect(readFile(".gitignore")).To(Equal("# BEGIN specware\n.claude/settings.local.json\n# END specware\n"))
+
+			value, err := spec.GetConfigValue(tempDir, "git.track_specs")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("true"))
+		})
+
+		It("moves the block when the ignore file changes", func() {
+			_, err := spec.SetConfigValue(tempDir, "git.ignore-file", spec.IgnoreFileExclude)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readFile(".git/info/exclude")).To(ContainSubstring("# BEGIN specware\n.spec/\n"))
+			Expect(readFile(".gitignore")).To(BeEmpty())
+		})
+
+		It("parses numbers and rejects invalid values", func() {
+			config, err := spec.SetConfigValue(tempDir, "requirements.discovery-questions", "8")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Requirements.DiscoveryQuestions).To(Equal(8))
+
+			_, err = spec.SetConfigValue(tempDir, "git.track-specs", "maybe")
+			Expect(err).To(MatchError(ContainSubstring("expects true or false")))
+			_, err = spec.SetConfigValue(tempDir, "git.branch-on", "sometimes")
+			Expect(err).To(MatchError(ContainSubstring("git.branch_on must be")))
+			_, err = spec.SetConfigValue(tempDir, "git.unknown", "1")
+			Expect(err).To(MatchError(ContainSubstring("unknown config key")))
+
+			reloaded, err := spec.LoadConfig(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloaded.Requirements.DiscoveryQuestions).To(Equal(8))
+			Expect(reloaded.Git.BranchOn).To(Equal(spec.BranchOnNever))
+		})
+
+		It("only changes the key it sets", func() {
+			configPath := filepath.Join(tempDir, ".spec", "config.json")
+			Expect(os.WriteFile(configPath, []byte(`{"custom_team_key": {"owner": "platform"}, "artifacts": {"threat-model": {"context": false}}}`), 0644)).To(Succeed())
+
+			_, err := spec.SetConfigValue(tempDir, "verify.test-command", "go test ./...")
+			Expect(err).NotTo(HaveOccurred())
+			config, err := spec.SetConfigValue(tempDir, "artifacts.threat-model.context", "true")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Artifacts["threat-model"].Context).To(BeTrue())
+			Expect(spec.GetConfigValue(tempDir, "artifacts.threat-model.context")).To(Equal("true"))
+
+			Expect(readFile(".spec/config.json")).To(MatchJSON(`{
+				"custom_team_key": {"owner": "platform"},
+				"artifacts": {"threat-model": {"context": true}},
+				"verify": {"test_command": "go test ./..."}
+			}`))
+
+			_, err = spec.SetConfigValue(tempDir, "artifacts.threat-model", "x")
+			Expect(err).To(MatchError(ContainSubstring("is a section")))
+		})
+	})
+})
@@ -0,0 +1,315 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Git hooks installed by InstallHooks
+const (
+	HookPreCommit = "pre-commit"
+	HookCommitMsg = "commit-msg"
+)
+
+// HookNames lists the hooks managed by specware
+var HookNames = []string{HookPreCommit, HookCommitMsg}
+
+// hookMarker identifies hook scripts written by specware
+const hookMarker = "# specware-hook"
+
+// chainedHookSuffix is appended to a pre-existing hook that specware moved
+// aside; the specware hook runs it first
+const chainedHookSuffix = ".pre-specware"
+
+// requiredFeatureFiles are the files CreateNewRequirements creates, which
+// every committed feature directory must keep
+var requiredFeatureFiles = []string{"requirements.md", "context-requirements.md", ".spec-status.json"}
+
+// hookScript renders the shell script for a hook. Any hook that existed
+// before installation runs first, and specware is invoked from the project
+// directory so that .spec/ is found.
+func hookScript(hook, specwareBinary, projectDir string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "%s: installed by 'specware hooks install'\n", hookMarker)
+	b.WriteString("hook_dir=$(dirname \"$0\")\n")
+	fmt.Fprintf(&b, "if [ -x \"$hook_dir/%s%s\" ]; then\n", hook, chainedHookSuffix)
+	fmt.Fprintf(&b, "\t\"$hook_dir/%s%s\" \"$@\" || exit $?\n", hook, chainedHookSuffix)
+	b.WriteString("fi\n")
+	if projectDir != "." {
+		if hook == HookCommitMsg {
+			// git passes the message file relative to the top level
+			b.WriteString("set -- \"$(cd \"$(dirname \"$1\")\" && pwd)/$(basename \"$1\")\"\n")
+		}
+		fmt.Fprintf(&b, "cd %s || exit 1\n", shellQuote(projectDir))
+	}
+	fmt.Fprintf(&b, "specware_bin=%s\n", shellQuote(specwareBinary))
+	b.WriteString("if [ ! -x \"$specware_bin\" ]; then\n\tspecware_bin=specware\nfi\n")
+	fmt.Fprintf(&b, "exec \"$specware_bin\" hooks run %s \"$@\"\n", hook)
+	return b.String()
+}
+
+// shellQuote quotes a string for use in a POSIX shell script
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// gitPath resolves a path inside the git directory, e.g. "hooks"
+func gitPath(targetDir, name string) (string, error) {
+	path, err := runGit(targetDir, "rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(targetDir, path)
+	}
+	return path, nil
+}
+
+// InstallHooks writes the specware pre-commit and commit-msg hooks into the
+// repository containing targetDir. Existing hooks that were not written by
+// specware are kept and run before the specware checks. It returns the paths
+// of the installed hooks.
+func InstallHooks(targetDir, specwareBinary string) ([]string, error) {
+	if !IsGitRepository(targetDir) {
+		return nil, fmt.Errorf("%s is not a git repository", targetDir)
+	}
+	hooksDir, err := gitPath(targetDir, "hooks")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	topLevel, err := runGit(targetDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	absTarget, err := filepath.Abs(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	// Resolve symlinks so the project path can be made relative to the
+	// top level reported by git, e.g. for temporary directories on macOS
+	if resolved, err := filepath.EvalSymlinks(absTarget); err == nil {
+		absTarget = resolved
+	}
+	projectDir, err := filepath.Rel(topLevel, absTarget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate project within repository: %w", err)
+	}
+
+	var installed []string
+	for _, hook := range HookNames {
+		hookPath := filepath.Join(hooksDir, hook)
+		existing, err := os.ReadFile(hookPath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read existing %s hook: %w", hook, err)
+		}
+		if err == nil && !bytes.Contains(existing, []byte(hookMarker)) {
+			chained := hookPath + chainedHookSuffix
+			if _, err := os.Stat(chained); err == nil {
+				return nil, fmt.Errorf("cannot install %s hook: both %s and %s exist", hook, hookPath, chained)
+			}
+			if err := os.Rename(hookPath, chained); err != nil {
+				return nil, fmt.Errorf("failed to move existing %s hook: %w", hook, err)
+			}
+		}
+
+		if err := os.WriteFile(hookPath, []byte(hookScript(hook, specwareBinary, projectDir)), 0755); err != nil {
+			return nil, fmt.Errorf("failed to write %s hook: %w", hook, err)
+		}
+		installed = append(installed, hookPath)
+	}
+	return installed, nil
+}
+
+// stagedFiles lists the paths staged for commit relative to targetDir
+func stagedFiles(targetDir string) ([]string, error) {
+	out, err := runGit(targetDir, "diff", "--cached", "--name-only", "--relative", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// stagedFeatureDirs returns the feature directories with staged changes
+func stagedFeatureDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		parts := strings.Split(file, "/")
+		if len(parts) < 3 || parts[0] != ".spec" {
+			continue
+		}
+		// Feature 000 is the example created by init, which has no requirements
+		if num, _, ok := parseFeatureDirName(parts[1]); ok && num > 0 && !seen[parts[1]] {
+			seen[parts[1]] = true
+			dirs = append(dirs, parts[1])
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// validateFeatureStatus checks that .spec-status.json content is a JSON
+// object using only the keys specware writes
+func validateFeatureStatus(content []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	var status FeatureStatus
+	if err := decoder.Decode(&status); err != nil {
+		return err
+	}
+	if strings.TrimSpace(status.CurrentStep) == "" {
+		return fmt.Errorf("current-step is missing")
+	}
+	return nil
+}
+
+// CheckPreCommit verifies the staged state of every feature directory touched
+// by the commit. Each feature that is not being removed must still contain the
+// files created by new-requirements and a well-formed .spec-status.json. The
+// returned problems are empty when the commit may proceed.
+func CheckPreCommit(targetDir string) ([]string, error) {
+	files, err := stagedFiles(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, dir := range stagedFeatureDirs(files) {
+		featurePath := ".spec/" + dir
+		out, err := runGit(targetDir, "ls-files", "--cached", "--", featurePath)
+		if err != nil {
+			return nil, err
+		}
+		if out == "" {
+			// The whole feature is being deleted
+			continue
+		}
+
+		committed := make(map[string]bool)
+		for _, file := range strings.Split(out, "\n") {
+			committed[strings.TrimPrefix(file, featurePath+"/")] = true
+		}
+		for _, required := range requiredFeatureFiles {
+			if !committed[required] {
+				problems = append(problems, fmt.Sprintf("%s: missing %s", featurePath, required))
+			}
+		}
+
+		if committed[".spec-status.json"] {
+			content, err := runGit(targetDir, "show", ":./"+featurePath+"/.spec-status.json")
+			if err != nil {
+				return nil, err
+			}
+			if err := validateFeatureStatus([]byte(content)); err != nil {
+				problems = append(problems, fmt.Sprintf("%s/.spec-status.json: %v", featurePath, err))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// currentFeatureBranch returns the feature whose branch is checked out, if any
+func currentFeatureBranch(targetDir string, config Config) (Feature, bool) {
+	branch, err := runGit(targetDir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil || branch == "" {
+		return Feature{}, false
+	}
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return Feature{}, false
+	}
+	for _, feature := range features {
+		if branch == FeatureBranchName(config, feature.Name) {
+			return feature, true
+		}
+		if status, err := readFeatureStatus(feature.Dir); err == nil && status.Branch == branch {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// CheckCommitMessageFile runs CheckCommitMessage on the message in path, as
+// passed to the commit-msg hook. The file is only read when
+// git.require_spec_trailer is set; a relative path is resolved against the
+// top level of the repository, where git runs hooks.
+func CheckCommitMessageFile(targetDir, path string) ([]string, error) {
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Git.RequireSpecTrailer {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		topLevel, err := runGit(targetDir, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(topLevel, path)
+	}
+	message, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit message: %w", err)
+	}
+	return CheckCommitMessage(targetDir, string(message))
+}
+
+// CheckCommitMessage enforces git.require_spec_trailer: when the checked out
+// branch belongs to a feature and the commit touches files outside .spec/,
+// the message must carry a "Spec:" trailer naming that feature
+func CheckCommitMessage(targetDir, message string) ([]string, error) {
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Git.RequireSpecTrailer {
+		return nil, nil
+	}
+	feature, ok := currentFeatureBranch(targetDir, config)
+	if !ok {
+		return nil, nil
+	}
+
+	files, err := stagedFiles(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	touchesCode := false
+	for _, file := range files {
+		if !strings.HasPrefix(file, ".spec/") {
+			touchesCode = true
+			break
+		}
+	}
+	if !touchesCode {
+		return nil, nil
+	}
+
+	// Lines starting with '#' are stripped by git before committing
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	for _, m := range specTrailerPattern.FindAllStringSubmatch(strings.Join(lines, "\n"), -1) {
+		if strings.EqualFold(m[1], feature.Name) || strings.EqualFold(m[1], feature.ShortName) {
+			return nil, nil
+		}
+	}
+	return []string{fmt.Sprintf("commits to code on the branch of feature %s need a 'Spec: %s' trailer", feature.ShortName, feature.Name)}, nil
+}
@@ -0,0 +1,149 @@
+package spec_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Git hooks", func() {
+	var tempDir string
+
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", tempDir}, args...)...).CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		return string(out)
+	}
+
+	writeFile := func(path, content string) {
+		fullPath := filepath.Join(tempDir, path)
+		Expect(os.MkdirAll(filepath.Dir(fullPath), 0755)).To(Succeed())
+		Expect(os.WriteFile(fullPath, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-hooks-test-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		git("init", "-q", "-b", "main")
+		git("config", "user.name", "Test User")
+		git("config", "user.email", "test@example.com")
+		track := true
+		_, err = spec.InitProjectWithOptions(tempDir, spec.InitOptions{TrackSpecs: &track})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		git("add", "-A")
+		git("commit", "-q", "-m", "Initial commit")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("InstallHooks", func() {
+		It("installs both hooks and chains an existing hook", func() {
+			writeFile(".git/hooks/pre-commit", "#!/bin/sh\necho existing\n")
+
+			installed, err := spec.InstallHooks(tempDir, "/usr/local/bin/specware")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(installed).To(HaveLen(2))
+
+			script, err := os.ReadFile(filepath.Join(tempDir, ".git", "hooks", "pre-commit"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(script)).To(ContainSubstring("pre-commit.pre-specware"))
+			Expect(string(script)).To(ContainSubstring("hooks run pre-commit"))
+			Expect(filepath.Join(tempDir, ".git", "hooks", "pre-commit.pre-specware")).To(BeAnExistingFile())
+
+			// Reinstalling replaces the specware hooks without touching the chained one
+			_, err = spec.InstallHooks(tempDir, "/usr/local/bin/specware")
+			Expect(err).NotTo(HaveOccurred())
+			chained, err := os.ReadFile(filepath.Join(tempDir, ".git", "hooks", "pre-commit.pre-specware"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(chained)).To(ContainSubstring("echo existing"))
+		})
+	})
+
+	Describe("CheckPreCommit", func() {
+		It("passes for well-formed feature directories", func() {
+			writeFile(".spec/001-user-auth/requirements.md", "# Updated\n")
+			git("add", "-A")
+			Expect(spec.CheckPreCommit(tempDir)).To(BeEmpty())
+		})
+
+		It("reports missing files and malformed status", func() {
+			git("rm", "-q", ".spec/001-user-auth/context-requirements.md")
+			writeFile(".spec/001-user-auth/.spec-status.json", `{"current-step": "Requirements Complete", "owner": "me"}`)
+			git("add", "-A")
+
+			problems, err := spec.CheckPreCommit(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(ConsistOf(
+				".spec/001-user-auth: missing context-requirements.md",
+				ContainSubstring(`unknown field "owner"`),
+			))
+		})
+
+		It("checks the staged content rather than the working tree", func() {
+			writeFile(".spec/001-user-auth/.spec-status.json", "{not json")
+			git("add", "-A")
+			writeFile(".spec/001-user-auth/.spec-status.json", `{"current-step": "Requirements Complete"}`)
+
+			problems, err := spec.CheckPreCommit(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(HaveLen(1))
+		})
+
+		It("allows removing a whole feature", func() {
+			git("rm", "-rq", ".spec/001-user-auth")
+			Expect(spec.CheckPreCommit(tempDir)).To(BeEmpty())
+		})
+	})
+
+	Describe("CheckCommitMessage", func() {
+		BeforeEach(func() {
+			_, err := spec.SetConfigValue(tempDir, "git.require-spec-trailer", "true")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.CreateFeatureBranch(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			writeFile("main.go", "package main\n")
+			git("add", "main.go")
+		})
+
+		It("requires a Spec trailer for code on a feature branch", func() {
+			problems, err := spec.CheckCommitMessage(tempDir, "Add main\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(ConsistOf(ContainSubstring("'Spec: 001-user-auth' trailer")))
+
+			Expect(spec.CheckCommitMessage(tempDir, "Add main\n\nSpec: 001-user-auth\n")).To(BeEmpty())
+		})
+
+		It("ignores trailers in comment lines", func() {
+			problems, err := spec.CheckCommitMessage(tempDir, "Add main\n\n# Spec: 001-user-auth\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(HaveLen(1))
+		})
+
+		It("does not apply on other branches", func() {
+			git("checkout", "-q", "main")
+			Expect(spec.CheckCommitMessage(tempDir, "Add main\n")).To(BeEmpty())
+		})
+
+		It("reads a relative message file from the top level of the repository", func() {
+			writeFile(filepath.Join(".git", "COMMIT_EDITMSG"), "Add main\n")
+			problems, err := spec.CheckCommitMessageFile(tempDir, filepath.Join(".git", "COMMIT_EDITMSG"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(HaveLen(1))
+
+			_, err = spec.SetConfigValue(tempDir, "git.require-spec-trailer", "false")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.CheckCommitMessageFile(tempDir, "missing-message")).To(BeEmpty())
+		})
+	})
+})
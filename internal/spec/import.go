@@ -0,0 +1,359 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Supported import source formats
+const (
+	ImportMarkdown        = "markdown"
+	ImportGitHubIssueJSON = "github-issue-json"
+	ImportJiraJSON        = "jira-json"
+)
+
+// SourceFileName holds the original document of an imported feature
+const SourceFileName = "source.md"
+
+// maxImportShortNameChars limits short names derived from document titles
+const maxImportShortNameChars = 40
+
+// FeatureSource records where an imported feature came from
+type FeatureSource struct {
+	Format     string `json:"format"`
+	File       string `json:"file"`
+	ID         string `json:"id,omitempty"`
+	URL        string `json:"url,omitempty"`
+	ImportedAt string `json:"imported-at"`
+}
+
+// ImportOptions describes the document a feature is imported from
+type ImportOptions struct {
+	// Format is one of ImportMarkdown, ImportGitHubIssueJSON or ImportJiraJSON
+	Format string
+	// SourceName is the file the document was read from, or "-" for stdin
+	SourceName string
+	// ShortName overrides the short name derived from the document title
+	ShortName string
+}
+
+// ImportedDocument is a source document normalized to markdown
+type ImportedDocument struct {
+	Title string
+	Body  string
+	ID    string
+	URL   string
+}
+
+// Requirements sections that imported documents are mapped onto, keyed by
+// the lower-cased section titles that feed them
+var importSectionAliases = map[string]string{
+	"problem":             "Problem Statement",
+	"problem statement":   "Problem Statement",
+	"background":          "Problem Statement",
+	"motivation":          "Problem Statement",
+	"solution":            "Solution Overview",
+	"solution overview":   "Solution Overview",
+	"proposal":            "Solution Overview",
+	"proposed solution":   "Solution Overview",
+	"overview":            "Solution Overview",
+	"summary":             "Solution Overview",
+	"acceptance criteria": "Acceptance Criteria",
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseImportSource normalizes an issue or product document to markdown
+func ParseImportSource(data []byte, format string) (ImportedDocument, error) {
+	switch format {
+	case ImportMarkdown:
+		doc := ImportedDocument{Body: strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))}
+		for _, h := range ParseHeadings([]byte(doc.Body)) {
+			if h.Level == 1 {
+				doc.Title = h.Title
+				break
+			}
+		}
+		return doc, nil
+
+	case ImportGitHubIssueJSON:
+		var issue struct {
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+			URL     string `json:"url"`
+		}
+		if err := json.Unmarshal(data, &issue); err != nil {
+			return ImportedDocument{}, fmt.Errorf("failed to parse GitHub issue JSON: %w", err)
+		}
+		if issue.Title == "" {
+			return ImportedDocument{}, fmt.Errorf("GitHub issue JSON has no title")
+		}
+		doc := ImportedDocument{
+			Title: issue.Title,
+			Body:  strings.TrimSpace(strings.ReplaceAll(issue.Body, "\r\n", "\n")),
+			URL:   issue.HTMLURL,
+		}
+		if doc.URL == "" {
+			doc.URL = issue.URL
+		}
+		if issue.Number > 0 {
+			doc.ID = fmt.Sprintf("#%d", issue.Number)
+		}
+		return doc, nil
+
+	case ImportJiraJSON:
+		var issue struct {
+			Key    string `json:"key"`
+			Self   string `json:"self"`
+			Fields struct {
+				Summary     string          `json:"summary"`
+				Description json.RawMessage `json:"description"`
+			} `json:"fields"`
+		}
+		if err := json.Unmarshal(data, &issue); err != nil {
+			return ImportedDocument{}, fmt.Errorf("failed to parse Jira issue JSON: %w", err)
+		}
+		if issue.Fields.Summary == "" {
+			return ImportedDocument{}, fmt.Errorf("Jira issue JSON has no fields.summary")
+		}
+		description, err := jiraDescription(issue.Fields.Description)
+		if err != nil {
+			return ImportedDocument{}, err
+		}
+		doc := ImportedDocument{Title: issue.Fields.Summary, Body: description, ID: issue.Key}
+		if issue.Key != "" {
+			if i := strings.Index(issue.Self, "/rest/api/"); i > 0 {
+				doc.URL = issue.Self[:i] + "/browse/" + issue.Key
+			}
+		}
+		return doc, nil
+
+	default:
+		return ImportedDocument{}, fmt.Errorf("unsupported import format %q (expected markdown, github-issue-json or jira-json)", format)
+	}
+}
+
+// jiraDescription returns a Jira description as text. Version 2 of the Jira
+// API returns a plain string and version 3 an Atlassian Document Format tree,
+// whose text is kept paragraph by paragraph.
+func jiraDescription(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")), nil
+	}
+
+	var root adfNode
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return "", fmt.Errorf("failed to parse Jira description: %w", err)
+	}
+	var b strings.Builder
+	writeADF(&b, root)
+	return strings.TrimSpace(blankLines.ReplaceAllString(b.String(), "\n\n")), nil
+}
+
+// adfNode is a node of an Atlassian Document Format tree
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// writeADF writes the text of an Atlassian Document Format node, separating
+// blocks with blank lines and prefixing list items with "- "
+func writeADF(b *strings.Builder, node adfNode) {
+	switch node.Type {
+	case "text":
+		b.WriteString(node.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "listItem":
+		b.WriteString("- ")
+	case "codeBlock":
+		b.WriteString("```\n")
+	}
+	for _, child := range node.Content {
+		writeADF(b, child)
+	}
+	switch node.Type {
+	case "paragraph", "heading", "bulletList", "orderedList":
+		b.WriteString("\n\n")
+	case "listItem":
+		b.WriteString("\n")
+	case "codeBlock":
+		b.WriteString("\n```\n\n")
+	}
+}
+
+// ImportShortName derives a feature short name from a document title
+func ImportShortName(title string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxImportShortNameChars {
+		slug = strings.TrimRight(slug[:maxImportShortNameChars], "-")
+		if i := strings.LastIndex(slug, "-"); i > maxImportShortNameChars/2 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
+
+// mapImportSections assigns the sections of an imported document to
+// requirements sections. A document without recognised sections provides the
+// Problem Statement as a whole.
+func mapImportSections(doc ImportedDocument) map[string]string {
+	body := []byte(doc.Body)
+	mapped := make(map[string]string)
+	for _, h := range ParseHeadings(body) {
+		if h.Level == 1 {
+			continue
+		}
+		section, ok := importSectionAliases[strings.ToLower(strings.TrimRight(h.Title, ":"))]
+		if !ok || mapped[section] != "" {
+			continue
+		}
+		if text := strings.TrimSpace(string(body[h.BodyStart:h.End])); text != "" {
+			mapped[section] = text
+		}
+	}
+	if len(mapped) > 0 {
+		return mapped
+	}
+
+	// Without sections, everything after the title is the problem statement
+	text := doc.Body
+	for _, h := range ParseHeadings(body) {
+		if h.Level == 1 {
+			text = string(body[:h.Start]) + string(body[h.BodyStart:])
+			break
+		}
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		mapped["Problem Statement"] = text
+	}
+	return mapped
+}
+
+// fillRequirements replaces the title placeholder and the bodies of the
+// given level-2 sections of a requirements document
+func fillRequirements(content []byte, title string, sections map[string]string) []byte {
+	text := string(content)
+	if title != "" {
+		text = strings.Replace(text, "[Feature Name]", title, 1)
+	}
+
+	headings := ParseHeadings([]byte(text))
+	// Replace from the end so earlier offsets stay valid
+	for i := len(headings) - 1; i >= 0; i-- {
+		h := headings[i]
+		value, ok := sections[h.Title]
+		if !ok || h.Level != 2 {
+			continue
+		}
+		// Keep nested sub-sections such as "### Dependencies"
+		end := h.End
+		if i+1 < len(headings) && headings[i+1].Start < h.End {
+			end = headings[i+1].Start
+		}
+		text = text[:h.BodyStart] + value + "\n\n" + text[end:]
+	}
+	return []byte(text)
+}
+
+// ImportFeature creates a new feature from an existing issue or product
+// document. The feature is numbered and created like new-requirements, the
+// original document is kept as source.md, recognised sections pre-fill
+// requirements.md, and the source is recorded in .spec-status.json.
+func ImportFeature(targetDir string, data []byte, opts ImportOptions) (string, []string, error) {
+	doc, err := ParseImportSource(data, opts.Format)
+	if err != nil {
+		return "", nil, err
+	}
+
+	shortName := opts.ShortName
+	if shortName == "" {
+		shortName = ImportShortName(doc.Title)
+		if shortName == "" {
+			return "", nil, fmt.Errorf("cannot derive a feature name from the document; use --name")
+		}
+	}
+	if err := ValidateFeatureName(shortName); err != nil {
+		return "", nil, err
+	}
+	if _, err := resolveFeatureDir(targetDir, shortName); err == nil {
+		return "", nil, fmt.Errorf("feature %s already exists", shortName)
+	}
+
+	createdFiles, err := CreateNewRequirements(targetDir, shortName)
+	if err != nil {
+		return "", nil, err
+	}
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	source := doc.Body
+	if opts.Format != ImportMarkdown {
+		heading := doc.Title
+		if doc.ID != "" {
+			heading = doc.ID + ": " + heading
+		}
+		source = "# " + heading + "\n"
+		if doc.URL != "" {
+			source += "\nSource: " + doc.URL + "\n"
+		}
+		if doc.Body != "" {
+			source += "\n" + doc.Body + "\n"
+		}
+	} else {
+		source += "\n"
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, SourceFileName), []byte(source), 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write %s: %w", SourceFileName, err)
+	}
+	createdFiles = append(createdFiles, filepath.Join(".spec", filepath.Base(featureDir), SourceFileName))
+
+	requirementsPath := filepath.Join(featureDir, "requirements.md")
+	requirements, err := os.ReadFile(requirementsPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read requirements.md: %w", err)
+	}
+	requirements = fillRequirements(requirements, doc.Title, mapImportSections(doc))
+	if err := os.WriteFile(requirementsPath, requirements, 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write requirements.md: %w", err)
+	}
+
+	status, err := readFeatureStatus(featureDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read feature status: %w", err)
+	}
+	sourceName := opts.SourceName
+	if sourceName == "" || sourceName == "-" {
+		sourceName = "stdin"
+	}
+	status.Source = &FeatureSource{
+		Format:     opts.Format,
+		File:       sourceName,
+		ID:         doc.ID,
+		URL:        doc.URL,
+		ImportedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := writeFeatureStatus(featureDir, status); err != nil {
+		return "", nil, err
+	}
+
+	return filepath.Base(featureDir), createdFiles, nil
+}
@@ -0,0 +1,102 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Feature import", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-import-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	readFile := func(feature, name string) string {
+		content, err := os.ReadFile(filepath.Join(tempDir, ".spec", feature, name))
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("maps markdown sections onto requirements and keeps the source", func() {
+		prd := "# Bulk User Upload\n\n## Background\nAdmins add users one at a time.\n\n## Proposal\nAccept a CSV upload.\n"
+		feature, createdFiles, err := spec.ImportFeature(tempDir, []byte(prd), spec.ImportOptions{Format: spec.ImportMarkdown, SourceName: "prd.md"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(feature).To(Equal("001-bulk-user-upload"))
+		Expect(createdFiles).To(ContainElement(filepath.Join(".spec", feature, "source.md")))
+
+		Expect(readFile(feature, "source.md")).To(Equal(prd))
+		requirements := readFile(feature, "requirements.md")
+		Expect(requirements).To(HavePrefix("# Requirements Specification: Bulk User Upload\n"))
+		Expect(requirements).To(ContainSubstring("## Problem Statement\nAdmins add users one at a time.\n\n## Solution Overview\nAccept a CSV upload.\n\n## Functional Requirements"))
+		Expect(requirements).To(ContainSubstring("### Dependencies"))
+
+		var status spec.FeatureStatus
+		Expect(json.Unmarshal([]byte(readFile(feature, ".spec-status.json")), &status)).To(Succeed())
+		Expect(status.CurrentStep).To(Equal("requirements-gathering"))
+		Expect(status.Source).NotTo(BeNil())
+		Expect(status.Source.Format).To(Equal(spec.ImportMarkdown))
+		Expect(status.Source.File).To(Equal("prd.md"))
+	})
+
+	It("imports a GitHub issue without sections as the problem statement", func() {
+		issue := `{"number": 42, "title": "Dark mode", "body": "Users want a dark theme.", "html_url": "https://github.com/o/r/issues/42"}`
+		feature, _, err := spec.ImportFeature(tempDir, []byte(issue), spec.ImportOptions{Format: spec.ImportGitHubIssueJSON, SourceName: "-"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(feature).To(Equal("001-dark-mode"))
+
+		Expect(readFile(feature, "source.md")).To(Equal("# #42: Dark mode\n\nSource: https://github.com/o/r/issues/42\n\nUsers want a dark theme.\n"))
+		Expect(readFile(feature, "requirements.md")).To(ContainSubstring("## Problem Statement\nUsers want a dark theme.\n\n## Solution Overview\nBrief description"))
+
+		var status spec.FeatureStatus
+		Expect(json.Unmarshal([]byte(readFile(feature, ".spec-status.json")), &status)).To(Succeed())
+		Expect(status.Source.File).To(Equal("stdin"))
+		Expect(status.Source.ID).To(Equal("#42"))
+		Expect(status.Source.URL).To(Equal("https://github.com/o/r/issues/42"))
+	})
+
+	It("reads Jira descriptions in Atlassian Document Format", func() {
+		issue := `{"key": "PROJ-7", "self": "https://acme.atlassian.net/rest/api/3/issue/10007", "fields": {"summary": "Audit log",
+			"description": {"type": "doc", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Record admin actions."}]},
+				{"type": "bulletList", "content": [{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "logins"}]}]}]}
+			]}}}`
+		doc, err := spec.ParseImportSource([]byte(issue), spec.ImportJiraJSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc).To(Equal(spec.ImportedDocument{
+			Title: "Audit log",
+			Body:  "Record admin actions.\n\n- logins",
+			ID:    "PROJ-7",
+			URL:   "https://acme.atlassian.net/browse/PROJ-7",
+		}))
+	})
+
+	It("uses the given short name and refuses duplicates", func() {
+		opts := spec.ImportOptions{Format: spec.ImportMarkdown, ShortName: "upload"}
+		feature, _, err := spec.ImportFeature(tempDir, []byte("# Anything\n"), opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(feature).To(Equal("001-upload"))
+
+		_, _, err = spec.ImportFeature(tempDir, []byte("# Anything\n"), opts)
+		Expect(err).To(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("derives short names from titles", func() {
+		Expect(spec.ImportShortName("Add OAuth2 login (Google & GitHub)!")).To(Equal("add-oauth2-login-google-github"))
+		Expect(len(spec.ImportShortName("A very long title that keeps going well past the limit for names"))).To(BeNumerically("<=", 40))
+	})
+})
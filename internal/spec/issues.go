@@ -0,0 +1,354 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Supported issue export formats
+const (
+	IssueFormatGitHub  = "github"
+	IssueFormatGitLab  = "gitlab"
+	IssueFormatJiraCSV = "jira-csv"
+)
+
+// Implementation plan item kinds, from coarsest to finest
+const (
+	PlanItemMilestone = "milestone"
+	PlanItemPhase     = "phase"
+	PlanItemStep      = "step"
+)
+
+// planGroupPattern matches "Milestone 1: ..." and "Phase 2: ..." headings
+var planGroupPattern = regexp.MustCompile(`(?i)^(milestone|phase)\s+\d+\s*:?\s*(.*)$`)
+
+// PlanItem is a milestone, phase or step of an implementation plan
+type PlanItem struct {
+	Kind     string
+	Title    string
+	Body     string
+	Done     bool
+	Children []*PlanItem
+
+	level int
+	lines []string
+}
+
+// Issue is a tracker issue generated from an implementation plan item
+type Issue struct {
+	Key      string   `json:"key"`
+	Kind     string   `json:"kind"`
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Labels   []string `json:"labels"`
+	Parent   string   `json:"parent,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// IssueOptions controls which plan items become issues and how they are labelled
+type IssueOptions struct {
+	// Labels are added to every issue, after those configured in issues.labels
+	Labels []string
+	// IncludeDone also exports steps that are already checked off
+	IncludeDone bool
+}
+
+// ParsePlanItems builds the milestone, phase and step hierarchy of an
+// implementation plan. Milestones and phases come from "Milestone N:" and
+// "Phase N:" headings, and steps from checkbox items. Plans may omit
+// milestones or phases, in which case items are returned at the top level.
+// The text and code under an item up to the next item becomes its body.
+func ParsePlanItems(content []byte) []*PlanItem {
+	headings := make(map[int]Heading)
+	for _, h := range ParseHeadings(content) {
+		headings[h.Line] = h
+	}
+	tasks := make(map[int]PlanTask)
+	for _, task := range parsePlanTasks(content) {
+		tasks[task.Line] = task
+	}
+
+	var roots []*PlanItem
+	var milestone, phase, current *PlanItem
+	addItem := func(item *PlanItem) {
+		switch {
+		case item.Kind != PlanItemMilestone && phase != nil:
+			phase.Children = append(phase.Children, item)
+		case item.Kind != PlanItemMilestone && milestone != nil:
+			milestone.Children = append(milestone.Children, item)
+		default:
+			roots = append(roots, item)
+		}
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		lineNum := i + 1
+		if h, ok := headings[lineNum]; ok {
+			match := planGroupPattern.FindStringSubmatch(h.Title)
+			switch {
+			case match != nil && strings.EqualFold(match[1], PlanItemMilestone):
+				milestone = &PlanItem{Kind: PlanItemMilestone, Title: h.Title, level: h.Level}
+				phase = nil
+				roots = append(roots, milestone)
+				current = milestone
+				continue
+			case match != nil:
+				newPhase := &PlanItem{Kind: PlanItemPhase, Title: h.Title, level: h.Level}
+				phase = nil
+				addItem(newPhase)
+				phase = newPhase
+				current = phase
+				continue
+			}
+
+			// Other headings at or above a group's level end that group
+			if phase != nil && h.Level <= phase.level {
+				phase = nil
+				current = nil
+			}
+			if milestone != nil && h.Level <= milestone.level {
+				milestone = nil
+				current = nil
+			}
+			if current != nil && current.Kind == PlanItemStep {
+				current = nil
+			}
+			if current != nil {
+				current.lines = append(current.lines, line)
+			}
+			continue
+		}
+
+		if task, ok := tasks[lineNum]; ok {
+			step := &PlanItem{Kind: PlanItemStep, Title: task.Text, Done: task.Done}
+			addItem(step)
+			current = step
+			continue
+		}
+
+		if current != nil {
+			current.lines = append(current.lines, line)
+		}
+	}
+
+	var finish func(items []*PlanItem)
+	finish = func(items []*PlanItem) {
+		for _, item := range items {
+			item.Body = strings.Trim(strings.ReplaceAll(strings.Join(item.lines, "\n"), "\r", ""), "\n")
+			item.lines = nil
+			finish(item.Children)
+			if item.Kind != PlanItemStep && len(item.Children) > 0 {
+				item.Done = true
+				for _, child := range item.Children {
+					item.Done = item.Done && child.Done
+				}
+			}
+		}
+	}
+	finish(roots)
+	return roots
+}
+
+// BuildIssues turns a feature's implementation plan into issues. Parents are
+// listed before their children, and every issue links to its parent and
+// children by key and by title.
+func BuildIssues(targetDir, shortName string, opts IssueOptions) ([]Issue, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(featureDir, "implementation-plan.md"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("implementation-plan.md not found for feature %s", shortName)
+		}
+		return nil, fmt.Errorf("failed to read implementation-plan.md: %w", err)
+	}
+
+	featureName := filepath.Base(featureDir)
+	baseLabels := append(append([]string{}, config.Issues.Labels...), opts.Labels...)
+	baseLabels = append(baseLabels, "spec:"+featureName)
+
+	var issues []Issue
+	var add func(items []*PlanItem, parentKey, parentTitle string, prefix string) []Issue
+	add = func(items []*PlanItem, parentKey, parentTitle string, prefix string) []Issue {
+		var added []Issue
+		counts := make(map[string]int)
+		for _, item := range items {
+			if item.Done && !opts.IncludeDone {
+				continue
+			}
+			counts[item.Kind]++
+			key := fmt.Sprintf("%s%s-%d", prefix, item.Kind, counts[item.Kind])
+			issue := Issue{
+				Key:    featureName + "/" + key,
+				Kind:   item.Kind,
+				Title:  fmt.Sprintf("[%s] %s", featureName, item.Title),
+				Labels: appendUnique(append([]string{}, baseLabels...), item.Kind),
+				Parent: parentKey,
+			}
+
+			index := len(issues)
+			issues = append(issues, issue)
+			children := add(item.Children, issue.Key, issue.Title, key+"/")
+
+			var body strings.Builder
+			if item.Body != "" {
+				body.WriteString(item.Body + "\n\n")
+			}
+			if len(children) > 0 {
+				body.WriteString("### Sub-issues\n")
+				for _, child := range children {
+					issues[index].Children = append(issues[index].Children, child.Key)
+					fmt.Fprintf(&body, "- %s\n", child.Title)
+				}
+				body.WriteString("\n")
+			}
+			if parentTitle != "" {
+				fmt.Fprintf(&body, "Parent: %s\n\n", parentTitle)
+			}
+			fmt.Fprintf(&body, "_Generated by specware from .spec/%s/implementation-plan.md_\n", featureName)
+			issues[index].Body = body.String()
+			added = append(added, issues[index])
+		}
+		return added
+	}
+	add(ParsePlanItems(content), "", "", "")
+
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("no open milestones, phases or steps found in implementation-plan.md for feature %s", shortName)
+	}
+	return issues, nil
+}
+
+// RenderIssues renders issues in a tracker format. GitHub and GitLab issues
+// are a JSON array of API payloads carrying their key and parent key; Jira
+// issues are CSV rows for the Jira importer with numeric Issue Id and Parent
+// Id columns.
+func RenderIssues(issues []Issue, format string) ([]byte, error) {
+	switch format {
+	case IssueFormatGitHub:
+		type githubIssue struct {
+			Key    string   `json:"key"`
+			Parent string   `json:"parent,omitempty"`
+			Title  string   `json:"title"`
+			Body   string   `json:"body"`
+			Labels []string `json:"labels"`
+		}
+		payloads := make([]githubIssue, 0, len(issues))
+		for _, issue := range issues {
+			payloads = append(payloads, githubIssue{issue.Key, issue.Parent, issue.Title, issue.Body, issue.Labels})
+		}
+		return marshalIssues(payloads)
+	case IssueFormatGitLab:
+		type gitlabIssue struct {
+			Key         string `json:"key"`
+			Parent      string `json:"parent,omitempty"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Labels      string `json:"labels"`
+		}
+		payloads := make([]gitlabIssue, 0, len(issues))
+		for _, issue := range issues {
+			payloads = append(payloads, gitlabIssue{issue.Key, issue.Parent, issue.Title, issue.Body, strings.Join(issue.Labels, ",")})
+		}
+		return marshalIssues(payloads)
+	case IssueFormatJiraCSV:
+		return renderJiraCSV(issues)
+	default:
+		return nil, fmt.Errorf("unsupported issue format %q (expected github, gitlab or jira-csv)", format)
+	}
+}
+
+// marshalIssues encodes issue payloads as indented JSON without escaping
+// the HTML characters that are common in code snippets
+func marshalIssues(payloads interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payloads); err != nil {
+		return nil, fmt.Errorf("failed to marshal issues: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// jiraIssueTypes maps plan item kinds to Jira issue types
+var jiraIssueTypes = map[string]string{
+	PlanItemMilestone: "Epic",
+	PlanItemPhase:     "Story",
+	PlanItemStep:      "Sub-task",
+}
+
+// jiraIssueType returns the Jira issue type of an issue. Jira only allows
+// sub-tasks under standard issues, so steps outside a phase become tasks.
+func jiraIssueType(issue Issue, kinds map[string]string) string {
+	if issue.Kind == PlanItemStep && kinds[issue.Parent] != PlanItemPhase {
+		return "Task"
+	}
+	return jiraIssueTypes[issue.Kind]
+}
+
+// renderJiraCSV renders issues for the Jira CSV importer. Jira labels cannot
+// contain spaces, and multiple labels are given as repeated Labels columns.
+func renderJiraCSV(issues []Issue) ([]byte, error) {
+	ids := make(map[string]string)
+	kinds := make(map[string]string)
+	maxLabels := 0
+	for i, issue := range issues {
+		ids[issue.Key] = strconv.Itoa(i + 1)
+		kinds[issue.Key] = issue.Kind
+		if len(issue.Labels) > maxLabels {
+			maxLabels = len(issue.Labels)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"Issue Id", "Parent Id", "Issue Type", "Summary", "Description"}
+	for i := 0; i < maxLabels; i++ {
+		header = append(header, "Labels")
+	}
+	w.Write(header)
+
+	for _, issue := range issues {
+		row := []string{ids[issue.Key], ids[issue.Parent], jiraIssueType(issue, kinds), issue.Title, issue.Body}
+		for i := 0; i < maxLabels; i++ {
+			label := ""
+			if i < len(issue.Labels) {
+				label = strings.Join(strings.Fields(issue.Labels[i]), "-")
+			}
+			row = append(row, label)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportIssues renders a feature's implementation plan as tracker issues
+func ExportIssues(targetDir, shortName, format string, opts IssueOptions) ([]byte, error) {
+	if _, err := RenderIssues(nil, format); err != nil {
+		return nil, err
+	}
+	issues, err := BuildIssues(targetDir, shortName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return RenderIssues(issues, format)
+}
@@ -0,0 +1,137 @@
+package spec_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Issue export", func() {
+	var tempDir string
+
+	const plan = "# Implementation Plan\n\n## Implementation\n\n### Milestone 1: Accounts\nUser accounts end to end\n\n#### Phase 1: Storage\n\n- [x] Step 1: Schema\n- [ ] Step 2: Repository\n\n```go\nfunc Save(u *User) error\n```\n\n#### Phase 2: API\n\n- [ ] Step 3: Handlers\n\n## Testing Strategy\n\nUnit tests only.\n"
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-issues-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		planPath := filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md")
+		Expect(os.WriteFile(planPath, []byte(plan), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("parses milestones, phases and steps with their bodies", func() {
+		items := spec.ParsePlanItems([]byte(plan))
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Title).To(Equal("Milestone 1: Accounts"))
+		Expect(items[0].Body).To(Equal("User accounts end to end"))
+		Expect(items[0].Children).To(HaveLen(2))
+
+		storage := items[0].Children[0]
+		Expect(storage.Kind).To(Equal(spec.PlanItemPhase))
+		Expect(storage.Children).To(HaveLen(2))
+		Expect(storage.Children[0].Done).To(BeTrue())
+		Expect(storage.Children[1].Body).To(Equal("```go\nfunc Save(u *User) error\n```"))
+		Expect(items[0].Children[1].Children[0].Body).To(BeEmpty())
+	})
+
+	It("returns steps at the top level when a plan has no milestones or phases", func() {
+		items := spec.ParsePlanItems([]byte("# Plan\n\n- [ ] One\n- [ ] Two\n"))
+		Expect(items).To(HaveLen(2))
+		Expect(items[1].Kind).To(Equal(spec.PlanItemStep))
+	})
+
+	It("links parents and children and skips finished steps", func() {
+		issues, err := spec.BuildIssues(tempDir, "user-auth", spec.IssueOptions{Labels: []string{"backend"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		var keys []string
+		for _, issue := range issues {
+			keys = append(keys, issue.Key)
+		}
+		Expect(keys).To(Equal([]string{
+			"001-user-auth/milestone-1",
+			"001-user-auth/milestone-1/phase-1",
+			"001-user-auth/milestone-1/phase-1/step-1",
+			"001-user-auth/milestone-1/phase-2",
+			"001-user-auth/milestone-1/phase-2/step-1",
+		}))
+
+		Expect(issues[0].Children).To(Equal([]string{"001-user-auth/milestone-1/phase-1", "001-user-auth/milestone-1/phase-2"}))
+		Expect(issues[0].Labels).To(Equal([]string{"specware", "backend", "spec:001-user-auth", "milestone"}))
+		Expect(issues[2].Title).To(Equal("[001-user-auth] Step 2: Repository"))
+		Expect(issues[2].Parent).To(Equal("001-user-auth/milestone-1/phase-1"))
+		Expect(issues[2].Body).To(ContainSubstring("func Save(u *User) error"))
+		Expect(issues[2].Body).To(ContainSubstring("Parent: [001-user-auth] Phase 1: Storage"))
+
+		issues, err = spec.BuildIssues(tempDir, "user-auth", spec.IssueOptions{IncludeDone: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(6))
+	})
+
+	It("renders GitHub and GitLab payloads", func() {
+		data, err := spec.ExportIssues(tempDir, "user-auth", spec.IssueFormatGitHub, spec.IssueOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var github []map[string]interface{}
+		Expect(json.Unmarshal(data, &github)).To(Succeed())
+		Expect(github).To(HaveLen(5))
+		Expect(github[0]).To(HaveKey("body"))
+		Expect(github[0]).NotTo(HaveKey("parent"))
+
+		data, err = spec.ExportIssues(tempDir, "user-auth", spec.IssueFormatGitLab, spec.IssueOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var gitlab []map[string]interface{}
+		Expect(json.Unmarshal(data, &gitlab)).To(Succeed())
+		Expect(gitlab[1]["labels"]).To(Equal("specware,spec:001-user-auth,phase"))
+		Expect(gitlab[1]["parent"]).To(Equal("001-user-auth/milestone-1"))
+	})
+
+	It("renders Jira CSV with numeric parent ids", func() {
+		data, err := spec.ExportIssues(tempDir, "user-auth", spec.IssueFormatJiraCSV, spec.IssueOptions{Labels: []string{"my team"}})
+		Expect(err).NotTo(HaveOccurred())
+		rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(HaveLen(6))
+		Expect(rows[0][:5]).To(Equal([]string{"Issue Id", "Parent Id", "Issue Type", "Summary", "Description"}))
+		Expect(rows[1][:3]).To(Equal([]string{"1", "", "Epic"}))
+		Expect(rows[3][:3]).To(Equal([]string{"3", "2", "Sub-task"}))
+		Expect(rows[1]).To(ContainElement("my-team"))
+	})
+
+	It("exports steps outside a phase as Jira tasks", func() {
+		planPath := filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md")
+		content := "# Implementation Plan\n\n### Milestone 1: Accounts\n\n- [ ] Step 1: Schema\n\n#### Phase 1: API\n\n- [ ] Step 2: Handlers\n"
+		Expect(os.WriteFile(planPath, []byte(content), 0644)).To(Succeed())
+
+		data, err := spec.ExportIssues(tempDir, "user-auth", spec.IssueFormatJiraCSV, spec.IssueOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(HaveLen(5))
+		Expect(rows[2][:3]).To(Equal([]string{"2", "1", "Task"}))
+		Expect(rows[3][:3]).To(Equal([]string{"3", "1", "Story"}))
+		Expect(rows[4][:3]).To(Equal([]string{"4", "3", "Sub-task"}))
+	})
+
+	It("rejects unknown formats", func() {
+		_, err := spec.ExportIssues(tempDir, "user-auth", "trello", spec.IssueOptions{})
+		Expect(err).To(MatchError(ContainSubstring("unsupported issue format")))
+	})
+})
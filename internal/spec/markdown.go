@@ -0,0 +1,136 @@
+package spec
+
+import (
+	"strings"
+)
+
+// Heading describes a markdown ATX heading and the byte range of its section.
+// A section runs from the heading line up to the next heading of the same or
+// a higher level, so it includes any nested sub-sections.
+type Heading struct {
+	Level     int    `json:"level"`
+	Title     string `json:"title"`
+	Line      int    `json:"line"`
+	Start     int    `json:"-"`
+	BodyStart int    `json:"-"`
+	End       int    `json:"-"`
+}
+
+// ParseHeadings returns the headings of a markdown document in order.
+// Lines inside fenced code blocks are ignored.
+func ParseHeadings(content []byte) []Heading {
+	var headings []Heading
+	inFence := false
+	fenceMarker := ""
+
+	offset := 0
+	lineNum := 0
+	text := string(content)
+	for offset < len(text) {
+		lineNum++
+		lineEnd := strings.IndexByte(text[offset:], '\n')
+		next := len(text)
+		if lineEnd >= 0 {
+			next = offset + lineEnd + 1
+		}
+		line := strings.TrimRight(text[offset:next], "\r\n")
+
+		trimmed := strings.TrimLeft(line, " ")
+		if marker := fenceOf(trimmed); marker != "" {
+			if !inFence {
+				inFence = true
+				fenceMarker = marker
+			} else if strings.HasPrefix(trimmed, fenceMarker) && strings.TrimSpace(strings.TrimLeft(trimmed, fenceMarker[:1])) == "" {
+				inFence = false
+			}
+		} else if !inFence {
+			if level, title, ok := parseHeadingLine(line); ok {
+				headings = append(headings, Heading{
+					Level:     level,
+					Title:     title,
+					Line:      lineNum,
+					Start:     offset,
+					BodyStart: next,
+				})
+			}
+		}
+		offset = next
+	}
+
+	for i := range headings {
+		headings[i].End = len(text)
+		for j := i + 1; j < len(headings); j++ {
+			if headings[j].Level <= headings[i].Level {
+				headings[i].End = headings[j].Start
+				break
+			}
+		}
+	}
+	return headings
+}
+
+// headingPaths returns the path of each heading: its title preceded by the
+// titles of its parent headings, joined with " > "
+func headingPaths(headings []Heading) []string {
+	paths := make([]string, len(headings))
+	var parents []string
+	var levels []int
+	for i, h := range headings {
+		for len(levels) > 0 && levels[len(levels)-1] >= h.Level {
+			parents = parents[:len(parents)-1]
+			levels = levels[:len(levels)-1]
+		}
+		parents = append(parents, h.Title)
+		levels = append(levels, h.Level)
+		paths[i] = strings.Join(parents, " > ")
+	}
+	return paths
+}
+
+// fenceOf returns the fence marker if the line opens or closes a code block
+func fenceOf(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// parseHeadingLine parses an ATX heading line such as "## Title"
+func parseHeadingLine(line string) (int, string, bool) {
+	if strings.HasPrefix(line, "    ") {
+		return 0, "", false
+	}
+	trimmed := strings.TrimLeft(line, " ")
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(rest)
+	title = strings.TrimSpace(strings.TrimRight(title, "#"))
+	return level, title, true
+}
+
+// headingAt returns the innermost heading whose section contains the offset
+func headingAt(headings []Heading, offset int) (Heading, bool) {
+	var found Heading
+	ok := false
+	for _, h := range headings {
+		if h.Start > offset {
+			break
+		}
+		if offset < h.End {
+			found = h
+			ok = true
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,397 @@
+package spec
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown block kinds produced by parseMarkdownBlocks
+const (
+	blockHeading = iota
+	blockParagraph
+	blockList
+	blockCode
+	blockQuote
+	blockRule
+	blockTable
+)
+
+// mdBlock is a block-level markdown element. Only the fields relevant to the
+// block kind are populated.
+type mdBlock struct {
+	Kind     int
+	Level    int
+	Text     string
+	Lang     string
+	Items    []mdListItem
+	Children []mdBlock
+	Header   []string
+	Rows     [][]string
+}
+
+// mdListItem is a single list item. Nested lists are represented by Indent.
+type mdListItem struct {
+	Indent  int
+	Ordered bool
+	Task    bool
+	Checked bool
+	Text    string
+}
+
+// mdSpan is a run of inline text with uniform formatting
+type mdSpan struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Code   bool
+	Link   string
+}
+
+var (
+	mdListItemPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTaskPattern      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdRulePattern      = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	mdTableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	mdSlugStripPattern = regexp.MustCompile(`[^a-z0-9 _-]`)
+)
+
+// parseMarkdownBlocks parses the subset of markdown used by spec templates:
+// ATX headings, paragraphs, bullet/numbered/task lists, fenced code blocks,
+// block quotes, horizontal rules and pipe tables
+func parseMarkdownBlocks(content string) []mdBlock {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var blocks []mdBlock
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, mdBlock{Kind: blockParagraph, Text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case fenceOf(strings.TrimLeft(line, " ")) != "":
+			flush()
+			marker := fenceOf(strings.TrimLeft(line, " "))
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, marker[:1]))
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), marker) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, mdBlock{Kind: blockCode, Lang: lang, Text: strings.Join(code, "\n")})
+
+		case isHeadingLine(line):
+			flush()
+			level, title, _ := parseHeadingLine(line)
+			blocks = append(blocks, mdBlock{Kind: blockHeading, Level: level, Text: title})
+
+		case mdRulePattern.MatchString(line):
+			flush()
+			blocks = append(blocks, mdBlock{Kind: blockRule})
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			i--
+			blocks = append(blocks, mdBlock{Kind: blockQuote, Children: parseMarkdownBlocks(strings.Join(quoted, "\n"))})
+
+		case mdListItemPattern.MatchString(line):
+			flush()
+			var items []mdListItem
+			for ; i < len(lines); i++ {
+				current := lines[i]
+				if m := mdListItemPattern.FindStringSubmatch(current); m != nil {
+					item := mdListItem{
+						Indent:  len(strings.ReplaceAll(m[1], "\t", "  ")) / 2,
+						Ordered: m[2] != "-" && m[2] != "*" && m[2] != "+",
+						Text:    m[3],
+					}
+					if t := mdTaskPattern.FindStringSubmatch(item.Text); t != nil {
+						item.Task = true
+						item.Checked = t[1] != " "
+						item.Text = t[2]
+					}
+					items = append(items, item)
+					continue
+				}
+				// Indented continuation lines belong to the previous item
+				if strings.TrimSpace(current) != "" && strings.HasPrefix(current, "  ") && fenceOf(strings.TrimSpace(current)) == "" {
+					items[len(items)-1].Text += " " + strings.TrimSpace(current)
+					continue
+				}
+				break
+			}
+			i--
+			blocks = append(blocks, mdBlock{Kind: blockList, Items: items})
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableSepPattern.MatchString(lines[i+1]):
+			flush()
+			table := mdBlock{Kind: blockTable, Header: splitTableRow(trimmed)}
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				table.Rows = append(table.Rows, splitTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			blocks = append(blocks, table)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// isHeadingLine reports whether a line is an ATX heading
+func isHeadingLine(line string) bool {
+	_, _, ok := parseHeadingLine(line)
+	return ok
+}
+
+// splitTableRow splits a pipe table row into trimmed cells
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// parseInline splits inline markdown into formatted spans
+func parseInline(text string) []mdSpan {
+	var spans []mdSpan
+	parseInlineInto(text, mdSpan{}, &spans)
+	return spans
+}
+
+// parseInlineInto parses text, inheriting the formatting of style
+func parseInlineInto(text string, style mdSpan, spans *[]mdSpan) {
+	var plain strings.Builder
+	emit := func() {
+		if plain.Len() > 0 {
+			span := style
+			span.Text = plain.String()
+			*spans = append(*spans, span)
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()#+-.!|", text[i+1]) >= 0:
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				emit()
+				span := style
+				span.Code = true
+				span.Text = text[i+1 : i+1+end]
+				*spans = append(*spans, span)
+				i += end + 2
+				continue
+			}
+
+		case (c == '*' || c == '_') && i+1 < len(text) && text[i+1] == c:
+			marker := text[i : i+2]
+			if end := strings.Index(text[i+2:], marker); end > 0 {
+				emit()
+				inner := style
+				inner.Bold = true
+				parseInlineInto(text[i+2:i+2+end], inner, spans)
+				i += end + 4
+				continue
+			}
+
+		case c == '*' || (c == '_' && (i == 0 || !isWordByte(text[i-1]))):
+			if end := strings.IndexByte(text[i+1:], c); end > 0 && text[i+1] != ' ' {
+				closing := i + 1 + end
+				if c != '_' || closing+1 >= len(text) || !isWordByte(text[closing+1]) {
+					emit()
+					inner := style
+					inner.Italic = true
+					parseInlineInto(text[i+1:closing], inner, spans)
+					i = closing + 1
+					continue
+				}
+			}
+
+		case c == '[':
+			if closeText := strings.Index(text[i:], "]("); closeText > 0 {
+				if closeURL := strings.IndexByte(text[i+closeText+2:], ')'); closeURL >= 0 {
+					emit()
+					inner := style
+					inner.Link = text[i+closeText+2 : i+closeText+2+closeURL]
+					parseInlineInto(text[i+1:i+closeText], inner, spans)
+					i += closeText + 3 + closeURL
+					continue
+				}
+			}
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	emit()
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// headingSlugger produces unique GitHub-style anchors for headings
+type headingSlugger struct {
+	seen map[string]int
+}
+
+// newHeadingSlugger returns an empty slugger
+func newHeadingSlugger() *headingSlugger {
+	return &headingSlugger{seen: make(map[string]int)}
+}
+
+// slug returns a unique anchor for a heading title
+func (s *headingSlugger) slug(title string) string {
+	base := strings.ToLower(strings.TrimSpace(title))
+	base = mdSlugStripPattern.ReplaceAllString(base, "")
+	base = strings.ReplaceAll(base, " ", "-")
+	if base == "" {
+		base = "section"
+	}
+	count := s.seen[base]
+	s.seen[base] = count + 1
+	if count == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, count)
+}
+
+// renderInlineHTML renders inline markdown as HTML
+func renderInlineHTML(text string) string {
+	var b strings.Builder
+	for _, span := range parseInline(text) {
+		content := html.EscapeString(span.Text)
+		if span.Code {
+			content = "<code>" + content + "</code>"
+		}
+		if span.Italic {
+			content = "<em>" + content + "</em>"
+		}
+		if span.Bold {
+			content = "<strong>" + content + "</strong>"
+		}
+		if span.Link != "" && !strings.HasPrefix(strings.ToLower(strings.TrimSpace(span.Link)), "javascript:") {
+			content = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(span.Link), content)
+		}
+		b.WriteString(content)
+	}
+	return b.String()
+}
+
+// RenderMarkdownHTML renders markdown as an HTML fragment
+func RenderMarkdownHTML(content string) string {
+	return renderBlocksHTML(parseMarkdownBlocks(content), newHeadingSlugger())
+}
+
+// renderBlocksHTML renders parsed blocks, assigning heading anchors from slugger
+func renderBlocksHTML(blocks []mdBlock, slugger *headingSlugger) string {
+	var b strings.Builder
+	for _, block := range blocks {
+		switch block.Kind {
+		case blockHeading:
+			fmt.Fprintf(&b, "<h%d id=\"%s\">%s</h%d>\n", block.Level, slugger.slug(block.Text), renderInlineHTML(block.Text), block.Level)
+		case blockParagraph:
+			fmt.Fprintf(&b, "<p>%s</p>\n", renderInlineHTML(block.Text))
+		case blockCode:
+			class := ""
+			if block.Lang != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(block.Lang))
+			}
+			fmt.Fprintf(&b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(block.Text))
+		case blockQuote:
+			fmt.Fprintf(&b, "<blockquote>\n%s</blockquote>\n", renderBlocksHTML(block.Children, slugger))
+		case blockRule:
+			b.WriteString("<hr>\n")
+		case blockList:
+			renderListHTML(&b, block.Items)
+		case blockTable:
+			b.WriteString("<table>\n<thead><tr>")
+			for _, cell := range block.Header {
+				fmt.Fprintf(&b, "<th>%s</th>", renderInlineHTML(cell))
+			}
+			b.WriteString("</tr></thead>\n<tbody>\n")
+			for _, row := range block.Rows {
+				b.WriteString("<tr>")
+				for _, cell := range row {
+					fmt.Fprintf(&b, "<td>%s</td>", renderInlineHTML(cell))
+				}
+				b.WriteString("</tr>\n")
+			}
+			b.WriteString("</tbody>\n</table>\n")
+		}
+	}
+	return b.String()
+}
+
+// renderListHTML renders flat list items as nested HTML lists using their indent
+func renderListHTML(b *strings.Builder, items []mdListItem) {
+	var open []string
+	depth := -1
+	for _, item := range items {
+		tag := "ul"
+		if item.Ordered {
+			tag = "ol"
+		}
+		indent := item.Indent
+		if indent > depth+1 {
+			indent = depth + 1
+		}
+		for depth > indent {
+			fmt.Fprintf(b, "</li>\n</%s>\n", open[len(open)-1])
+			open = open[:len(open)-1]
+			depth--
+		}
+		if depth == indent {
+			b.WriteString("</li>\n")
+		} else {
+			fmt.Fprintf(b, "<%s>\n", tag)
+			open = append(open, tag)
+			depth = indent
+		}
+
+		b.WriteString("<li>")
+		if item.Task {
+			checked := ""
+			if item.Checked {
+				checked = " checked"
+			}
+			fmt.Fprintf(b, "<input type=\"checkbox\" disabled%s> ", checked)
+		}
+		b.WriteString(renderInlineHTML(item.Text))
+	}
+	for len(open) > 0 {
+		fmt.Fprintf(b, "</li>\n</%s>\n", open[len(open)-1])
+		open = open[:len(open)-1]
+	}
+}
@@ -0,0 +1,62 @@
+package spec_test
+
+import (
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Markdown", func() {
+	Describe("ParseHeadings", func() {
+		const document = "# Title\n\nIntro\n\n## First\nBody one\n\n### Nested\nNested body\n\n## Second\n```\n# not a heading\n```\nBody two\n"
+
+		It("should find headings with levels and line numbers", func() {
+			headings := spec.ParseHeadings([]byte(document))
+
+			var titles []string
+			for _, h := range headings {
+				titles = append(titles, h.Title)
+			}
+			Expect(titles).To(Equal([]string{"Title", "First", "Nested", "Second"}))
+			Expect(headings[1].Level).To(Equal(2))
+			Expect(headings[1].Line).To(Equal(5))
+		})
+
+		It("should include nested sections in a section's range", func() {
+			headings := spec.ParseHeadings([]byte(document))
+			first := headings[1]
+
+			section := document[first.Start:first.End]
+			Expect(section).To(HavePrefix("## First\n"))
+			Expect(section).To(ContainSubstring("Nested body"))
+			Expect(section).NotTo(ContainSubstring("## Second"))
+		})
+
+		It("should ignore headings inside fenced code blocks", func() {
+			headings := spec.ParseHeadings([]byte(document))
+			last := headings[len(headings)-1]
+
+			Expect(last.Title).To(Equal("Second"))
+			Expect(document[last.Start:last.End]).To(ContainSubstring("# not a heading"))
+		})
+	})
+
+	Describe("RenderMarkdownHTML", func() {
+		It("should render inline formatting and escape HTML", func() {
+			output := spec.RenderMarkdownHTML("Some **bold**, *italic*, `code <b>` and [a link](https://example.com) in snake_case_name.")
+			Expect(output).To(Equal(`<p>Some <strong>bold</strong>, <em>italic</em>, <code>code &lt;b&gt;</code> and <a href="https://example.com">a link</a> in snake_case_name.</p>` + "\n"))
+		})
+
+		It("should render nested and task lists", func() {
+			output := spec.RenderMarkdownHTML("- [x] Done\n  - Child\n- [ ] Todo\n")
+			Expect(output).To(Equal("<ul>\n<li><input type=\"checkbox\" disabled checked> Done<ul>\n<li>Child</li>\n</ul>\n</li>\n<li><input type=\"checkbox\" disabled> Todo</li>\n</ul>\n"))
+		})
+
+		It("should render code blocks and tables", func() {
+			output := spec.RenderMarkdownHTML("```go\nfmt.Println(\"<hi>\")\n```\n\n| A | B |\n|---|---|\n| 1 | 2 |\n")
+			Expect(output).To(ContainSubstring(`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>`))
+			Expect(output).To(ContainSubstring("<tr><td>1</td><td>2</td></tr>"))
+		})
+	})
+})
@@ -0,0 +1,788 @@
+package spec
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PackManifestFileName is the manifest at the root of a template pack
+const PackManifestFileName = "pack.json"
+
+// packInstalledFileName records the files a pack installed outside its own
+// directory, so removing or upgrading the pack can delete them. It is written
+// by InstallPack and never copied from the pack source.
+const packInstalledFileName = ".installed.json"
+
+// packReplacedDir keeps the files a pack replaced in .claude, so removing the
+// pack can restore them
+const packReplacedDir = ".replaced"
+
+// packFileKinds are the .claude sub-directories packs install files into
+var packFileKinds = []string{"commands", "agents"}
+
+// Pack install locations
+const (
+	// PackScopeProject installs into .spec/packs, commands and agents into .claude
+	PackScopeProject = "project"
+	// PackScopeUser installs into the user config directory, commands and
+	// agents into ~/.claude, so one installation serves every repository
+	PackScopeUser = "user"
+)
+
+// Pack states reported by ListPacks
+const (
+	// PackActive is installed with the version pinned in .spec/config.json
+	PackActive = "active"
+	// PackUnpinned is installed but not pinned, so it is not used
+	PackUnpinned = "unpinned"
+	// PackMismatch is installed with a different version than pinned
+	PackMismatch = "version-mismatch"
+	// PackMissing is pinned but not installed
+	PackMissing = "missing"
+)
+
+var (
+	packNamePattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	packVersionPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z.+_-]*$`)
+)
+
+// PackManifest is the pack.json of a template pack. Next to it, a pack may
+// contain templates/ (laid out like .spec/templates), commands/ and agents/
+// (installed into .claude), and config.json with configuration defaults.
+type PackManifest struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PackInfo describes an installed or pinned pack
+type PackInfo struct {
+	PackManifest
+	Scope  string `json:"scope,omitempty"`
+	Dir    string `json:"dir,omitempty"`
+	Pinned string `json:"pinned,omitempty"`
+	State  string `json:"state"`
+	// Files are the commands and agents the pack installed
+	Files []string `json:"files,omitempty"`
+	// Replaced are the files the pack replaced, restored when it is removed
+	Replaced []string `json:"replaced,omitempty"`
+	// Config are the config keys the pack's defaults filled in on install,
+	// either because the project did not set them or because it kept the
+	// built-in default
+	Config []string `json:"config,omitempty"`
+	// KeptConfig are the config keys the project sets to a value other than
+	// the pack's default
+	KeptConfig []string `json:"kept_config,omitempty"`
+}
+
+// PackInstallOptions controls where a pack is installed
+type PackInstallOptions struct {
+	// Scope is PackScopeProject (the default) or PackScopeUser
+	Scope string
+	// Force replaces commands and agents the pack did not install; they are
+	// restored when the pack is removed
+	Force bool
+	// Upgrade allows installing a version other than the one pinned in
+	// .spec/config.json, moving the pin
+	Upgrade bool
+}
+
+// packInstallation records what a pack installed outside its own directory
+type packInstallation struct {
+	Files []packInstalledFile `json:"files"`
+	// Config maps the dotted config keys the pack's defaults filled in to
+	// their values
+	Config map[string]packConfigValue `json:"config,omitempty"`
+}
+
+// packConfigValue is a config value filled in by a pack
+type packConfigValue struct {
+	Value interface{} `json:"value"`
+	// Replaced is set if the pack replaced the built-in default, which is
+	// restored when the pack is removed
+	Replaced bool `json:"replaced,omitempty"`
+}
+
+// packInstalledFile is a command or agent installed by a pack
+type packInstalledFile struct {
+	// Path is relative to the .claude directory
+	Path string `json:"path"`
+	// Replaced is set if the pack replaced an existing file, which is kept in
+	// the pack's .replaced directory
+	Replaced bool `json:"replaced,omitempty"`
+}
+
+// owns reports whether the installation includes a file
+func (p packInstallation) owns(path string) bool {
+	for _, file := range p.Files {
+		if file.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// packsDir returns the directory packs of a scope are installed to
+func packsDir(targetDir, scope string) (string, error) {
+	switch scope {
+	case "", PackScopeProject:
+		return filepath.Join(targetDir, ".spec", "packs"), nil
+	case PackScopeUser:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find user config directory: %w", err)
+		}
+		return filepath.Join(dir, "specware", "packs"), nil
+	default:
+		return "", fmt.Errorf("unknown pack scope %q (expected %s or %s)", scope, PackScopeProject, PackScopeUser)
+	}
+}
+
+// claudeDir returns the .claude directory commands and agents of a scope are
+// installed to
+func claudeDir(targetDir, scope string) (string, error) {
+	if scope != PackScopeUser {
+		return filepath.Join(targetDir, ".claude"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude"), nil
+}
+
+// readPackManifest reads and validates the pack.json in a directory
+func readPackManifest(dir string) (PackManifest, error) {
+	var manifest PackManifest
+	data, err := os.ReadFile(filepath.Join(dir, PackManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, fmt.Errorf("%s not found in %s", PackManifestFileName, dir)
+		}
+		return manifest, fmt.Errorf("failed to read %s: %w", PackManifestFileName, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse %s: %w", PackManifestFileName, err)
+	}
+	if !packNamePattern.MatchString(manifest.Name) {
+		return manifest, fmt.Errorf("invalid pack name %q: use lowercase letters, numbers, hyphens and underscores", manifest.Name)
+	}
+	if !packVersionPattern.MatchString(manifest.Version) {
+		return manifest, fmt.Errorf("invalid version %q for pack %s", manifest.Version, manifest.Name)
+	}
+	return manifest, nil
+}
+
+// packRoot returns the directory holding pack.json: the source itself, or
+// its only sub-directory as archives often wrap their content in one
+func packRoot(dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, PackManifestFileName)); err == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read pack: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if _, err := os.Stat(filepath.Join(dir, entries[0].Name(), PackManifestFileName)); err == nil {
+			return filepath.Join(dir, entries[0].Name()), nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in %s", PackManifestFileName, dir)
+}
+
+// extractPackArchive extracts a .tar.gz archive into dir. Entries escaping
+// dir and anything but regular files and directories are rejected.
+func extractPackArchive(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open pack archive: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read pack archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read pack archive: %w", err)
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("pack archive entry %q is outside the pack", header.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+		default:
+			return fmt.Errorf("pack archive entry %q is not a regular file or directory", header.Name)
+		}
+	}
+}
+
+// copyTree copies the regular files of a pack's directory tree, except for an
+// installation record and replaced files the pack may contain
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if rel == packReplacedDir {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() || rel == packInstalledFileName {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
+
+// InstallPack installs a template pack from a directory or .tar.gz archive.
+// The pack is copied to the scope's packs directory, replacing any installed
+// version, and its commands and agents are copied into .claude. Inside a
+// project, the version is pinned in .spec/config.json, and a different pinned
+// version is only replaced with opts.Upgrade. The pack's config.json fills in
+// settings missing from the project configuration.
+func InstallPack(targetDir, source string, opts PackInstallOptions) (PackInfo, error) {
+	packs, err := packsDir(targetDir, opts.Scope)
+	if err != nil {
+		return PackInfo{}, err
+	}
+	scope := opts.Scope
+	if scope == "" {
+		scope = PackScopeProject
+	}
+	specDir := filepath.Join(targetDir, ".spec")
+	_, statErr := os.Stat(specDir)
+	inProject := statErr == nil
+	if scope == PackScopeProject && !inProject {
+		return PackInfo{}, fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return PackInfo{}, fmt.Errorf("pack source not found: %w", err)
+	}
+	srcDir := source
+	if !info.IsDir() {
+		if !strings.HasSuffix(source, ".tar.gz") && !strings.HasSuffix(source, ".tgz") {
+			return PackInfo{}, fmt.Errorf("pack source must be a directory or a .tar.gz archive")
+		}
+		tmp, err := os.MkdirTemp("", "specware-pack-*")
+		if err != nil {
+			return PackInfo{}, err
+		}
+		defer os.RemoveAll(tmp)
+		if err := extractPackArchive(source, tmp); err != nil {
+			return PackInfo{}, err
+		}
+		srcDir = tmp
+	}
+	srcDir, err = packRoot(srcDir)
+	if err != nil {
+		return PackInfo{}, err
+	}
+	manifest, err := readPackManifest(srcDir)
+	if err != nil {
+		return PackInfo{}, err
+	}
+	if inProject {
+		pinned, err := LoadConfig(targetDir)
+		if err != nil {
+			return PackInfo{}, err
+		}
+		if version, ok := pinned.Packs[manifest.Name]; ok && version != manifest.Version && !opts.Upgrade {
+			return PackInfo{}, fmt.Errorf("pack %s is pinned to version %s in .spec/config.json; use --upgrade to install version %s",
+				manifest.Name, version, manifest.Version)
+		}
+	}
+
+	// Refuse to replace commands and agents the pack did not install
+	claude, err := claudeDir(targetDir, scope)
+	if err != nil {
+		return PackInfo{}, err
+	}
+	dest := filepath.Join(packs, manifest.Name)
+	previous, err := readPackInstallation(dest)
+	if err != nil {
+		return PackInfo{}, err
+	}
+	files := packFiles(srcDir)
+	var conflicts []string
+	for _, file := range files {
+		target := filepath.Join(claude, file)
+		if _, err := os.Stat(target); err == nil && !previous.owns(file) {
+			conflicts = append(conflicts, target)
+		}
+	}
+	if len(conflicts) > 0 && !opts.Force {
+		return PackInfo{}, fmt.Errorf("pack %s would replace files it did not install:\n  %s\nuse --force to replace them until the pack is removed",
+			manifest.Name, strings.Join(conflicts, "\n  "))
+	}
+
+	// Fill in the pack's configuration defaults, replacing those of an
+	// installed version, and validate them before changing anything
+	var config Config
+	var filled map[string]packConfigValue
+	var kept []string
+	var doc map[string]interface{}
+	if inProject {
+		if doc, err = readConfigDocument(targetDir); err != nil {
+			return PackInfo{}, err
+		}
+		unfillConfigDefaults(doc, previous.Config)
+		if data, err := os.ReadFile(filepath.Join(srcDir, "config.json")); err == nil {
+			var defaults map[string]interface{}
+			if err := json.Unmarshal(data, &defaults); err != nil {
+				return PackInfo{}, fmt.Errorf("failed to parse config.json of pack %s: %w", manifest.Name, err)
+			}
+			delete(defaults, "packs")
+			filled = make(map[string]packConfigValue)
+			kept = fillConfigDefaults(doc, defaults, defaultConfigDocument(), "", filled)
+		}
+		if config, err = decodeConfigDocument(doc); err != nil {
+			return PackInfo{}, fmt.Errorf("config.json of pack %s: %w", manifest.Name, err)
+		}
+		if err := validateConfig(config); err != nil {
+			return PackInfo{}, fmt.Errorf("config.json of pack %s: %w", manifest.Name, err)
+		}
+	}
+
+	// Replace any installed version, restoring the files it replaced
+	if err := removePackFiles(dest, claude); err != nil {
+		return PackInfo{}, err
+	}
+	if err := os.MkdirAll(packs, 0755); err != nil {
+		return PackInfo{}, fmt.Errorf("failed to create packs directory: %w", err)
+	}
+	if err := copyTree(srcDir, dest); err != nil {
+		return PackInfo{}, fmt.Errorf("failed to install pack: %w", err)
+	}
+
+	installation := packInstallation{Config: filled}
+	var installedPaths, replacedPaths []string
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(dest, file))
+		if err != nil {
+			return PackInfo{}, err
+		}
+		target := filepath.Join(claude, file)
+		installed := packInstalledFile{Path: file}
+		if existing, err := os.ReadFile(target); err == nil {
+			backup := filepath.Join(dest, packReplacedDir, file)
+			if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+				return PackInfo{}, err
+			}
+			if err := os.WriteFile(backup, existing, 0644); err != nil {
+				return PackInfo{}, fmt.Errorf("failed to keep %s: %w", target, err)
+			}
+			installed.Replaced = true
+			replacedPaths = append(replacedPaths, target)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return PackInfo{}, err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return PackInfo{}, fmt.Errorf("failed to install %s: %w", file, err)
+		}
+		installation.Files = append(installation.Files, installed)
+		installedPaths = append(installedPaths, target)
+	}
+	data, err := json.MarshalIndent(installation, "", "  ")
+	if err != nil {
+		return PackInfo{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dest, packInstalledFileName), data, 0644); err != nil {
+		return PackInfo{}, fmt.Errorf("failed to record installed files: %w", err)
+	}
+
+	result := PackInfo{PackManifest: manifest, Scope: scope, Dir: dest, State: PackUnpinned, Files: installedPaths, Replaced: replacedPaths}
+	if inProject {
+		pins, _ := doc["packs"].(map[string]interface{})
+		if pins == nil {
+			pins = make(map[string]interface{})
+			doc["packs"] = pins
+		}
+		pins[manifest.Name] = manifest.Version
+		if err := writeConfigDocument(targetDir, doc); err != nil {
+			return PackInfo{}, err
+		}
+		result.Pinned = manifest.Version
+		result.State = PackActive
+		for key := range filled {
+			result.Config = append(result.Config, key)
+		}
+		sort.Strings(result.Config)
+		result.KeptConfig = kept
+	}
+	return result, nil
+}
+
+// fillConfigDefaults copies the values of defaults into a config document
+// where it does not set them or keeps the built-in default from builtIn,
+// recording them in filled by dotted key. It returns the keys the document
+// sets to a different value of its own.
+func fillConfigDefaults(doc, defaults, builtIn map[string]interface{}, prefix string, filled map[string]packConfigValue) []string {
+	var names []string
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var kept []string
+	for _, name := range names {
+		key := prefix + name
+		value := defaults[name]
+		current, ok := doc[name]
+		_, valueIsObject := value.(map[string]interface{})
+		if !ok && valueIsObject && prefix == "" {
+			// Fill config sections key by key, so removing the pack only
+			// removes the settings it filled in
+			current, ok = make(map[string]interface{}), true
+			doc[name] = current
+		}
+		if !ok {
+			doc[name] = value
+			filled[key] = packConfigValue{Value: value}
+			continue
+		}
+		builtInValue, hasBuiltIn := builtIn[name]
+		currentObject, currentIsObject := current.(map[string]interface{})
+		valueObject, valueIsObject := value.(map[string]interface{})
+		switch {
+		case currentIsObject && valueIsObject:
+			builtInObject, _ := builtInValue.(map[string]interface{})
+			kept = append(kept, fillConfigDefaults(currentObject, valueObject, builtInObject, key+".", filled)...)
+		case reflect.DeepEqual(current, value):
+		case hasBuiltIn && reflect.DeepEqual(current, builtInValue):
+			// init writes every built-in default, which is not a choice
+			// of the project
+			doc[name] = value
+			filled[key] = packConfigValue{Value: value, Replaced: true}
+		default:
+			kept = append(kept, key)
+		}
+	}
+	return kept
+}
+
+// unfillConfigDefaults removes the defaults a pack filled in from a config
+// document, or restores the built-in defaults they replaced, unless they were
+// changed since
+func unfillConfigDefaults(doc map[string]interface{}, filled map[string]packConfigValue) {
+	builtIn := defaultConfigDocument()
+	for key, filledValue := range filled {
+		path := strings.Split(key, ".")
+		current, ok := lookupConfigValue(doc, path)
+		if !ok || !reflect.DeepEqual(current, filledValue.Value) {
+			continue
+		}
+		parent, _ := lookupConfigValue(doc, path[:len(path)-1])
+		if builtInValue, ok := lookupConfigValue(builtIn, path); ok && filledValue.Replaced {
+			parent.(map[string]interface{})[path[len(path)-1]] = builtInValue
+		} else {
+			delete(parent.(map[string]interface{}), path[len(path)-1])
+		}
+	}
+}
+
+// packFiles returns the commands and agents of a pack, relative to the
+// .claude directory they are installed into
+func packFiles(dir string) []string {
+	var files []string
+	for _, kind := range packFileKinds {
+		entries, err := os.ReadDir(filepath.Join(dir, kind))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".md") {
+				files = append(files, filepath.Join(kind, entry.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// readPackInstallation reads the record of what an installed pack installed
+// into .claude. Entries outside the commands and agents directories are
+// rejected.
+func readPackInstallation(dir string) (packInstallation, error) {
+	var installation packInstallation
+	data, err := os.ReadFile(filepath.Join(dir, packInstalledFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return installation, nil
+		}
+		return installation, fmt.Errorf("failed to read %s: %w", packInstalledFileName, err)
+	}
+	if err := json.Unmarshal(data, &installation); err != nil {
+		return installation, fmt.Errorf("failed to parse %s: %w", packInstalledFileName, err)
+	}
+	for _, file := range installation.Files {
+		if !isPackFile(file.Path) {
+			return installation, fmt.Errorf("%s of pack %s lists %q, which is not a command or agent", packInstalledFileName, filepath.Base(dir), file.Path)
+		}
+	}
+	return installation, nil
+}
+
+// isPackFile reports whether a path relative to .claude names a file directly
+// inside one of the directories packs install into
+func isPackFile(file string) bool {
+	if filepath.IsAbs(file) || filepath.Clean(file) != file {
+		return false
+	}
+	kind, name := filepath.Split(file)
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, k := range packFileKinds {
+		if kind == k+string(filepath.Separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// removePackFiles removes an installed pack directory and the commands and
+// agents it installed into the claude directory, restoring the files it
+// replaced
+func removePackFiles(dir, claude string) error {
+	installation, err := readPackInstallation(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range installation.Files {
+		path := filepath.Join(claude, file.Path)
+		if file.Replaced {
+			content, err := os.ReadFile(filepath.Join(dir, packReplacedDir, file.Path))
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", path, err)
+			}
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", path, err)
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove pack: %w", err)
+	}
+	return nil
+}
+
+// installedPack reads a pack installed in a scope
+func installedPack(targetDir, scope, name string) (PackInfo, bool) {
+	packs, err := packsDir(targetDir, scope)
+	if err != nil {
+		return PackInfo{}, false
+	}
+	dir := filepath.Join(packs, name)
+	manifest, err := readPackManifest(dir)
+	if err != nil {
+		return PackInfo{}, false
+	}
+	info := PackInfo{PackManifest: manifest, Scope: scope, Dir: dir}
+	if claude, err := claudeDir(targetDir, scope); err == nil {
+		installation, _ := readPackInstallation(dir)
+		for _, file := range installation.Files {
+			info.Files = append(info.Files, filepath.Join(claude, file.Path))
+			if file.Replaced {
+				info.Replaced = append(info.Replaced, filepath.Join(claude, file.Path))
+			}
+		}
+	}
+	return info, true
+}
+
+// ListPacks returns the packs installed for the project or the user and the
+// packs pinned in .spec/config.json, with their state
+func ListPacks(targetDir string) ([]PackInfo, error) {
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []PackInfo
+	seen := make(map[string]bool)
+	for _, scope := range []string{PackScopeProject, PackScopeUser} {
+		dir, err := packsDir(targetDir, scope)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read packs directory: %w", err)
+		}
+		for _, entry := range entries {
+			info, ok := installedPack(targetDir, scope, entry.Name())
+			if !ok || !entry.IsDir() {
+				continue
+			}
+			info.Pinned = config.Packs[info.Name]
+			switch {
+			case info.Pinned == "":
+				info.State = PackUnpinned
+			case info.Pinned == info.Version:
+				info.State = PackActive
+			default:
+				info.State = PackMismatch
+			}
+			seen[info.Name] = true
+			packs = append(packs, info)
+		}
+	}
+	for name, version := range config.Packs {
+		if !seen[name] {
+			packs = append(packs, PackInfo{PackManifest: PackManifest{Name: name}, Pinned: version, State: PackMissing})
+		}
+	}
+	sort.SliceStable(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs, nil
+}
+
+// activePacks returns the packs pinned in .spec/config.json whose pinned
+// version is installed, preferring a project installation over the user's
+func activePacks(targetDir string) []PackInfo {
+	config, err := LoadConfig(targetDir)
+	if err != nil || len(config.Packs) == 0 {
+		return nil
+	}
+	var names []string
+	for name := range config.Packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var active []PackInfo
+	for _, name := range names {
+		for _, scope := range []string{PackScopeProject, PackScopeUser} {
+			if info, ok := installedPack(targetDir, scope, name); ok && info.Version == config.Packs[name] {
+				info.Pinned = config.Packs[name]
+				info.State = PackActive
+				active = append(active, info)
+				break
+			}
+		}
+	}
+	return active
+}
+
+// RemovePack uninstalls a pack and the commands and agents it installed, and
+// removes its pin from .spec/config.json. Without a scope, the project
+// installation is removed if there is one, otherwise the user's.
+func RemovePack(targetDir, name, scope string) (PackInfo, error) {
+	scopes := []string{PackScopeProject, PackScopeUser}
+	if scope != "" {
+		if _, err := packsDir(targetDir, scope); err != nil {
+			return PackInfo{}, err
+		}
+		scopes = []string{scope}
+	}
+
+	var removed PackInfo
+	var installation packInstallation
+	found := false
+	for _, s := range scopes {
+		if info, ok := installedPack(targetDir, s, name); ok {
+			claude, err := claudeDir(targetDir, s)
+			if err != nil {
+				return PackInfo{}, err
+			}
+			if installation, err = readPackInstallation(info.Dir); err != nil {
+				return PackInfo{}, err
+			}
+			if err := removePackFiles(info.Dir, claude); err != nil {
+				return PackInfo{}, err
+			}
+			removed, found = info, true
+			break
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, ".spec")); err == nil {
+		doc, err := readConfigDocument(targetDir)
+		if err != nil {
+			return PackInfo{}, err
+		}
+		unfillConfigDefaults(doc, installation.Config)
+		config, err := decodeConfigDocument(doc)
+		if err != nil {
+			return PackInfo{}, err
+		}
+		pinned, ok := config.Packs[name]
+		if pins, isObject := doc["packs"].(map[string]interface{}); isObject {
+			delete(pins, name)
+			if len(pins) == 0 {
+				delete(doc, "packs")
+			}
+		}
+		if ok || len(installation.Config) > 0 {
+			if err := writeConfigDocument(targetDir, doc); err != nil {
+				return PackInfo{}, err
+			}
+		}
+		if ok && !found {
+			removed = PackInfo{PackManifest: PackManifest{Name: name}, Pinned: pinned, State: PackMissing}
+			found = true
+		}
+	}
+	if !found {
+		return PackInfo{}, fmt.Errorf("pack %s is not installed", name)
+	}
+	return removed, nil
+}
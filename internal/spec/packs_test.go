@@ -0,0 +1,338 @@
+package spec_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Template packs", func() {
+	var (
+		tempDir string
+		packDir string
+		homeDir string
+	)
+
+	writeFile := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-packs-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		homeDir = filepath.Join(tempDir, "home")
+		for _, env := range []string{"HOME", "XDG_CONFIG_HOME"} {
+			previous, set := os.LookupEnv(env)
+			DeferCleanup(func() {
+				if set {
+					os.Setenv(env, previous)
+				} else {
+					os.Unsetenv(env)
+				}
+			})
+		}
+		os.Setenv("HOME", homeDir)
+		os.Setenv("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+
+		packDir = filepath.Join(tempDir, "acme")
+		writeFile(filepath.Join(packDir, "pack.json"), `{"name": "acme", "version": "1.2.0"}`)
+		writeFile(filepath.Join(packDir, "templates", "requirements.md"), "<!-- specware:extends -->\n<!-- specware:add -->\n## Security Review\n")
+		writeFile(filepath.Join(packDir, "templates", "migration", "requirements.md"), "# Migration: [Feature Name]\n")
+		writeFile(filepath.Join(packDir, "commands", "acme-review.md"), "# Review\n")
+		writeFile(filepath.Join(packDir, "agents", "acme-bot.md"), "# Bot\n")
+		writeFile(filepath.Join(packDir, "config.json"), `{
+			"review": {"required_approvals": 2},
+			"artifacts": {"threat-model": {"context": true}},
+			"packs": {"other": "9.9"}
+		}`)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("installs a pack into the project and pins its version", func() {
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Name).To(Equal("acme"))
+		Expect(pack.Scope).To(Equal(spec.PackScopeProject))
+		Expect(pack.State).To(Equal(spec.PackActive))
+		Expect(filepath.Join(tempDir, ".spec", "packs", "acme", "pack.json")).To(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, ".claude", "commands", "acme-review.md")).To(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, ".claude", "agents", "acme-bot.md")).To(BeAnExistingFile())
+
+		config, err := spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Packs).To(Equal(map[string]string{"acme": "1.2.0"}))
+		Expect(config.Requirements.DiscoveryQuestions).To(Equal(5))
+	})
+
+	It("only fills in configuration the project does not set", func() {
+		_, err := spec.SetConfigValue(tempDir, "review.required-approvals", "1")
+		Expect(err).NotTo(HaveOccurred())
+
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Config).To(Equal([]string{"artifacts.threat-model"}))
+		Expect(pack.KeptConfig).To(Equal([]string{"review.required_approvals"}))
+		config, err := spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Review.RequiredApprovals).To(Equal(1))
+		Expect(config.Artifacts).To(HaveKey("threat-model"))
+		Expect(config.Packs).To(Equal(map[string]string{"acme": "1.2.0"}))
+
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		config, err = spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Artifacts).To(BeEmpty())
+		Expect(config.Review.RequiredApprovals).To(Equal(1))
+	})
+
+	It("replaces the built-in defaults written by init and restores them on removal", func() {
+		writeFile(filepath.Join(packDir, "config.json"), `{"requirements": {"discovery_questions": 9}, "review": {"required_approvals": 2}}`)
+		configPath := filepath.Join(tempDir, ".spec", "config.json")
+		initial, err := os.ReadFile(configPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Config).To(Equal([]string{"requirements.discovery_questions", "review.required_approvals"}))
+		Expect(pack.KeptConfig).To(BeEmpty())
+		Expect(spec.GetConfigValue(tempDir, "requirements.discovery-questions")).To(Equal("9"))
+		Expect(spec.GetConfigValue(tempDir, "review.required-approvals")).To(Equal("2"))
+
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.GetConfigValue(tempDir, "requirements.discovery-questions")).To(Equal("5"))
+		Expect(spec.GetConfigValue(tempDir, "review.required-approvals")).To(Equal("0"))
+		content, err := os.ReadFile(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(MatchJSON(initial))
+	})
+
+	It("keeps config keys it does not know", func() {
+		configPath := filepath.Join(tempDir, ".spec", "config.json")
+		writeFile(configPath, `{"custom_team_key": {"owner": "platform"}}`)
+
+		_, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		content, err := os.ReadFile(configPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring(`"custom_team_key"`))
+	})
+
+	It("fills in missing settings and keeps those changed after install", func() {
+		writeFile(filepath.Join(tempDir, ".spec", "config.json"), `{"requirements": {"discovery_questions": 3}}`)
+
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Config).To(Equal([]string{"artifacts.threat-model", "review.required_approvals"}))
+		config, err := spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Review.RequiredApprovals).To(Equal(2))
+		Expect(config.Requirements.DiscoveryQuestions).To(Equal(3))
+
+		_, err = spec.SetConfigValue(tempDir, "review.required-approvals", "1")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		config, err = spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Review.RequiredApprovals).To(Equal(1))
+		Expect(config.Artifacts).To(BeEmpty())
+	})
+
+	It("uses templates of pinned packs after localized templates", func() {
+		_, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := spec.RenderTemplate(tempDir, "", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("# Requirements Specification"))
+		Expect(string(content)).To(HaveSuffix("## Security Review\n"))
+
+		sets, err := spec.TemplateSets(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sets).To(ContainElement("migration"))
+		_, err = spec.CreateNewRequirementsWithOptions(tempDir, "move-db", spec.RequirementsOptions{Type: "migration"})
+		Expect(err).NotTo(HaveOccurred())
+		requirements, err := os.ReadFile(filepath.Join(tempDir, ".spec", "001-move-db", "requirements.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(requirements)).To(Equal("# Migration: [Feature Name]\n"))
+
+		templates, err := spec.ListTemplates(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(templates).To(ContainElement(spec.TemplateInfo{Name: "requirements.md", State: spec.TemplatePack, Extends: true, Pack: "acme"}))
+
+		writeFile(filepath.Join(tempDir, ".spec", "templates", "requirements.md"), "# Local\n")
+		content, err = spec.RenderTemplate(tempDir, "", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Local\n"))
+	})
+
+	It("ignores packs whose pinned version is not installed", func() {
+		_, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.SetConfigValue(tempDir, "packs.acme", "2.0.0")
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := spec.RenderTemplate(tempDir, "", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).NotTo(ContainSubstring("Security Review"))
+
+		packs, err := spec.ListPacks(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packs).To(HaveLen(1))
+		Expect(packs[0].State).To(Equal(spec.PackMismatch))
+		Expect(packs[0].Pinned).To(Equal("2.0.0"))
+	})
+
+	It("installs from a .tar.gz archive for the user", func() {
+		archive := filepath.Join(tempDir, "acme.tar.gz")
+		f, err := os.Create(archive)
+		Expect(err).NotTo(HaveOccurred())
+		gz := gzip.NewWriter(f)
+		tw := tar.NewWriter(gz)
+		for name, content := range map[string]string{
+			"acme-1.2.0/pack.json":                 `{"name": "acme", "version": "1.2.0"}`,
+			"acme-1.2.0/templates/bugfix/extra.md": "# Extra\n",
+			"acme-1.2.0/commands/acme-review.md":   "# Review\n",
+		} {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		pack, err := spec.InstallPack(tempDir, archive, spec.PackInstallOptions{Scope: spec.PackScopeUser})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Dir).To(Equal(filepath.Join(homeDir, ".config", "specware", "packs", "acme")))
+		Expect(filepath.Join(homeDir, ".claude", "commands", "acme-review.md")).To(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, ".claude", "commands", "acme-review.md")).NotTo(BeAnExistingFile())
+
+		content, err := spec.RenderTemplate(tempDir, "bugfix", "extra")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Extra\n"))
+	})
+
+	It("rejects archives with entries outside the pack", func() {
+		archive := filepath.Join(tempDir, "evil.tgz")
+		f, err := os.Create(archive)
+		Expect(err).NotTo(HaveOccurred())
+		gz := gzip.NewWriter(f)
+		tw := tar.NewWriter(gz)
+		Expect(tw.WriteHeader(&tar.Header{Name: "../escape.md", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})).To(Succeed())
+		_, err = tw.Write([]byte("x"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		_, err = spec.InstallPack(tempDir, archive, spec.PackInstallOptions{})
+		Expect(err).To(MatchError(ContainSubstring("outside the pack")))
+	})
+
+	It("validates the manifest", func() {
+		writeFile(filepath.Join(packDir, "pack.json"), `{"name": "Acme Templates", "version": "1.0"}`)
+		_, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).To(MatchError(ContainSubstring("invalid pack name")))
+
+		Expect(os.Remove(filepath.Join(packDir, "pack.json"))).To(Succeed())
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).To(MatchError(ContainSubstring("pack.json not found")))
+	})
+
+	It("never removes files outside the installed commands and agents", func() {
+		victim := filepath.Join(tempDir, "victim.txt")
+		writeFile(victim, "keep\n")
+		writeFile(filepath.Join(packDir, ".installed.json"), `{"files": [{"path": "`+victim+`"}, {"path": "../victim.txt"}]}`)
+
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Files).To(ConsistOf(
+			filepath.Join(tempDir, ".claude", "commands", "acme-review.md"),
+			filepath.Join(tempDir, ".claude", "agents", "acme-bot.md"),
+		))
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(victim).To(BeAnExistingFile())
+
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		writeFile(filepath.Join(tempDir, ".spec", "packs", "acme", ".installed.json"), `{"files": [{"path": "commands/../../victim.txt"}]}`)
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).To(MatchError(ContainSubstring("not a command or agent")))
+		Expect(victim).To(BeAnExistingFile())
+	})
+
+	It("only replaces existing commands and agents when forced, and restores them on removal", func() {
+		specify := filepath.Join(tempDir, ".claude", "commands", "specify.md")
+		original, err := os.ReadFile(specify)
+		Expect(err).NotTo(HaveOccurred())
+		writeFile(filepath.Join(packDir, "commands", "specify.md"), "# Acme specify\n")
+
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).To(MatchError(ContainSubstring("would replace files it did not install:\n  " + specify)))
+		Expect(filepath.Join(tempDir, ".spec", "packs", "acme")).NotTo(BeADirectory())
+
+		pack, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{Force: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pack.Replaced).To(ConsistOf(specify))
+		Expect(os.ReadFile(specify)).To(BeEquivalentTo("# Acme specify\n"))
+
+		// Reinstalling keeps the original to restore
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.ReadFile(specify)).To(Equal(original))
+		Expect(filepath.Join(tempDir, ".claude", "commands", "acme-review.md")).NotTo(BeAnExistingFile())
+	})
+
+	It("upgrades and removes packs with the files they installed", func() {
+		_, err := spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Remove(filepath.Join(packDir, "agents", "acme-bot.md"))).To(Succeed())
+		writeFile(filepath.Join(packDir, "pack.json"), `{"name": "acme", "version": "1.3.0"}`)
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{})
+		Expect(err).To(MatchError(ContainSubstring("pinned to version 1.2.0 in .spec/config.json; use --upgrade")))
+		Expect(filepath.Join(tempDir, ".claude", "agents", "acme-bot.md")).To(BeAnExistingFile())
+		_, err = spec.InstallPack(tempDir, packDir, spec.PackInstallOptions{Upgrade: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(tempDir, ".claude", "agents", "acme-bot.md")).NotTo(BeAnExistingFile())
+		config, err := spec.LoadConfig(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Packs["acme"]).To(Equal("1.3.0"))
+
+		removed, err := spec.RemovePack(tempDir, "acme", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed.Version).To(Equal("1.3.0"))
+		Expect(filepath.Join(tempDir, ".spec", "packs", "acme")).NotTo(BeADirectory())
+		Expect(filepath.Join(tempDir, ".claude", "commands", "acme-review.md")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, ".claude", "commands", "specify.md")).To(BeAnExistingFile())
+
+		packs, err := spec.ListPacks(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packs).To(BeEmpty())
+
+		_, err = spec.RemovePack(tempDir, "acme", "")
+		Expect(err).To(MatchError(ContainSubstring("not installed")))
+	})
+})
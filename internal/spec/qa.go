@@ -0,0 +1,368 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Q&A phases and the context files that hold their questions
+const (
+	QAPhaseRequirements       = "requirements"
+	QAPhaseImplementationPlan = "implementation-plan"
+)
+
+// Q&A question categories, matching the question counts in config.json
+const (
+	QACategoryDiscovery = "discovery"
+	QACategoryExpert    = "expert"
+	QACategoryPlan      = "plan"
+	QACategoryTesting   = "testing"
+)
+
+// qaExampleQuestion is the placeholder entry shipped in the context template
+const qaExampleQuestion = "Example question?"
+
+var (
+	qaHeadingPattern  = regexp.MustCompile(`^Q(\d+):\s*(.*)$`)
+	qaDefaultPattern  = regexp.MustCompile(`^\*\*Default if unknown:\*\*\s*(.*)$`)
+	qaAnswerPattern   = regexp.MustCompile(`^\*\*Answer:\*\*\s*(.*)$`)
+	qaCategoryPattern = regexp.MustCompile(`^\*\*Category:\*\*\s*(.*)$`)
+)
+
+// QAEntry is a single "### QN:" question recorded in a context file
+type QAEntry struct {
+	Number   int    `json:"number"`
+	Question string `json:"question"`
+	Default  string `json:"default,omitempty"`
+	Answer   string `json:"answer,omitempty"`
+	Category string `json:"category,omitempty"`
+	Phase    string `json:"phase"`
+	File     string `json:"file"`
+
+	start, end int
+}
+
+// ID returns the question identifier, e.g. "Q3"
+func (e QAEntry) ID() string {
+	return fmt.Sprintf("Q%d", e.Number)
+}
+
+// Answered reports whether an answer has been recorded
+func (e QAEntry) Answered() bool {
+	return strings.TrimSpace(e.Answer) != ""
+}
+
+// qaContextFile returns the context file name for a Q&A phase
+func qaContextFile(phase string) (string, string, error) {
+	switch strings.ToLower(phase) {
+	case "requirements":
+		return QAPhaseRequirements, "context-requirements.md", nil
+	case "implementation-plan", "implementation", "plan":
+		return QAPhaseImplementationPlan, "context-implementation-plan.md", nil
+	default:
+		return "", "", fmt.Errorf("unknown Q&A phase %q (expected requirements or implementation-plan)", phase)
+	}
+}
+
+// parseQAEntries extracts Q&A entries from the content of a context file
+func parseQAEntries(content []byte, phase, file string) []QAEntry {
+	var entries []QAEntry
+	for _, h := range ParseHeadings(content) {
+		match := qaHeadingPattern.FindStringSubmatch(h.Title)
+		if match == nil || strings.TrimSpace(match[2]) == qaExampleQuestion {
+			continue
+		}
+		num, _ := strconv.Atoi(match[1])
+		entry := QAEntry{
+			Number:   num,
+			Question: strings.TrimSpace(match[2]),
+			Phase:    phase,
+			File:     file,
+			start:    h.Start,
+			end:      h.End,
+		}
+		for _, line := range strings.Split(string(content[h.BodyStart:h.End]), "\n") {
+			line = strings.TrimSpace(line)
+			if m := qaDefaultPattern.FindStringSubmatch(line); m != nil {
+				entry.Default = strings.TrimSpace(m[1])
+			} else if m := qaAnswerPattern.FindStringSubmatch(line); m != nil {
+				entry.Answer = strings.TrimSpace(m[1])
+			} else if m := qaCategoryPattern.FindStringSubmatch(line); m != nil {
+				entry.Category = strings.ToLower(strings.TrimSpace(m[1]))
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ListQuestions returns the Q&A entries recorded for a feature across both
+// context files, ordered by phase and then by position in the file
+func ListQuestions(targetDir, shortName string) ([]QAEntry, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	return readQuestions(featureDir)
+}
+
+// readQuestions reads the Q&A entries from a feature directory
+func readQuestions(featureDir string) ([]QAEntry, error) {
+	var entries []QAEntry
+	for _, phase := range []string{QAPhaseRequirements, QAPhaseImplementationPlan} {
+		_, file, _ := qaContextFile(phase)
+		content, err := os.ReadFile(filepath.Join(featureDir, file))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		entries = append(entries, parseQAEntries(content, phase, file)...)
+	}
+	return entries, nil
+}
+
+// QuestionInput describes a question to add with AddQuestion
+type QuestionInput struct {
+	Phase    string
+	Category string
+	Question string
+	Default  string
+	Reason   string
+}
+
+// qaPhaseCategories returns the question categories asked during a phase
+func qaPhaseCategories(phase string) []string {
+	if phase == QAPhaseImplementationPlan {
+		return []string{QACategoryPlan, QACategoryTesting}
+	}
+	return []string{QACategoryDiscovery, QACategoryExpert}
+}
+
+// defaultQACategory picks a category for a new question from the feature's
+// current step when none is given explicitly. Implementation plan questions
+// count as testing questions once the configured plan questions were asked.
+func defaultQACategory(phase, currentStep string, existing []QAEntry, config Config) string {
+	if phase == QAPhaseImplementationPlan {
+		planQuestions := 0
+		for _, entry := range existing {
+			if entry.Phase == phase && entry.Category == QACategoryPlan {
+				planQuestions++
+			}
+		}
+		if config.Implementation.PlanQuestions > 0 && planQuestions >= config.Implementation.PlanQuestions {
+			return QACategoryTesting
+		}
+		return QACategoryPlan
+	}
+	if stepsEqual(currentStep, "Requirements Expert Q&A") {
+		return QACategoryExpert
+	}
+	return QACategoryDiscovery
+}
+
+// AddQuestion appends a new question to the context file for the given phase.
+// Questions are numbered sequentially across both phases so implementation
+// plan questions continue from the last requirements question. If no category
+// is given it is inferred from the feature's current step and the questions
+// already asked.
+func AddQuestion(targetDir, shortName string, input QuestionInput) (QAEntry, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return QAEntry{}, err
+	}
+
+	phase, file, err := qaContextFile(input.Phase)
+	if err != nil {
+		return QAEntry{}, err
+	}
+	question := strings.TrimSpace(input.Question)
+	if question == "" {
+		return QAEntry{}, fmt.Errorf("question cannot be empty")
+	}
+
+	existing, err := readQuestions(featureDir)
+	if err != nil {
+		return QAEntry{}, err
+	}
+
+	category := strings.ToLower(strings.TrimSpace(input.Category))
+	if category == "" {
+		status, _ := readFeatureStatus(featureDir)
+		config, err := LoadConfig(targetDir)
+		if err != nil {
+			return QAEntry{}, err
+		}
+		category = defaultQACategory(phase, status.CurrentStep, existing, config)
+	}
+	validCategories := qaPhaseCategories(phase)
+	valid := false
+	for _, c := range validCategories {
+		if c == category {
+			valid = true
+		}
+	}
+	if !valid {
+		return QAEntry{}, fmt.Errorf("unknown %s question category %q (expected %s)", phase, category, strings.Join(validCategories, " or "))
+	}
+
+	contextPath := filepath.Join(featureDir, file)
+	content, err := os.ReadFile(contextPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return QAEntry{}, fmt.Errorf("%s not found for feature %s", file, shortName)
+		}
+		return QAEntry{}, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	next := 1
+	for _, entry := range existing {
+		if entry.Number >= next {
+			next = entry.Number + 1
+		}
+	}
+
+	entry := QAEntry{
+		Number:   next,
+		Question: question,
+		Default:  formatQADefault(input.Default, input.Reason),
+		Category: category,
+		Phase:    phase,
+		File:     file,
+	}
+
+	updated := insertQAEntry(removeQAExample(string(content)), renderQAEntry(entry))
+	if err := os.WriteFile(contextPath, []byte(updated), 0644); err != nil {
+		return QAEntry{}, fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return entry, nil
+}
+
+// AnswerQuestion records the answer to a question identified as "Q3" or "3".
+// If useDefault is set the recorded default is used as the answer.
+func AnswerQuestion(targetDir, shortName, id, answer string, useDefault bool) (QAEntry, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return QAEntry{}, err
+	}
+
+	num, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(id)), "Q"))
+	if err != nil {
+		return QAEntry{}, fmt.Errorf("invalid question id %q (expected e.g. Q3)", id)
+	}
+
+	entries, err := readQuestions(featureDir)
+	if err != nil {
+		return QAEntry{}, err
+	}
+	for _, entry := range entries {
+		if entry.Number != num {
+			continue
+		}
+
+		if useDefault {
+			if entry.Default == "" {
+				return QAEntry{}, fmt.Errorf("question %s has no recorded default", entry.ID())
+			}
+			answer = entry.Default + " (default used)"
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return QAEntry{}, fmt.Errorf("answer cannot be empty")
+		}
+
+		contextPath := filepath.Join(featureDir, entry.File)
+		content, err := os.ReadFile(contextPath)
+		if err != nil {
+			return QAEntry{}, fmt.Errorf("failed to read %s: %w", entry.File, err)
+		}
+		text := string(content)
+
+		entry.Answer = answer
+		block := strings.TrimRight(text[entry.start:entry.end], "\n")
+		var lines []string
+		for _, line := range strings.Split(block, "\n") {
+			if !qaAnswerPattern.MatchString(strings.TrimSpace(line)) {
+				lines = append(lines, line)
+			}
+		}
+		lines = append(lines, "**Answer:** "+answer)
+		trailing := text[entry.start+len(block) : entry.end]
+		updated := text[:entry.start] + strings.Join(lines, "\n") + trailing + text[entry.end:]
+
+		if err := os.WriteFile(contextPath, []byte(updated), 0644); err != nil {
+			return QAEntry{}, fmt.Errorf("failed to write %s: %w", entry.File, err)
+		}
+		return entry, nil
+	}
+	return QAEntry{}, fmt.Errorf("question %s not found for feature %s", id, shortName)
+}
+
+// formatQADefault renders the default answer and its justification
+func formatQADefault(defaultAnswer, reason string) string {
+	defaultAnswer = strings.TrimSpace(defaultAnswer)
+	if defaultAnswer != "" {
+		defaultAnswer = strings.ToUpper(defaultAnswer[:1]) + defaultAnswer[1:]
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return defaultAnswer
+	}
+	if defaultAnswer == "" {
+		return "(" + reason + ")"
+	}
+	return fmt.Sprintf("%s (%s)", defaultAnswer, reason)
+}
+
+// renderQAEntry renders an entry in the format used by the context template
+func renderQAEntry(entry QAEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s: %s\n", entry.ID(), entry.Question)
+	if entry.Category != "" {
+		fmt.Fprintf(&b, "**Category:** %s\n", entry.Category)
+	}
+	if entry.Default != "" {
+		fmt.Fprintf(&b, "**Default if unknown:** %s\n", entry.Default)
+	}
+	if entry.Answer != "" {
+		fmt.Fprintf(&b, "**Answer:** %s\n", entry.Answer)
+	}
+	return b.String()
+}
+
+// removeQAExample strips the placeholder question shipped in the context template
+func removeQAExample(content string) string {
+	for _, h := range ParseHeadings([]byte(content)) {
+		if match := qaHeadingPattern.FindStringSubmatch(h.Title); match != nil && strings.TrimSpace(match[2]) == qaExampleQuestion {
+			return content[:h.Start] + content[h.End:]
+		}
+	}
+	return content
+}
+
+// insertQAEntry inserts a rendered entry at the end of the "Questions & Answers"
+// section, or at the end of the document if there is no such section
+func insertQAEntry(content, entry string) string {
+	insertAt := len(content)
+	for _, h := range ParseHeadings([]byte(content)) {
+		if strings.EqualFold(h.Title, "Questions & Answers") {
+			insertAt = h.End
+			break
+		}
+	}
+
+	before := strings.TrimRight(content[:insertAt], "\n")
+	after := content[insertAt:]
+	if before != "" {
+		before += "\n\n"
+	}
+	if after == "" {
+		return before + entry
+	}
+	return before + entry + "\n" + after
+}
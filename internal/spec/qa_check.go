@@ -0,0 +1,165 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// QACheckResult reports whether the configured number of questions were asked
+// for one question category and whether each has an answer or a default
+type QACheckResult struct {
+	Category   string   `json:"category"`
+	Phase      string   `json:"phase"`
+	Required   int      `json:"required"`
+	Asked      int      `json:"asked"`
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// Passed reports whether the category has no gap
+func (r QACheckResult) Passed() bool {
+	return r.Asked >= r.Required && len(r.Unresolved) == 0
+}
+
+// String describes the result, including any gap, in a single line
+func (r QACheckResult) String() string {
+	summary := fmt.Sprintf("%s questions (%s): %d of %d asked", r.Category, r.Phase, r.Asked, r.Required)
+	if r.Asked < r.Required {
+		summary += fmt.Sprintf(", %d missing", r.Required-r.Asked)
+	}
+	if len(r.Unresolved) > 0 {
+		summary += fmt.Sprintf(", no answer or default for %s", strings.Join(r.Unresolved, ", "))
+	}
+	return summary
+}
+
+// qaPhaseExitChecks maps the Q&A workflow steps to the question categories
+// that must be complete before the feature moves on from that step
+var qaPhaseExitChecks = map[string][]string{
+	normalizeStep("Requirements Gathering"):  {QACategoryDiscovery},
+	normalizeStep("Requirements Expert Q&A"): {QACategoryExpert},
+	normalizeStep("Implementation Plan Q&A"): {QACategoryPlan, QACategoryTesting},
+}
+
+// requiredQuestions returns the configured question count for a category
+func requiredQuestions(config Config, category string) int {
+	switch category {
+	case QACategoryDiscovery:
+		return config.Requirements.DiscoveryQuestions
+	case QACategoryExpert:
+		return config.Requirements.ExpertQuestions
+	case QACategoryPlan:
+		return config.Implementation.PlanQuestions
+	case QACategoryTesting:
+		return config.Implementation.TestingQuestions
+	}
+	return 0
+}
+
+// CheckQuestions compares the Q&A entries recorded for a feature against the
+// question counts configured in .spec/config.json. If no categories are given
+// all four categories are checked.
+func CheckQuestions(targetDir, shortName string, categories ...string) ([]QACheckResult, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	return checkQuestions(featureDir, config, categories)
+}
+
+// checkQuestions performs CheckQuestions for a resolved feature directory
+func checkQuestions(featureDir string, config Config, categories []string) ([]QACheckResult, error) {
+	entries, err := readQuestions(featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*QACheckResult)
+	for _, phase := range []string{QAPhaseRequirements, QAPhaseImplementationPlan} {
+		phaseCategories := qaPhaseCategories(phase)
+		for _, category := range phaseCategories {
+			results[category] = &QACheckResult{
+				Category: category,
+				Phase:    phase,
+				Required: requiredQuestions(config, category),
+			}
+		}
+
+		for _, entry := range entries {
+			if entry.Phase != phase {
+				continue
+			}
+
+			// Entries written without a category fill whichever category of
+			// the phase is still short of questions, in workflow order
+			result, ok := results[entry.Category]
+			if !ok || result.Phase != phase {
+				result = results[phaseCategories[len(phaseCategories)-1]]
+				for _, category := range phaseCategories {
+					if results[category].Asked < results[category].Required {
+						result = results[category]
+						break
+					}
+				}
+			}
+
+			result.Asked++
+			if !entry.Answered() && strings.TrimSpace(entry.Default) == "" {
+				result.Unresolved = append(result.Unresolved, entry.ID())
+			}
+		}
+	}
+
+	if len(categories) == 0 {
+		categories = []string{QACategoryDiscovery, QACategoryExpert, QACategoryPlan, QACategoryTesting}
+	}
+	var checked []QACheckResult
+	for _, category := range categories {
+		result, ok := results[category]
+		if !ok {
+			return nil, fmt.Errorf("unknown question category %q", category)
+		}
+		checked = append(checked, *result)
+	}
+	return checked, nil
+}
+
+// checkQAPhaseExit verifies the Q&A of the step a feature is leaving. Gaps are
+// written to out as warnings unless validation.enforce_question_counts is set.
+func checkQAPhaseExit(targetDir, featureDir, shortName, from, to string, force bool, out io.Writer) error {
+	categories, ok := qaPhaseExitChecks[normalizeStep(from)]
+	if !ok || stepsEqual(from, to) {
+		return nil
+	}
+
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return err
+	}
+	results, err := checkQuestions(featureDir, config, categories)
+	if err != nil {
+		return err
+	}
+
+	var gaps []string
+	for _, result := range results {
+		if !result.Passed() {
+			gaps = append(gaps, result.String())
+		}
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+
+	if config.Validation.EnforceQuestionCounts && !force {
+		return fmt.Errorf("feature %s cannot leave '%s' until its questions are complete:\n  %s",
+			shortName, from, strings.Join(gaps, "\n  "))
+	}
+	fmt.Fprintf(out, "Warning: feature %s is leaving '%s' with incomplete questions:\n  %s\n",
+		shortName, from, strings.Join(gaps, "\n  "))
+	return nil
+}
@@ -0,0 +1,175 @@
+package spec_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QA Check", func() {
+	var tempDir string
+
+	addQuestions := func(category string, count int) {
+		for i := 0; i < count; i++ {
+			_, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{
+				Phase:    "requirements",
+				Category: category,
+				Question: fmt.Sprintf("%s question %d?", category, i+1),
+				Default:  "yes",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	writeConfig := func(config string) {
+		path := filepath.Join(tempDir, ".spec", "config.json")
+		Expect(os.WriteFile(path, []byte(config), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "test-feature")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("CheckQuestions", func() {
+		It("should report missing questions per category", func() {
+			addQuestions(spec.QACategoryDiscovery, 3)
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryDiscovery, spec.QACategoryExpert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+
+			Expect(results[0].Asked).To(Equal(3))
+			Expect(results[0].Required).To(Equal(5))
+			Expect(results[0].Passed()).To(BeFalse())
+			Expect(results[0].String()).To(ContainSubstring("3 of 5 asked, 2 missing"))
+
+			Expect(results[1].Asked).To(Equal(0))
+			Expect(results[1].Required).To(Equal(4))
+		})
+
+		It("should pass once enough questions have defaults or answers", func() {
+			addQuestions(spec.QACategoryDiscovery, 5)
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryDiscovery)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Passed()).To(BeTrue())
+		})
+
+		It("should report questions without an answer or default", func() {
+			_, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "No default?"})
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryDiscovery)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Unresolved).To(ConsistOf("Q1"))
+
+			_, err = spec.AnswerQuestion(tempDir, "test-feature", "Q1", "no", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err = spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryDiscovery)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Unresolved).To(BeEmpty())
+		})
+
+		It("should use the configured question counts", func() {
+			writeConfig(`{"requirements": {"discovery_questions": 2, "expert_questions": 1}}`)
+			addQuestions(spec.QACategoryDiscovery, 2)
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(4))
+			Expect(results[0].Passed()).To(BeTrue())
+			Expect(results[1].Required).To(Equal(1))
+			Expect(results[2].Required).To(Equal(5))
+		})
+
+		It("should count questions without a category towards the phase", func() {
+			writeConfig(`{"requirements": {"discovery_questions": 1, "expert_questions": 1}}`)
+			contextPath := filepath.Join(tempDir, ".spec", "001-test-feature", "context-requirements.md")
+			content := "# Context\n\n## Questions & Answers\n\n### Q1: First?\n**Answer:** Yes\n\n### Q2: Second?\n**Answer:** No\n"
+			Expect(os.WriteFile(contextPath, []byte(content), 0644)).To(Succeed())
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryDiscovery, spec.QACategoryExpert)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Passed()).To(BeTrue())
+			Expect(results[1].Passed()).To(BeTrue())
+		})
+	})
+
+	Describe("UpdateFeatureStatus Q&A checks", func() {
+		BeforeEach(func() {
+			Expect(spec.UpdateFeatureStatus(tempDir, "test-feature", "Requirements Gathering")).To(Succeed())
+		})
+
+		It("should only warn about gaps by default", func() {
+			err := spec.UpdateFeatureStatus(tempDir, "test-feature", "Requirements Context Gathering")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should block leaving a Q&A step when enforcement is enabled", func() {
+			writeConfig(`{"validation": {"enforce_question_counts": true}}`)
+
+			err := spec.UpdateFeatureStatus(tempDir, "test-feature", "Requirements Context Gathering")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("discovery questions (requirements): 0 of 5 asked"))
+
+			err = spec.UpdateFeatureStatusWithOptions(tempDir, "test-feature", "Requirements Context Gathering", spec.StatusUpdateOptions{Force: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should allow leaving a Q&A step once questions are complete", func() {
+			writeConfig(`{"validation": {"enforce_question_counts": true}}`)
+			addQuestions(spec.QACategoryDiscovery, 5)
+
+			err := spec.UpdateFeatureStatus(tempDir, "test-feature", "Requirements Context Gathering")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should count implementation plan and testing questions asked as specify.md describes", func() {
+			_, err := spec.CreateNewImplementationPlan(tempDir, "test-feature")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.UpdateFeatureStatus(tempDir, "test-feature", "Implementation Planning")).To(Succeed())
+			Expect(spec.UpdateFeatureStatus(tempDir, "test-feature", "Implementation Plan Q&A")).To(Succeed())
+			writeConfig(`{"validation": {"enforce_question_counts": true}}`)
+
+			// Step 3 records plan questions without a category
+			for i := 0; i < 5; i++ {
+				entry, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "implementation-plan", Question: fmt.Sprintf("Plan %d?", i+1), Default: "yes"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entry.Category).To(Equal(spec.QACategoryPlan))
+			}
+			// Step 4 records testing questions with --category testing; once
+			// the plan questions are complete the category is also inferred
+			entry, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "implementation-plan", Category: "testing", Question: "Unit tests?", Default: "yes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Category).To(Equal(spec.QACategoryTesting))
+			entry, err = spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "implementation-plan", Question: "E2e tests?", Default: "no"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Category).To(Equal(spec.QACategoryTesting))
+
+			results, err := spec.CheckQuestions(tempDir, "test-feature", spec.QACategoryPlan, spec.QACategoryTesting)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Asked).To(Equal(5))
+			Expect(results[1].Asked).To(Equal(2))
+			Expect(results[1].Passed()).To(BeTrue())
+
+			Expect(spec.UpdateFeatureStatus(tempDir, "test-feature", "Implementation Plan Generated")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,117 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QA", func() {
+	var tempDir string
+
+	readContext := func(file string) string {
+		content, err := os.ReadFile(filepath.Join(tempDir, ".spec", "001-test-feature", file))
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "test-feature")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("AddQuestion", func() {
+		It("should replace the template example with a numbered entry", func() {
+			entry, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "Will users log in with email?", Default: "yes", Reason: "most apps do"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.ID()).To(Equal("Q1"))
+
+			content := readContext("context-requirements.md")
+			Expect(content).NotTo(ContainSubstring("Example question?"))
+			Expect(content).To(ContainSubstring("### Q1: Will users log in with email?\n**Category:** discovery\n**Default if unknown:** Yes (most apps do)\n"))
+			Expect(content).To(ContainSubstring("## Context Gathering Results"))
+		})
+
+		It("should continue numbering across phases", func() {
+			_, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "First?", Default: "yes"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "Second?", Default: "no"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = spec.CreateNewImplementationPlan(tempDir, "test-feature")
+			Expect(err).NotTo(HaveOccurred())
+
+			entry, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "implementation-plan", Question: "Third?", Default: "yes"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.ID()).To(Equal("Q3"))
+			Expect(readContext("context-implementation-plan.md")).To(ContainSubstring("### Q3: Third?"))
+		})
+
+		It("should fail for unknown phases or missing context files", func() {
+			_, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "testing", Question: "Q?", Default: "yes"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown Q&A phase"))
+
+			_, err = spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "implementation-plan", Question: "Q?", Default: "yes"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("context-implementation-plan.md not found"))
+		})
+	})
+
+	Describe("AnswerQuestion", func() {
+		BeforeEach(func() {
+			_, err := spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "First?", Default: "yes", Reason: "common"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.AddQuestion(tempDir, "test-feature", spec.QuestionInput{Phase: "requirements", Question: "Second?", Default: "no"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record and replace answers", func() {
+			_, err := spec.AnswerQuestion(tempDir, "test-feature", "Q1", "No", false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.AnswerQuestion(tempDir, "test-feature", "q1", "Yes, with SSO", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			content := readContext("context-requirements.md")
+			Expect(content).To(ContainSubstring("**Default if unknown:** Yes (common)\n**Answer:** Yes, with SSO\n\n### Q2"))
+			Expect(content).NotTo(ContainSubstring("**Answer:** No\n"))
+		})
+
+		It("should record the default when requested", func() {
+			entry, err := spec.AnswerQuestion(tempDir, "test-feature", "Q2", "", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Answer).To(Equal("No (default used)"))
+		})
+
+		It("should list only unanswered questions as unanswered", func() {
+			_, err := spec.AnswerQuestion(tempDir, "test-feature", "Q1", "yes", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			entries, err := spec.ListQuestions(tempDir, "test-feature")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Answered()).To(BeTrue())
+			Expect(entries[1].Answered()).To(BeFalse())
+		})
+
+		It("should fail for unknown questions", func() {
+			_, err := spec.AnswerQuestion(tempDir, "test-feature", "Q9", "yes", false)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("question Q9 not found"))
+		})
+	})
+})
@@ -0,0 +1,420 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RelationsFileName is the per-feature file recording relationships to other features
+const RelationsFileName = "relations.json"
+
+// FeatureRelations represents the relationships stored in relations.json
+type FeatureRelations struct {
+	DependsOn []string `json:"depends-on,omitempty"`
+	Blocks    []string `json:"blocks,omitempty"`
+}
+
+// DependencyGraph maps each feature short name to the short names it depends on
+type DependencyGraph struct {
+	Features  []Feature
+	DependsOn map[string][]string
+}
+
+// readFeatureRelations reads relations.json from a feature directory, returning
+// empty relations if the file does not exist
+func readFeatureRelations(featureDir string) (FeatureRelations, error) {
+	var relations FeatureRelations
+	data, err := os.ReadFile(filepath.Join(featureDir, RelationsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return relations, nil
+		}
+		return relations, fmt.Errorf("failed to read %s: %w", RelationsFileName, err)
+	}
+	if err := json.Unmarshal(data, &relations); err != nil {
+		return relations, fmt.Errorf("failed to parse %s in %s: %w", RelationsFileName, filepath.Base(featureDir), err)
+	}
+	return relations, nil
+}
+
+// writeFeatureRelations writes relations.json to a feature directory
+func writeFeatureRelations(featureDir string, relations FeatureRelations) error {
+	jsonData, err := json.MarshalIndent(relations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal relations: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, RelationsFileName), jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", RelationsFileName, err)
+	}
+	return nil
+}
+
+// appendUnique appends value to list if it is not already present
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// LinkFeatures records that shortName depends on and/or blocks other features.
+// The link is rejected if any referenced feature does not exist or if it would
+// introduce a dependency cycle.
+func LinkFeatures(targetDir, shortName string, dependsOn, blocks []string) error {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return err
+	}
+
+	if len(dependsOn) == 0 && len(blocks) == 0 {
+		return fmt.Errorf("at least one --depends-on or --blocks feature must be provided")
+	}
+
+	for _, other := range append(append([]string{}, dependsOn...), blocks...) {
+		if other == shortName {
+			return fmt.Errorf("feature %s cannot be linked to itself", shortName)
+		}
+		if _, err := resolveFeatureDir(targetDir, other); err != nil {
+			return err
+		}
+	}
+
+	relations, err := readFeatureRelations(featureDir)
+	if err != nil {
+		return err
+	}
+	for _, other := range dependsOn {
+		relations.DependsOn = appendUnique(relations.DependsOn, other)
+	}
+	for _, other := range blocks {
+		relations.Blocks = appendUnique(relations.Blocks, other)
+	}
+
+	// Check the resulting graph for cycles before writing anything
+	graph, err := BuildDependencyGraph(targetDir)
+	if err != nil {
+		return err
+	}
+	graph.addRelations(shortName, relations)
+	if cycle := graph.FindCycle(); cycle != nil {
+		return fmt.Errorf("linking would create a dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return writeFeatureRelations(featureDir, relations)
+}
+
+// UnlinkFeatures removes relationships previously recorded for shortName.
+// Links are removed from both features, so "a --depends-on b" also removes a
+// "b --blocks a" link.
+func UnlinkFeatures(targetDir, shortName string, dependsOn, blocks []string) error {
+	if _, err := resolveFeatureDir(targetDir, shortName); err != nil {
+		return err
+	}
+	if len(dependsOn) == 0 && len(blocks) == 0 {
+		return fmt.Errorf("at least one --depends-on or --blocks feature must be provided")
+	}
+
+	// Every link is an edge from a dependent feature to its dependency
+	type edge struct{ dependent, dependency string }
+	var edges []edge
+	for _, other := range dependsOn {
+		edges = append(edges, edge{dependent: shortName, dependency: other})
+	}
+	for _, other := range blocks {
+		edges = append(edges, edge{dependent: other, dependency: shortName})
+	}
+
+	// Relations of the features involved, by directory; features that no
+	// longer exist are skipped
+	dirs := make(map[string]string)
+	relations := make(map[string]FeatureRelations)
+	changed := make(map[string]bool)
+	load := func(name string) (string, bool, error) {
+		if dir, ok := dirs[name]; ok {
+			return dir, true, nil
+		}
+		dir, err := resolveFeatureDir(targetDir, name)
+		if err != nil {
+			return "", false, nil
+		}
+		r, err := readFeatureRelations(dir)
+		if err != nil {
+			return "", false, err
+		}
+		dirs[name], relations[dir] = dir, r
+		return dir, true, nil
+	}
+
+	for _, e := range edges {
+		found := false
+		if dir, ok, err := load(e.dependent); err != nil {
+			return err
+		} else if ok {
+			if r := relations[dir]; contains(r.DependsOn, e.dependency) {
+				r.DependsOn = removeAll(r.DependsOn, []string{e.dependency})
+				relations[dir], changed[dir], found = r, true, true
+			}
+		}
+		if dir, ok, err := load(e.dependency); err != nil {
+			return err
+		} else if ok {
+			if r := relations[dir]; contains(r.Blocks, e.dependent) {
+				r.Blocks = removeAll(r.Blocks, []string{e.dependent})
+				relations[dir], changed[dir], found = r, true, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("feature %s does not depend on %s", e.dependent, e.dependency)
+		}
+	}
+
+	for dir := range changed {
+		if err := writeFeatureRelations(dir, relations[dir]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contains reports whether list includes value
+func contains(list []string, value string) bool {
+	for _, existing := range list {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// removeAll returns list without any of the values in remove
+func removeAll(list, remove []string) []string {
+	var result []string
+	for _, value := range list {
+		keep := true
+		for _, r := range remove {
+			if value == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// BuildDependencyGraph reads the relations of every feature in the project.
+// A "blocks" relation on one feature is treated as a "depends-on" relation
+// on the blocked feature.
+func BuildDependencyGraph(targetDir string) (*DependencyGraph, error) {
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &DependencyGraph{
+		Features:  features,
+		DependsOn: make(map[string][]string),
+	}
+	for _, feature := range features {
+		relations, err := readFeatureRelations(feature.Dir)
+		if err != nil {
+			return nil, err
+		}
+		graph.addRelations(feature.ShortName, relations)
+	}
+	return graph, nil
+}
+
+// addRelations merges the relations of a single feature into the graph
+func (g *DependencyGraph) addRelations(shortName string, relations FeatureRelations) {
+	for _, dependency := range relations.DependsOn {
+		g.DependsOn[shortName] = appendUnique(g.DependsOn[shortName], dependency)
+	}
+	for _, blocked := range relations.Blocks {
+		g.DependsOn[blocked] = appendUnique(g.DependsOn[blocked], shortName)
+	}
+}
+
+// FindCycle returns the short names forming a dependency cycle, or nil if the
+// graph is acyclic. The first and last entries of a returned cycle are equal.
+func (g *DependencyGraph) FindCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dependency := range g.DependsOn[name] {
+			switch state[dependency] {
+			case visiting:
+				for i, entry := range stack {
+					if entry == dependency {
+						cycle = append(append([]string{}, stack[i:]...), dependency)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(dependency) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return false
+	}
+
+	names := make([]string, 0, len(g.DependsOn))
+	for name := range g.DependsOn {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited && visit(name) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// feature returns the feature with the given short name, if known
+func (g *DependencyGraph) feature(shortName string) (Feature, bool) {
+	for _, feature := range g.Features {
+		if feature.ShortName == shortName {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// isFeatureComplete reports whether a feature's status has reached "Implementation Complete"
+func isFeatureComplete(status string) bool {
+	return StepIndex(status) >= StepIndex("Implementation Complete")
+}
+
+// IncompleteDependencies returns the dependencies of shortName that are not complete
+func IncompleteDependencies(targetDir, shortName string) ([]Feature, error) {
+	graph, err := BuildDependencyGraph(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var incomplete []Feature
+	for _, dependency := range graph.DependsOn[shortName] {
+		feature, ok := graph.feature(dependency)
+		if !ok {
+			feature = Feature{ShortName: dependency, Name: dependency}
+		}
+		if !isFeatureComplete(feature.Status) {
+			incomplete = append(incomplete, feature)
+		}
+	}
+	return incomplete, nil
+}
+
+// warnIncompleteDependencies writes a warning to out when a feature advances past
+// planning while any of its dependencies are not complete. The check is
+// advisory, so unreadable relations are reported as a warning too.
+func warnIncompleteDependencies(targetDir, shortName, status string, out io.Writer) {
+	if StepIndex(status) <= StepIndex("Implementation Planning Complete") {
+		return
+	}
+
+	incomplete, err := IncompleteDependencies(targetDir, shortName)
+	if err != nil {
+		fmt.Fprintf(out, "Warning: could not check the dependencies of feature %s: %v\n", shortName, err)
+		return
+	}
+	if len(incomplete) == 0 {
+		return
+	}
+
+	var names []string
+	for _, feature := range incomplete {
+		current := feature.Status
+		if current == "" {
+			current = "unknown"
+		}
+		names = append(names, fmt.Sprintf("%s (%s)", feature.Name, current))
+	}
+	fmt.Fprintf(out, "Warning: feature %s is advancing to '%s' but depends on incomplete features: %s\n",
+		shortName, status, strings.Join(names, ", "))
+}
+
+// RenderDependencyGraph renders the dependency graph in the given format
+// ("dot" or "mermaid"). Edges point from a dependency to the feature it blocks.
+func RenderDependencyGraph(targetDir, format string) (string, error) {
+	graph, err := BuildDependencyGraph(targetDir)
+	if err != nil {
+		return "", err
+	}
+
+	label := func(shortName string) string {
+		feature, ok := graph.feature(shortName)
+		if !ok {
+			return shortName
+		}
+		if feature.Status == "" {
+			return feature.Name
+		}
+		return fmt.Sprintf("%s\\n%s", feature.Name, feature.Status)
+	}
+
+	type edge struct{ from, to string }
+	var edges []edge
+	for _, feature := range graph.Features {
+		for _, dependency := range graph.DependsOn[feature.ShortName] {
+			edges = append(edges, edge{from: dependency, to: feature.ShortName})
+		}
+	}
+
+	var b strings.Builder
+	switch format {
+	case "dot":
+		b.WriteString("digraph features {\n")
+		b.WriteString("  rankdir=LR;\n")
+		b.WriteString("  node [shape=box];\n")
+		for _, feature := range graph.Features {
+			fmt.Fprintf(&b, "  %q [label=\"%s\"];\n", feature.ShortName, strings.ReplaceAll(label(feature.ShortName), "\"", "\\\""))
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&b, "  %q -> %q;\n", e.from, e.to)
+		}
+		b.WriteString("}\n")
+	case "mermaid":
+		// Feature numbers are unique, so they make safe mermaid node identifiers
+		id := func(shortName string) string {
+			if feature, ok := graph.feature(shortName); ok {
+				return fmt.Sprintf("f%03d", feature.Number)
+			}
+			return "f_" + strings.ReplaceAll(shortName, "-", "_")
+		}
+		b.WriteString("graph LR\n")
+		for _, feature := range graph.Features {
+			text := strings.ReplaceAll(label(feature.ShortName), "\\n", "<br/>")
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id(feature.ShortName), strings.ReplaceAll(text, "\"", "#quot;"))
+		}
+		for _, e := range edges {
+			fmt.Fprintf(&b, "  %s --> %s\n", id(e.from), id(e.to))
+		}
+	default:
+		return "", fmt.Errorf("unsupported graph format %q (expected dot or mermaid)", format)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,161 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Relations", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		for _, name := range []string{"user-db", "user-auth", "user-profile"} {
+			_, err = spec.CreateNewRequirements(tempDir, name)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("LinkFeatures", func() {
+		It("should record depends-on relations in relations.json", func() {
+			err := spec.LinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			relationsPath := filepath.Join(tempDir, ".spec", "002-user-auth", spec.RelationsFileName)
+			Expect(relationsPath).To(BeAnExistingFile())
+
+			graph, err := spec.BuildDependencyGraph(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(graph.DependsOn["user-auth"]).To(ConsistOf("user-db"))
+		})
+
+		It("should treat blocks as the inverse of depends-on", func() {
+			err := spec.LinkFeatures(tempDir, "user-db", nil, []string{"user-profile"})
+			Expect(err).NotTo(HaveOccurred())
+
+			graph, err := spec.BuildDependencyGraph(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(graph.DependsOn["user-profile"]).To(ConsistOf("user-db"))
+		})
+
+		It("should reject links that create a cycle", func() {
+			Expect(spec.LinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+			Expect(spec.LinkFeatures(tempDir, "user-profile", []string{"user-auth"}, nil)).To(Succeed())
+
+			err := spec.LinkFeatures(tempDir, "user-profile", nil, []string{"user-db"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dependency cycle"))
+
+			relationsPath := filepath.Join(tempDir, ".spec", "003-user-profile", spec.RelationsFileName)
+			content, err := os.ReadFile(relationsPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).NotTo(ContainSubstring("blocks"))
+		})
+
+		It("should reject unknown features and self links", func() {
+			err := spec.LinkFeatures(tempDir, "user-auth", []string{"missing"}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("feature directory not found"))
+
+			err = spec.LinkFeatures(tempDir, "user-auth", []string{"user-auth"}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be linked to itself"))
+		})
+
+		It("should remove links with UnlinkFeatures", func() {
+			Expect(spec.LinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+			Expect(spec.UnlinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+
+			graph, err := spec.BuildDependencyGraph(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(graph.DependsOn["user-auth"]).To(BeEmpty())
+		})
+
+		It("should remove links recorded on the other feature", func() {
+			Expect(spec.LinkFeatures(tempDir, "user-db", nil, []string{"user-auth"})).To(Succeed())
+			Expect(spec.LinkFeatures(tempDir, "user-auth", nil, []string{"user-profile"})).To(Succeed())
+			Expect(spec.UnlinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+			Expect(spec.UnlinkFeatures(tempDir, "user-profile", []string{"user-auth"}, nil)).To(Succeed())
+
+			graph, err := spec.BuildDependencyGraph(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(graph.DependsOn).To(BeEmpty())
+		})
+
+		It("should fail without a relation or for features that are not linked", func() {
+			err := spec.UnlinkFeatures(tempDir, "user-auth", nil, nil)
+			Expect(err).To(MatchError(ContainSubstring("at least one --depends-on or --blocks")))
+
+			err = spec.UnlinkFeatures(tempDir, "user-auth", nil, []string{"user-db"})
+			Expect(err).To(MatchError("feature user-db does not depend on user-auth"))
+		})
+	})
+
+	Describe("IncompleteDependencies", func() {
+		It("should list dependencies that are not complete", func() {
+			Expect(spec.LinkFeatures(tempDir, "user-auth", []string{"user-db", "user-profile"}, nil)).To(Succeed())
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-db", "Implementation Complete")).To(Succeed())
+
+			incomplete, err := spec.IncompleteDependencies(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(incomplete).To(HaveLen(1))
+			Expect(incomplete[0].ShortName).To(Equal("user-profile"))
+		})
+
+		It("should still allow the status update when dependencies are incomplete", func() {
+			Expect(spec.LinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+
+			err := spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not block the status update on unreadable relations of other features", func() {
+			relationsPath := filepath.Join(tempDir, ".spec", "003-user-profile", spec.RelationsFileName)
+			Expect(os.WriteFile(relationsPath, []byte("{not json"), 0644)).To(Succeed())
+
+			err := spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("RenderDependencyGraph", func() {
+		BeforeEach(func() {
+			Expect(spec.LinkFeatures(tempDir, "user-auth", []string{"user-db"}, nil)).To(Succeed())
+		})
+
+		It("should render dot output", func() {
+			output, err := spec.RenderDependencyGraph(tempDir, "dot")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HavePrefix("digraph features {"))
+			Expect(output).To(ContainSubstring(`"user-db" -> "user-auth";`))
+		})
+
+		It("should render mermaid output", func() {
+			output, err := spec.RenderDependencyGraph(tempDir, "mermaid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(HavePrefix("graph LR"))
+			Expect(output).To(ContainSubstring("f001 --> f002"))
+			Expect(output).To(ContainSubstring("001-user-db<br/>requirements-gathering"))
+		})
+
+		It("should reject unknown formats", func() {
+			_, err := spec.RenderDependencyGraph(tempDir, "png")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported graph format"))
+		})
+	})
+})
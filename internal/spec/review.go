@@ -0,0 +1,368 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReviewsFileName is the per-feature file holding review comments and approvals
+const ReviewsFileName = "reviews.json"
+
+// ReviewComment is a reviewer's comment on a section of a feature artifact.
+// IDs combine the feature number and a sequence number, e.g. "007-3", so they
+// are unique across the project.
+type ReviewComment struct {
+	ID         string `json:"id"`
+	File       string `json:"file"`
+	Section    string `json:"section,omitempty"`
+	Text       string `json:"text"`
+	By         string `json:"by,omitempty"`
+	Created    string `json:"created"`
+	Resolved   bool   `json:"resolved"`
+	ResolvedBy string `json:"resolved-by,omitempty"`
+	ResolvedAt string `json:"resolved-at,omitempty"`
+}
+
+// Approval records that a reviewer approved a phase of a feature
+type Approval struct {
+	By      string `json:"by"`
+	Phase   string `json:"phase"`
+	Created string `json:"created"`
+}
+
+// Reviews represents the contents of reviews.json
+type Reviews struct {
+	Comments  []ReviewComment `json:"comments"`
+	Approvals []Approval      `json:"approvals"`
+	Sections  []SectionReview `json:"sections,omitempty"`
+}
+
+// CommentOptions describes a new review comment
+type CommentOptions struct {
+	// Section is the heading the comment refers to, if any
+	Section string
+	// File is the artifact commented on. When empty it is the artifact
+	// containing Section, or requirements.md.
+	File string
+	Text string
+	By   string
+}
+
+// readReviews reads reviews.json from a feature directory, returning empty
+// reviews if the file does not exist
+func readReviews(featureDir string) (Reviews, error) {
+	var reviews Reviews
+	data, err := os.ReadFile(filepath.Join(featureDir, ReviewsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reviews, nil
+		}
+		return reviews, fmt.Errorf("failed to read %s: %w", ReviewsFileName, err)
+	}
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return reviews, fmt.Errorf("failed to parse %s in %s: %w", ReviewsFileName, filepath.Base(featureDir), err)
+	}
+	return reviews, nil
+}
+
+// writeReviews writes reviews.json to a feature directory
+func writeReviews(featureDir string, reviews Reviews) error {
+	jsonData, err := json.MarshalIndent(reviews, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reviews: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, ReviewsFileName), jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ReviewsFileName, err)
+	}
+	return nil
+}
+
+// DefaultReviewer returns the name recorded when no reviewer is given: the
+// git user.name of the project, or the USER environment variable
+func DefaultReviewer(targetDir string) string {
+	if name, err := runGit(targetDir, "config", "user.name"); err == nil && name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
+
+// findSection returns the title of the heading of a markdown artifact that
+// matches section, ignoring case
+func findSection(path, section string) (string, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	for _, h := range ParseHeadings(content) {
+		if strings.EqualFold(h.Title, strings.TrimSpace(section)) {
+			return h.Title, true
+		}
+	}
+	return "", false
+}
+
+// AddReviewComment records a review comment in a feature's reviews.json. A
+// section must match a heading of the artifact commented on; without --file
+// requirements.md, the implementation plan and the other artifacts are
+// searched in that order.
+func AddReviewComment(targetDir, shortName string, opts CommentOptions) (ReviewComment, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return ReviewComment{}, err
+	}
+	if strings.TrimSpace(opts.Text) == "" {
+		return ReviewComment{}, fmt.Errorf("comment text cannot be empty")
+	}
+
+	file, section := opts.File, ""
+	if file != "" {
+		if file != filepath.Base(file) || artifactKind(file, nil) == "" {
+			return ReviewComment{}, fmt.Errorf("%s is not an artifact of feature %s", file, shortName)
+		}
+		if _, err := os.Stat(filepath.Join(featureDir, file)); err != nil {
+			return ReviewComment{}, fmt.Errorf("%s not found for feature %s", file, shortName)
+		}
+		if opts.Section != "" {
+			title, ok := findSection(filepath.Join(featureDir, file), opts.Section)
+			if !ok {
+				return ReviewComment{}, fmt.Errorf("section %q not found in %s", opts.Section, file)
+			}
+			section = title
+		}
+	} else if opts.Section == "" {
+		file = "requirements.md"
+	} else {
+		names, err := snapshotArtifacts(featureDir)
+		if err != nil {
+			return ReviewComment{}, err
+		}
+		candidates := append([]string{"requirements.md", "implementation-plan.md"}, names...)
+		for _, name := range candidates {
+			if filepath.Ext(name) != ".md" {
+				continue
+			}
+			if title, ok := findSection(filepath.Join(featureDir, name), opts.Section); ok {
+				file, section = name, title
+				break
+			}
+		}
+		if file == "" {
+			return ReviewComment{}, fmt.Errorf("section %q not found in the artifacts of feature %s", opts.Section, shortName)
+		}
+	}
+
+	reviews, err := readReviews(featureDir)
+	if err != nil {
+		return ReviewComment{}, err
+	}
+	number, _, _ := parseFeatureDirName(filepath.Base(featureDir))
+	next := 1
+	for _, c := range reviews.Comments {
+		if _, seq, ok := strings.Cut(c.ID, "-"); ok {
+			if n, err := strconv.Atoi(seq); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	comment := ReviewComment{
+		ID:      fmt.Sprintf("%03d-%d", number, next),
+		File:    file,
+		Section: section,
+		Text:    strings.TrimSpace(opts.Text),
+		By:      opts.By,
+		Created: time.Now().UTC().Format(time.RFC3339),
+	}
+	reviews.Comments = append(reviews.Comments, comment)
+	if err := writeReviews(featureDir, reviews); err != nil {
+		return ReviewComment{}, err
+	}
+	return comment, nil
+}
+
+// ListReviews returns the review comments and approvals of a feature
+func ListReviews(targetDir, shortName string) (Reviews, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return Reviews{}, err
+	}
+	return readReviews(featureDir)
+}
+
+// ResolveReviewComment marks a comment as resolved. The feature is found from
+// the number in the comment ID.
+func ResolveReviewComment(targetDir, id, by string) (ReviewComment, error) {
+	numberPart, seqPart, ok := strings.Cut(id, "-")
+	number, err := strconv.Atoi(numberPart)
+	seq, seqErr := strconv.Atoi(seqPart)
+	if !ok || err != nil || seqErr != nil {
+		return ReviewComment{}, fmt.Errorf("invalid comment ID %q (expected e.g. 007-3)", id)
+	}
+	id = fmt.Sprintf("%03d-%d", number, seq)
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return ReviewComment{}, err
+	}
+
+	for _, feature := range features {
+		if feature.Number != number {
+			continue
+		}
+		reviews, err := readReviews(feature.Dir)
+		if err != nil {
+			return ReviewComment{}, err
+		}
+		for i, c := range reviews.Comments {
+			if c.ID != id {
+				continue
+			}
+			if c.Resolved {
+				return ReviewComment{}, fmt.Errorf("comment %s is already resolved", id)
+			}
+			reviews.Comments[i].Resolved = true
+			reviews.Comments[i].ResolvedBy = by
+			reviews.Comments[i].ResolvedAt = time.Now().UTC().Format(time.RFC3339)
+			if err := writeReviews(feature.Dir, reviews); err != nil {
+				return ReviewComment{}, err
+			}
+			return reviews.Comments[i], nil
+		}
+	}
+	return ReviewComment{}, fmt.Errorf("comment %s not found", id)
+}
+
+// reviewPhase returns the phase under review for a workflow step: the
+// requirements until implementation planning starts, then the plan
+func reviewPhase(step string) string {
+	if StepIndex(step) >= StepIndex("Implementation Planning") {
+		return QAPhaseImplementationPlan
+	}
+	return QAPhaseRequirements
+}
+
+// ApproveFeature records a reviewer's approval of a phase of a feature. The
+// phase defaults to the one the feature's status is in. Approving twice
+// updates the approval's time.
+func ApproveFeature(targetDir, shortName, by, phase string) (Approval, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return Approval{}, err
+	}
+	if strings.TrimSpace(by) == "" {
+		return Approval{}, fmt.Errorf("reviewer name cannot be empty")
+	}
+	if phase == "" {
+		status, err := readFeatureStatus(featureDir)
+		if err != nil && !os.IsNotExist(err) {
+			return Approval{}, err
+		}
+		phase = reviewPhase(status.CurrentStep)
+	}
+	if phase != QAPhaseRequirements && phase != QAPhaseImplementationPlan {
+		return Approval{}, fmt.Errorf("unknown phase %q (expected requirements or implementation-plan)", phase)
+	}
+
+	reviews, err := readReviews(featureDir)
+	if err != nil {
+		return Approval{}, err
+	}
+	approval := Approval{By: strings.TrimSpace(by), Phase: phase, Created: time.Now().UTC().Format(time.RFC3339)}
+	replaced := false
+	for i, a := range reviews.Approvals {
+		if strings.EqualFold(a.By, approval.By) && a.Phase == phase {
+			reviews.Approvals[i] = approval
+			replaced = true
+		}
+	}
+	if !replaced {
+		reviews.Approvals = append(reviews.Approvals, approval)
+	}
+	if err := writeReviews(featureDir, reviews); err != nil {
+		return Approval{}, err
+	}
+	return approval, nil
+}
+
+// Approvers returns the reviewers who approved a phase
+func (r Reviews) Approvers(phase string) []string {
+	var names []string
+	for _, a := range r.Approvals {
+		if a.Phase == phase {
+			names = append(names, a.By)
+		}
+	}
+	return names
+}
+
+// Unresolved returns the comments that have not been resolved
+func (r Reviews) Unresolved() []ReviewComment {
+	var unresolved []ReviewComment
+	for _, c := range r.Comments {
+		if !c.Resolved {
+			unresolved = append(unresolved, c)
+		}
+	}
+	return unresolved
+}
+
+// checkApprovals runs when a feature leaves a phase under review: entering
+// "Implementation Planning" ends the requirements review and entering
+// "Implementation In Progress" ends the plan review. A transition crossing both
+// checks both phases. The transition is refused while a phase has fewer than
+// review.required_approvals approvals. Unresolved comments are written to out
+// as warnings.
+func checkApprovals(targetDir, featureDir, shortName, from, to string, force bool, out io.Writer) error {
+	var phases []string
+	if enteringStep("Implementation Planning", from, to) {
+		phases = append(phases, QAPhaseRequirements)
+	}
+	if enteringStep("Implementation In Progress", from, to) {
+		phases = append(phases, QAPhaseImplementationPlan)
+	}
+	if len(phases) == 0 {
+		return nil
+	}
+
+	reviews, err := readReviews(featureDir)
+	if err != nil {
+		return err
+	}
+	if unresolved := reviews.Unresolved(); len(unresolved) > 0 {
+		var ids []string
+		for _, c := range unresolved {
+			ids = append(ids, c.ID)
+		}
+		fmt.Fprintf(out, "Warning: feature %s is entering '%s' with %d unresolved review comment(s): %s\n",
+			shortName, to, len(unresolved), strings.Join(ids, ", "))
+	}
+
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return err
+	}
+	required := config.Review.RequiredApprovals
+	if required == 0 {
+		return nil
+	}
+	var gaps []string
+	for _, phase := range phases {
+		if approvers := reviews.Approvers(phase); len(approvers) < required {
+			gaps = append(gaps, fmt.Sprintf("%d of %d required %s approvals", len(approvers), required, phase))
+		}
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+	if force {
+		fmt.Fprintf(out, "Warning: feature %s is entering '%s' with %s\n", shortName, to, strings.Join(gaps, " and "))
+		return nil
+	}
+	return fmt.Errorf("feature %s cannot enter '%s' with %s (see 'specware review approve')",
+		shortName, to, strings.Join(gaps, " and "))
+}
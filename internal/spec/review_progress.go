@@ -0,0 +1,231 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Section review states
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	// ReviewChanged marks an approved section whose content changed since
+	ReviewChanged = "changed"
+)
+
+// SectionReview records the review state of a section of a feature document
+// in reviews.json. Hash is the content hash at the time of approval.
+type SectionReview struct {
+	File    string `json:"file"`
+	Section string `json:"section"`
+	State   string `json:"state"`
+	By      string `json:"by,omitempty"`
+	Updated string `json:"updated"`
+	Hash    string `json:"hash"`
+}
+
+// SectionProgress is the review state of one section of a document
+type SectionProgress struct {
+	Section string `json:"section"`
+	Line    int    `json:"line"`
+	State   string `json:"state"`
+	By      string `json:"by,omitempty"`
+	Updated string `json:"updated,omitempty"`
+}
+
+// ReviewProgress is the section-by-section review state of a document
+type ReviewProgress struct {
+	File     string            `json:"file"`
+	Sections []SectionProgress `json:"sections"`
+}
+
+// Approved returns the number of approved sections
+func (p ReviewProgress) Approved() int {
+	approved := 0
+	for _, s := range p.Sections {
+		if s.State == ReviewApproved {
+			approved++
+		}
+	}
+	return approved
+}
+
+// NextPending returns the first section that is not approved, if any
+func (p ReviewProgress) NextPending() (SectionProgress, bool) {
+	for _, s := range p.Sections {
+		if s.State != ReviewApproved {
+			return s, true
+		}
+	}
+	return SectionProgress{}, false
+}
+
+// sectionHash hashes the content below a heading, including sub-sections,
+// ignoring surrounding blank lines and line endings
+func sectionHash(content []byte, section DocumentSection) string {
+	body := strings.ReplaceAll(string(content[section.BodyStart:section.End]), "\r\n", "\n")
+	sum := sha256.Sum256([]byte(strings.Trim(body, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// reviewedSections returns the sections of a document that are reviewed: all
+// headings except a single level-1 title spanning the document, which is also
+// left out of the paths so renaming the title keeps approvals
+func reviewedSections(content []byte) []DocumentSection {
+	sections := documentSections(content)
+	titles := 0
+	for _, s := range sections {
+		if s.Level == 1 {
+			titles++
+		}
+	}
+	if titles != 1 || len(sections) == 1 || sections[0].Level != 1 {
+		return sections
+	}
+	prefix := sections[0].Path + " > "
+	var reviewed []DocumentSection
+	for _, s := range sections[1:] {
+		s.Path = strings.TrimPrefix(s.Path, prefix)
+		reviewed = append(reviewed, s)
+	}
+	return reviewed
+}
+
+// reviewDocument returns the document reviewed by default: the requirements
+// until implementation planning starts, then the plan
+func reviewDocument(featureDir, doc string) (string, error) {
+	if doc != "" {
+		return doc, nil
+	}
+	status, err := readFeatureStatus(featureDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if reviewPhase(status.CurrentStep) == QAPhaseImplementationPlan {
+		return "plan", nil
+	}
+	return "requirements", nil
+}
+
+// MarkSection records the review state of a section of a feature document.
+// Approving stores the section's content hash so the approval is withdrawn
+// when the section changes; marking it pending removes the record. The
+// document defaults to the one under review for the feature's status.
+func MarkSection(targetDir, shortName, doc, heading, state, by string) (SectionReview, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return SectionReview{}, err
+	}
+	if state != ReviewApproved && state != ReviewPending {
+		return SectionReview{}, fmt.Errorf("unknown review state %q (expected approved or pending)", state)
+	}
+	doc, err = reviewDocument(featureDir, doc)
+	if err != nil {
+		return SectionReview{}, err
+	}
+	path, content, err := readDocument(targetDir, shortName, doc)
+	if err != nil {
+		return SectionReview{}, err
+	}
+	name := filepath.Base(path)
+	found, err := findDocumentSection(content, name, heading)
+	if err != nil {
+		return SectionReview{}, err
+	}
+	var section DocumentSection
+	for _, s := range reviewedSections(content) {
+		if s.Start == found.Start {
+			section = s
+		}
+	}
+	if section.Path == "" {
+		return SectionReview{}, fmt.Errorf("the title of %s is not reviewed; mark its sections instead", name)
+	}
+
+	reviews, err := readReviews(featureDir)
+	if err != nil {
+		return SectionReview{}, err
+	}
+	record := SectionReview{
+		File:    name,
+		Section: section.Path,
+		State:   state,
+		By:      by,
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Hash:    sectionHash(content, section),
+	}
+	var kept []SectionReview
+	for _, r := range reviews.Sections {
+		if r.File != name || r.Section != section.Path {
+			kept = append(kept, r)
+		}
+	}
+	if state == ReviewApproved {
+		kept = append(kept, record)
+	}
+	reviews.Sections = kept
+	if err := writeReviews(featureDir, reviews); err != nil {
+		return SectionReview{}, err
+	}
+	return record, nil
+}
+
+// GetReviewProgress returns the review state of every section of a feature
+// document. Approvals of sections whose content hash no longer matches are
+// changed to ReviewChanged and saved, so they stay withdrawn even if the
+// section is later changed back.
+func GetReviewProgress(targetDir, shortName, doc string) (ReviewProgress, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return ReviewProgress{}, err
+	}
+	doc, err = reviewDocument(featureDir, doc)
+	if err != nil {
+		return ReviewProgress{}, err
+	}
+	path, content, err := readDocument(targetDir, shortName, doc)
+	if err != nil {
+		return ReviewProgress{}, err
+	}
+	name := filepath.Base(path)
+
+	reviews, err := readReviews(featureDir)
+	if err != nil {
+		return ReviewProgress{}, err
+	}
+	records := make(map[string]int)
+	for i, r := range reviews.Sections {
+		if r.File == name {
+			records[r.Section] = i
+		}
+	}
+
+	progress := ReviewProgress{File: name}
+	invalidated := false
+	for _, section := range reviewedSections(content) {
+		entry := SectionProgress{Section: section.Path, Line: section.Line, State: ReviewPending}
+		if i, ok := records[section.Path]; ok {
+			record := &reviews.Sections[i]
+			if record.State == ReviewApproved && record.Hash != sectionHash(content, section) {
+				record.State = ReviewChanged
+				invalidated = true
+			}
+			entry.State = record.State
+			entry.By = record.By
+			entry.Updated = record.Updated
+		}
+		progress.Sections = append(progress.Sections, entry)
+	}
+
+	if invalidated {
+		if err := writeReviews(featureDir, reviews); err != nil {
+			return ReviewProgress{}, err
+		}
+	}
+	return progress, nil
+}
@@ -0,0 +1,126 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Section review progress", func() {
+	var (
+		tempDir          string
+		requirementsPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-review-progress-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		requirementsPath = filepath.Join(tempDir, ".spec", "001-user-auth", "requirements.md")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("lists every section below the title as pending", func() {
+		progress, err := spec.GetReviewProgress(tempDir, "user-auth", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.File).To(Equal("requirements.md"))
+		Expect(progress.Sections).NotTo(BeEmpty())
+		Expect(progress.Sections[0].Section).To(Equal("Problem Statement"))
+		Expect(progress.Approved()).To(Equal(0))
+		for _, s := range progress.Sections {
+			Expect(s.State).To(Equal(spec.ReviewPending))
+		}
+	})
+
+	It("records approvals and resumes at the next pending section", func() {
+		record, err := spec.MarkSection(tempDir, "user-auth", "", "problem statement", spec.ReviewApproved, "alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.Section).To(Equal("Problem Statement"))
+		Expect(record.Hash).NotTo(BeEmpty())
+
+		progress, err := spec.GetReviewProgress(tempDir, "user-auth", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.Sections[0].State).To(Equal(spec.ReviewApproved))
+		Expect(progress.Sections[0].By).To(Equal("alice"))
+		Expect(progress.Approved()).To(Equal(1))
+		next, ok := progress.NextPending()
+		Expect(ok).To(BeTrue())
+		Expect(next.Section).To(Equal("Solution Overview"))
+	})
+
+	It("withdraws approval when the section content changes", func() {
+		_, err := spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", spec.ReviewApproved, "alice")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.MarkSection(tempDir, "user-auth", "requirements", "Solution Overview", spec.ReviewApproved, "alice")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(spec.SetSection(tempDir, "user-auth", "requirements", "Problem Statement", []byte("Users are locked out."))).To(Succeed())
+
+		progress, err := spec.GetReviewProgress(tempDir, "user-auth", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.Sections[0].State).To(Equal(spec.ReviewChanged))
+		Expect(progress.Sections[1].State).To(Equal(spec.ReviewApproved))
+
+		_, err = spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", spec.ReviewApproved, "bob")
+		Expect(err).NotTo(HaveOccurred())
+		progress, err = spec.GetReviewProgress(tempDir, "user-auth", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.Sections[0].State).To(Equal(spec.ReviewApproved))
+		Expect(progress.Approved()).To(Equal(2))
+	})
+
+	It("keeps approvals when the title or other sections change", func() {
+		_, err := spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", spec.ReviewApproved, "alice")
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := os.ReadFile(requirementsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(requirementsPath, []byte(strings.Replace(string(content), "[Feature Name]", "User Auth", 1)), 0644)).To(Succeed())
+		Expect(spec.SetSection(tempDir, "user-auth", "requirements", "Solution Overview", []byte("Email links."))).To(Succeed())
+
+		progress, err := spec.GetReviewProgress(tempDir, "user-auth", "requirements")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.Sections[0].State).To(Equal(spec.ReviewApproved))
+	})
+
+	It("resets sections to pending", func() {
+		_, err := spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", spec.ReviewApproved, "alice")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", spec.ReviewPending, "alice")
+		Expect(err).NotTo(HaveOccurred())
+
+		reviews, err := spec.ListReviews(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reviews.Sections).To(BeEmpty())
+	})
+
+	It("defaults to the implementation plan once planning has started", func() {
+		_, err := spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Implementation Planning", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+
+		progress, err := spec.GetReviewProgress(tempDir, "user-auth", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress.File).To(Equal("implementation-plan.md"))
+	})
+
+	It("rejects unknown states and the document title", func() {
+		_, err := spec.MarkSection(tempDir, "user-auth", "requirements", "Problem Statement", "done", "alice")
+		Expect(err).To(MatchError(ContainSubstring("unknown review state")))
+
+		_, err = spec.MarkSection(tempDir, "user-auth", "requirements", "Requirements Specification: [Feature Name]", spec.ReviewApproved, "alice")
+		Expect(err).To(MatchError(ContainSubstring("is not reviewed")))
+	})
+})
@@ -0,0 +1,182 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Reviews", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-review-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Describe("comments", func() {
+		It("records comments on sections with feature-numbered IDs", func() {
+			first, err := spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{
+				Section: "acceptance criteria",
+				Text:    "How long is a link valid?",
+				By:      "alice",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.ID).To(Equal("001-1"))
+			Expect(first.File).To(Equal("requirements.md"))
+			Expect(first.Section).To(Equal("Acceptance Criteria"))
+
+			second, err := spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{Text: "Looks good overall"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.ID).To(Equal("001-2"))
+
+			reviews, err := spec.ListReviews(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews.Comments).To(HaveLen(2))
+			Expect(reviews.Unresolved()).To(HaveLen(2))
+
+			_, err = os.Stat(filepath.Join(tempDir, ".spec", "001-user-auth", spec.ReviewsFileName))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("finds sections in the implementation plan", func() {
+			_, err := spec.CreateNewImplementationPlan(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			plan, err := os.ReadFile(filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md"))
+			Expect(err).NotTo(HaveOccurred())
+			headings := spec.ParseHeadings(plan)
+			Expect(headings).NotTo(BeEmpty())
+			title := headings[len(headings)-1].Title
+
+			comment, err := spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{Section: title, Text: "Split this up"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(comment.File).To(Equal("implementation-plan.md"))
+		})
+
+		It("rejects unknown sections, files and empty text", func() {
+			_, err := spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{Section: "Nope", Text: "x"})
+			Expect(err).To(MatchError(ContainSubstring(`section "Nope" not found`)))
+
+			_, err = spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{File: ".spec-status.json", Text: "x"})
+			Expect(err).To(MatchError(ContainSubstring("is not an artifact")))
+
+			_, err = spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{Text: "  "})
+			Expect(err).To(MatchError(ContainSubstring("cannot be empty")))
+		})
+
+		It("resolves comments by ID without the feature name", func() {
+			_, err := spec.AddReviewComment(tempDir, "user-auth", spec.CommentOptions{Text: "Clarify scope"})
+			Expect(err).NotTo(HaveOccurred())
+
+			resolved, err := spec.ResolveReviewComment(tempDir, "1-1", "bob")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.ID).To(Equal("001-1"))
+			Expect(resolved.Resolved).To(BeTrue())
+			Expect(resolved.ResolvedBy).To(Equal("bob"))
+
+			reviews, err := spec.ListReviews(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews.Unresolved()).To(BeEmpty())
+
+			_, err = spec.ResolveReviewComment(tempDir, "001-1", "bob")
+			Expect(err).To(MatchError(ContainSubstring("already resolved")))
+			_, err = spec.ResolveReviewComment(tempDir, "001-9", "bob")
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+			_, err = spec.ResolveReviewComment(tempDir, "abc", "bob")
+			Expect(err).To(MatchError(ContainSubstring("invalid comment ID")))
+		})
+	})
+
+	Describe("approvals", func() {
+		It("records one approval per reviewer and phase", func() {
+			approval, err := spec.ApproveFeature(tempDir, "user-auth", "alice", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approval.Phase).To(Equal(spec.QAPhaseRequirements))
+
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "Alice", "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "bob", spec.QAPhaseImplementationPlan)
+			Expect(err).NotTo(HaveOccurred())
+
+			reviews, err := spec.ListReviews(tempDir, "user-auth")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews.Approvers(spec.QAPhaseRequirements)).To(Equal([]string{"Alice"}))
+			Expect(reviews.Approvers(spec.QAPhaseImplementationPlan)).To(Equal([]string{"bob"}))
+
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "", "")
+			Expect(err).To(HaveOccurred())
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "alice", "design")
+			Expect(err).To(MatchError(ContainSubstring("unknown phase")))
+		})
+
+		It("defaults to the implementation plan once planning has started", func() {
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Implementation Planning", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+			approval, err := spec.ApproveFeature(tempDir, "user-auth", "alice", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approval.Phase).To(Equal(spec.QAPhaseImplementationPlan))
+		})
+
+		It("gates status transitions on review.required_approvals", func() {
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Requirements Complete", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+			_, err := spec.SetConfigValue(tempDir, "review.required_approvals", "2")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Planning")
+			Expect(err).To(MatchError(ContainSubstring("0 of 2 required requirements approvals")))
+
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "alice", "")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "bob", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Planning")).To(Succeed())
+
+			err = spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")
+			Expect(err).To(MatchError(ContainSubstring("required implementation-plan approvals")))
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Implementation In Progress", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+		})
+
+		It("checks both phases when a transition skips planning", func() {
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Requirements Complete", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+			_, err := spec.SetConfigValue(tempDir, "review.required_approvals", "1")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "alice", spec.QAPhaseImplementationPlan)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")
+			Expect(err).To(MatchError(ContainSubstring("0 of 1 required requirements approvals")))
+			Expect(err.Error()).NotTo(ContainSubstring("implementation-plan approvals"))
+
+			_, err = spec.ApproveFeature(tempDir, "user-auth", "alice", spec.QAPhaseRequirements)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation In Progress")).To(Succeed())
+		})
+
+		It("does not gate transitions by default", func() {
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Requirements Complete", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Planning")).To(Succeed())
+		})
+	})
+
+	It("does not treat reviews.json as an artifact", func() {
+		_, err := spec.ApproveFeature(tempDir, "user-auth", "alice", "")
+		Expect(err).NotTo(HaveOccurred())
+		artifacts, err := spec.ListArtifacts(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		for _, a := range artifacts {
+			Expect(a.Name).NotTo(Equal(spec.ReviewsFileName))
+		}
+	})
+})
@@ -0,0 +1,215 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Artifact kinds used to classify files within a feature directory
+const (
+	ArtifactRequirements = "requirements"
+	ArtifactPlan         = "plan"
+	ArtifactContext      = "context"
+	ArtifactSpec         = "spec"
+	ArtifactDocument     = "document"
+)
+
+// SearchOptions restricts which artifacts and features are searched
+type SearchOptions struct {
+	// In limits the search to one artifact kind (requirements, plan, context,
+	// spec, document)
+	In string
+	// Status limits the search to features whose current step matches
+	Status string
+}
+
+// SearchMatch is a single matching line
+type SearchMatch struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// SearchSection groups matches within one section of one file
+type SearchSection struct {
+	File    string        `json:"file"`
+	Heading string        `json:"heading,omitempty"`
+	Score   int           `json:"score"`
+	Matches []SearchMatch `json:"matches"`
+}
+
+// SearchResult groups all matches for a single feature
+type SearchResult struct {
+	Feature   string          `json:"feature"`
+	ShortName string          `json:"short-name"`
+	Status    string          `json:"current-step,omitempty"`
+	Score     int             `json:"score"`
+	Sections  []SearchSection `json:"sections"`
+}
+
+// artifactKind classifies a file within a feature directory. Files that are
+// not specification artifacts (status and metadata files) return "".
+// documents holds the file names of configured artifact types (see
+// artifactDocuments); it may be nil when only artifacts need telling apart
+// from other files.
+func artifactKind(fileName string, documents map[string]bool) string {
+	switch {
+	case strings.HasPrefix(fileName, "."):
+		return ""
+	case fileName == "requirements.md":
+		return ArtifactRequirements
+	case fileName == "implementation-plan.md":
+		return ArtifactPlan
+	case strings.HasPrefix(fileName, "context-") && strings.HasSuffix(fileName, ".md"):
+		return ArtifactContext
+	case fileName == RelationsFileName, fileName == SourceFileName, fileName == SpecManifestFileName,
+		fileName == ReviewsFileName:
+		return ""
+	case documents[fileName]:
+		return ArtifactDocument
+	default:
+		return ArtifactSpec
+	}
+}
+
+// SearchSpecs performs a case-insensitive full-text search across the
+// specification artifacts of every feature. Results are ranked by score.
+func SearchSpecs(targetDir, query string, opts SearchOptions) ([]SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+
+	switch opts.In {
+	case "", ArtifactRequirements, ArtifactPlan, ArtifactContext, ArtifactSpec, ArtifactDocument:
+	default:
+		return nil, fmt.Errorf("unknown artifact kind %q (expected requirements, plan, context, spec or document)", opts.In)
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	documents := artifactDocuments(config)
+
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, feature := range features {
+		if opts.Status != "" && !stepsEqual(feature.Status, opts.Status) {
+			continue
+		}
+
+		entries, err := os.ReadDir(feature.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feature directory %s: %w", feature.Name, err)
+		}
+
+		result := SearchResult{
+			Feature:   feature.Name,
+			ShortName: feature.ShortName,
+			Status:    feature.Status,
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			kind := artifactKind(entry.Name(), documents)
+			if kind == "" || (opts.In != "" && kind != opts.In) {
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(feature.Dir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+			}
+			if bytes.IndexByte(content, 0) >= 0 {
+				continue // skip binary files
+			}
+
+			for _, section := range searchContent(entry.Name(), content, terms) {
+				result.Score += section.Score
+				result.Sections = append(result.Sections, section)
+			}
+		}
+
+		if len(result.Sections) > 0 {
+			sort.SliceStable(result.Sections, func(i, j int) bool {
+				return result.Sections[i].Score > result.Sections[j].Score
+			})
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+// searchContent finds matching lines in a single file, grouped by heading
+func searchContent(fileName string, content []byte, terms []string) []SearchSection {
+	phrase := strings.Join(terms, " ")
+	headings := ParseHeadings(content)
+	isMarkdown := strings.HasSuffix(fileName, ".md")
+
+	var sections []SearchSection
+	index := make(map[string]int)
+
+	text := string(content)
+	offset := 0
+	lineNum := 0
+	for offset < len(text) {
+		lineNum++
+		next := len(text)
+		if i := strings.IndexByte(text[offset:], '\n'); i >= 0 {
+			next = offset + i + 1
+		}
+		line := strings.TrimRight(text[offset:next], "\r\n")
+		lineOffset := offset
+		offset = next
+
+		lower := strings.ToLower(line)
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				score++
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		if len(terms) > 1 && strings.Contains(lower, phrase) {
+			score += len(terms)
+		}
+
+		heading := ""
+		if isMarkdown {
+			if h, ok := headingAt(headings, lineOffset); ok {
+				heading = h.Title
+				if h.Start == lineOffset {
+					score *= 2 // matches in headings are the strongest signal
+				}
+			}
+		}
+
+		key := heading
+		i, ok := index[key]
+		if !ok {
+			sections = append(sections, SearchSection{File: fileName, Heading: heading})
+			i = len(sections) - 1
+			index[key] = i
+		}
+		sections[i].Score += score
+		sections[i].Matches = append(sections[i].Matches, SearchMatch{
+			Line: lineNum,
+			Text: strings.TrimSpace(line),
+		})
+	}
+	return sections
+}
@@ -0,0 +1,86 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/tiwillia/specware/internal/spec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Search", func() {
+	var tempDir string
+
+	writeArtifact := func(feature, file, content string) {
+		path := filepath.Join(tempDir, ".spec", feature, file)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "api-gateway")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeArtifact("001-api-gateway", "requirements.md",
+			"# Requirements\n\n## Rate Limiting\nRequests are rate limited per client.\n\n## Constraints\nNo rate limiting for admins.\n")
+		writeArtifact("002-user-auth", "requirements.md",
+			"# Requirements\n\n## Functional Requirements\nLogin attempts are limited.\n")
+		writeArtifact("002-user-auth", "openapi.yaml", "paths:\n  /login:\n    description: rate limiting applies\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should group matches by feature and section heading", func() {
+		results, err := spec.SearchSpecs(tempDir, "rate limiting", spec.SearchOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].Feature).To(Equal("001-api-gateway"))
+		Expect(results[0].Sections[0].File).To(Equal("requirements.md"))
+		Expect(results[0].Sections[0].Heading).To(Equal("Rate Limiting"))
+		Expect(results[0].Sections[0].Matches[0].Line).To(Equal(3))
+	})
+
+	It("should search technical specification artifacts", func() {
+		results, err := spec.SearchSpecs(tempDir, "rate", spec.SearchOptions{In: spec.ArtifactSpec})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].ShortName).To(Equal("user-auth"))
+		Expect(results[0].Sections[0].File).To(Equal("openapi.yaml"))
+	})
+
+	It("should filter by artifact kind", func() {
+		results, err := spec.SearchSpecs(tempDir, "rate", spec.SearchOptions{In: spec.ArtifactPlan})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+	})
+
+	It("should filter by status", func() {
+		Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Requirements Complete")).To(Succeed())
+
+		results, err := spec.SearchSpecs(tempDir, "limit", spec.SearchOptions{Status: "requirements-complete"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].ShortName).To(Equal("user-auth"))
+	})
+
+	It("should reject empty queries and unknown artifact kinds", func() {
+		_, err := spec.SearchSpecs(tempDir, "  ", spec.SearchOptions{})
+		Expect(err).To(HaveOccurred())
+
+		_, err = spec.SearchSpecs(tempDir, "rate", spec.SearchOptions{In: "docs"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unknown artifact kind"))
+	})
+})
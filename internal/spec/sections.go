@@ -0,0 +1,175 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentSection is a heading of a markdown artifact with its path
+type DocumentSection struct {
+	Heading
+	Path string `json:"path"`
+}
+
+// documentAliases are short names accepted for the main artifacts
+var documentAliases = map[string]string{
+	"requirements":        "requirements.md",
+	"plan":                "implementation-plan.md",
+	"implementation-plan": "implementation-plan.md",
+}
+
+// documentFileName resolves a document argument such as "requirements",
+// "plan" or "context-requirements.md" to a markdown artifact file name
+func documentFileName(doc string) (string, error) {
+	name := doc
+	if alias, ok := documentAliases[doc]; ok {
+		name = alias
+	} else if filepath.Ext(name) == "" {
+		name += ".md"
+	}
+	if name != filepath.Base(name) || filepath.Ext(name) != ".md" || artifactKind(name, nil) == "" {
+		return "", fmt.Errorf("%s is not a markdown document of a feature (expected requirements, plan or an artifact name)", doc)
+	}
+	return name, nil
+}
+
+// readDocument reads a markdown artifact of a feature
+func readDocument(targetDir, shortName, doc string) (string, []byte, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return "", nil, err
+	}
+	name, err := documentFileName(doc)
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(featureDir, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf("%s not found for feature %s", name, shortName)
+		}
+		return "", nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return path, content, nil
+}
+
+// documentSections returns the headings of a markdown document with their paths
+func documentSections(content []byte) []DocumentSection {
+	headings := ParseHeadings(content)
+	paths := headingPaths(headings)
+	sections := make([]DocumentSection, len(headings))
+	for i, h := range headings {
+		sections[i] = DocumentSection{Heading: h, Path: paths[i]}
+	}
+	return sections
+}
+
+// findDocumentSection returns the section matching a heading title, or a
+// path of titles such as "Milestone 1 > Phase 2" for titles that repeat.
+// Matching ignores case.
+func findDocumentSection(content []byte, name, heading string) (DocumentSection, error) {
+	heading = strings.TrimSpace(heading)
+	var matches []DocumentSection
+	for _, s := range documentSections(content) {
+		path := strings.ToLower(s.Path)
+		want := strings.ToLower(heading)
+		if strings.EqualFold(s.Title, heading) || path == want || strings.HasSuffix(path, " > "+want) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return DocumentSection{}, fmt.Errorf("section %q not found in %s", heading, name)
+	case 1:
+		return matches[0], nil
+	default:
+		var candidates []string
+		for _, m := range matches {
+			candidates = append(candidates, fmt.Sprintf("%q (line %d)", m.Path, m.Line))
+		}
+		return DocumentSection{}, fmt.Errorf("section %q is ambiguous in %s; use one of: %s",
+			heading, name, strings.Join(candidates, ", "))
+	}
+}
+
+// ListSections returns the headings of a feature document in order
+func ListSections(targetDir, shortName, doc string) ([]DocumentSection, error) {
+	_, content, err := readDocument(targetDir, shortName, doc)
+	if err != nil {
+		return nil, err
+	}
+	return documentSections(content), nil
+}
+
+// GetSection returns the content of a section of a feature document exactly
+// as written, including nested sub-sections and, with withHeading, the
+// heading line itself. Blank lines around the content are not included.
+func GetSection(targetDir, shortName, doc, heading string, withHeading bool) (string, error) {
+	path, content, err := readDocument(targetDir, shortName, doc)
+	if err != nil {
+		return "", err
+	}
+	section, err := findDocumentSection(content, filepath.Base(path), heading)
+	if err != nil {
+		return "", err
+	}
+	start := section.BodyStart
+	if withHeading {
+		start = section.Start
+	}
+	return strings.Trim(string(content[start:section.End]), "\r\n"), nil
+}
+
+// SetSection replaces the content below a heading of a feature document,
+// including nested sub-sections. The heading itself, the blank lines that
+// surrounded the old content and the rest of the document are preserved
+// byte for byte.
+func SetSection(targetDir, shortName, doc, heading string, replacement []byte) error {
+	path, content, err := readDocument(targetDir, shortName, doc)
+	if err != nil {
+		return err
+	}
+	section, err := findDocumentSection(content, filepath.Base(path), heading)
+	if err != nil {
+		return err
+	}
+
+	text := string(content)
+	body := text[section.BodyStart:section.End]
+	newBody := strings.Trim(string(replacement), "\r\n")
+
+	if strings.TrimSpace(body) == "" {
+		// An empty section is separated from the next heading by a blank line
+		if newBody != "" {
+			newBody += "\n"
+			if section.End < len(text) {
+				newBody += "\n"
+			}
+		}
+	} else {
+		trimmed := strings.TrimLeft(body, "\r\n")
+		leading := body[:len(body)-len(trimmed)]
+		core := strings.TrimRight(trimmed, "\r\n")
+		trailing := trimmed[len(core):]
+		if newBody != "" {
+			newBody = leading + newBody + trailing
+		} else if section.End < len(text) {
+			newBody = "\n"
+		}
+	}
+
+	// A heading on the last line without a newline needs one before content
+	if section.BodyStart == len(text) && !strings.HasSuffix(text, "\n") && newBody != "" {
+		newBody = "\n" + newBody
+	}
+
+	updated := text[:section.BodyStart] + newBody + text[section.End:]
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
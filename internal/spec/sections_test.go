@@ -0,0 +1,156 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Document sections", func() {
+	var (
+		tempDir  string
+		planPath string
+	)
+
+	const plan = `# Implementation Plan
+
+## Milestone 1: Backend
+
+### Phase 1: Storage
+- [ ] Step 1: Add table
+
+### Phase 2: API
+- [ ] Step 2: Add endpoint
+
+## Milestone 2: Frontend
+
+### Phase 1: Forms
+- [ ] Step 3: Add form
+
+## Empty
+## Last
+Final words`
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-sections-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		planPath = filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md")
+		Expect(os.WriteFile(planPath, []byte(plan), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("lists headings with their paths", func() {
+		sections, err := spec.ListSections(tempDir, "user-auth", "plan")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sections).To(HaveLen(8))
+		Expect(sections[2].Path).To(Equal("Implementation Plan > Milestone 1: Backend > Phase 1: Storage"))
+		Expect(sections[2].Level).To(Equal(3))
+		Expect(sections[2].Line).To(Equal(5))
+	})
+
+	It("gets sections verbatim, including sub-sections", func() {
+		content, err := spec.GetSection(tempDir, "user-auth", "plan", "phase 2: api", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal("- [ ] Step 2: Add endpoint"))
+
+		content, err = spec.GetSection(tempDir, "user-auth", "implementation-plan.md", "Milestone 2: Frontend", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal("## Milestone 2: Frontend\n\n### Phase 1: Forms\n- [ ] Step 3: Add form"))
+	})
+
+	It("requires a path for repeated titles", func() {
+		_, err := spec.GetSection(tempDir, "user-auth", "plan", "Phase 1: Storage", false)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = spec.GetSection(tempDir, "user-auth", "plan", "Milestone 2: Frontend > Phase 1: Forms", false)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(planPath, []byte("## A\n### Tasks\none\n## B\n### Tasks\ntwo\n"), 0644)).To(Succeed())
+		_, err = spec.GetSection(tempDir, "user-auth", "plan", "Tasks", false)
+		Expect(err).To(MatchError(ContainSubstring("ambiguous")))
+
+		content, err := spec.GetSection(tempDir, "user-auth", "plan", "b > tasks", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal("two"))
+	})
+
+	It("replaces a section and preserves everything else byte for byte", func() {
+		Expect(spec.SetSection(tempDir, "user-auth", "plan", "Phase 2: API", []byte("- [ ] Step 2: Add endpoint\n- [ ] Step 2b: Document it\n"))).To(Succeed())
+		content, err := os.ReadFile(planPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		expected := `# Implementation Plan
+
+## Milestone 1: Backend
+
+### Phase 1: Storage
+- [ ] Step 1: Add table
+
+### Phase 2: API
+- [ ] Step 2: Add endpoint
+- [ ] Step 2b: Document it
+
+## Milestone 2: Frontend
+
+### Phase 1: Forms
+- [ ] Step 3: Add form
+
+## Empty
+## Last
+Final words`
+		Expect(string(content)).To(Equal(expected))
+	})
+
+	It("round-trips get and set without changes", func() {
+		for _, heading := range []string{"Milestone 1: Backend", "Phase 1: Forms", "Last", "Empty"} {
+			content, err := spec.GetSection(tempDir, "user-auth", "plan", heading, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.SetSection(tempDir, "user-auth", "plan", heading, []byte(content+"\n"))).To(Succeed())
+		}
+		content, err := os.ReadFile(planPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(plan))
+	})
+
+	It("fills empty sections and the last section", func() {
+		Expect(spec.SetSection(tempDir, "user-auth", "plan", "Empty", []byte("Now filled"))).To(Succeed())
+		Expect(spec.SetSection(tempDir, "user-auth", "plan", "Last", []byte("Changed"))).To(Succeed())
+		content, err := os.ReadFile(planPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HaveSuffix("## Empty\nNow filled\n\n## Last\nChanged"))
+	})
+
+	It("works on requirements and context documents", func() {
+		Expect(spec.SetSection(tempDir, "user-auth", "requirements", "Problem Statement", []byte("Users are locked out."))).To(Succeed())
+		content, err := spec.GetSection(tempDir, "user-auth", "requirements", "Problem Statement", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal("Users are locked out."))
+
+		_, err = spec.ListSections(tempDir, "user-auth", "context-requirements")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects unknown documents and sections", func() {
+		_, err := spec.ListSections(tempDir, "user-auth", ".spec-status.json")
+		Expect(err).To(MatchError(ContainSubstring("not a markdown document")))
+
+		_, err = spec.ListSections(tempDir, "user-auth", "diagram")
+		Expect(err).To(MatchError(ContainSubstring("diagram.md not found")))
+
+		err = spec.SetSection(tempDir, "user-auth", "plan", "Missing", []byte("x"))
+		Expect(err).To(MatchError(ContainSubstring(`section "Missing" not found`)))
+	})
+})
@@ -0,0 +1,432 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotsDirName is the directory inside a feature holding its snapshots
+const SnapshotsDirName = ".snapshots"
+
+// snapshotMetaFileName records a snapshot's details inside its directory
+const snapshotMetaFileName = ".snapshot.json"
+
+// snapshotIDFormat names snapshots after the UTC time they were taken
+const snapshotIDFormat = "20060102T150405Z"
+
+// diffContextLines is the number of unchanged lines kept around changes
+const diffContextLines = 2
+
+// Snapshot is a stored copy of a feature's artifacts
+type Snapshot struct {
+	ID      string   `json:"id"`
+	Created string   `json:"created"`
+	Reason  string   `json:"reason,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	Files   []string `json:"files"`
+}
+
+// Section diff changes
+const (
+	SectionAdded    = "added"
+	SectionRemoved  = "removed"
+	SectionModified = "modified"
+)
+
+// DiffLine is a line of a section diff. Op is "+" for added lines, "-" for
+// removed lines, " " for context and "..." where unchanged lines are omitted.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// SectionDiff is a changed section of a markdown artifact. Path joins the
+// titles of the section's heading and its parents with " > ".
+type SectionDiff struct {
+	Path   string     `json:"path"`
+	Change string     `json:"change"`
+	Lines  []DiffLine `json:"lines"`
+}
+
+// FileDiff lists the changed sections of one artifact
+type FileDiff struct {
+	Name     string        `json:"name"`
+	Sections []SectionDiff `json:"sections"`
+}
+
+// FeatureDiff compares a snapshot with the current artifacts of a feature
+type FeatureDiff struct {
+	Feature  string     `json:"feature"`
+	Snapshot Snapshot   `json:"snapshot"`
+	Files    []FileDiff `json:"files"`
+}
+
+// diffedArtifacts are the artifacts compared by DiffFeature
+var diffedArtifacts = []string{"requirements.md", "implementation-plan.md"}
+
+// snapshotArtifacts returns the names of the artifacts copied into a snapshot
+func snapshotArtifacts(featureDir string) ([]string, error) {
+	entries, err := os.ReadDir(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature directory: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && artifactKind(entry.Name(), nil) != "" {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// readSnapshots returns the snapshots of a feature, oldest first
+func readSnapshots(featureDir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(featureDir, SnapshotsDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		snapshot := Snapshot{ID: entry.Name()}
+		data, err := os.ReadFile(filepath.Join(featureDir, SnapshotsDirName, entry.Name(), snapshotMetaFileName))
+		if err == nil {
+			if err := json.Unmarshal(data, &snapshot); err != nil {
+				return nil, fmt.Errorf("failed to parse snapshot %s: %w", entry.Name(), err)
+			}
+			snapshot.ID = entry.Name()
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ID < snapshots[j].ID
+	})
+	return snapshots, nil
+}
+
+// ListSnapshots returns the snapshots of a feature, oldest first
+func ListSnapshots(targetDir, shortName string) ([]Snapshot, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	return readSnapshots(featureDir)
+}
+
+// CreateSnapshot stores a timestamped copy of a feature's artifacts under
+// .snapshots/ in the feature directory, recording the reason and the
+// feature's status at the time
+func CreateSnapshot(targetDir, shortName, reason string) (Snapshot, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	status, err := readFeatureStatus(featureDir)
+	if err != nil && !os.IsNotExist(err) {
+		return Snapshot{}, err
+	}
+	return createSnapshot(featureDir, reason, status.CurrentStep)
+}
+
+func createSnapshot(featureDir, reason, status string) (Snapshot, error) {
+	names, err := snapshotArtifacts(featureDir)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	now := time.Now().UTC()
+	id := now.Format(snapshotIDFormat)
+	snapshotsDir := filepath.Join(featureDir, SnapshotsDirName)
+	// Snapshots taken within the same second get a numeric suffix
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(snapshotsDir, id)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format(snapshotIDFormat), n)
+	}
+	snapshotDir := filepath.Join(snapshotsDir, id)
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	snapshot := Snapshot{ID: id, Created: now.Format(time.RFC3339), Reason: reason, Status: status, Files: names}
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(featureDir, name))
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(snapshotDir, name), content, 0644); err != nil {
+			return Snapshot{}, fmt.Errorf("failed to write snapshot of %s: %w", name, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshotDir, snapshotMetaFileName), data, 0644); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// snapshotMatches reports whether a snapshot holds exactly the feature's
+// current artifacts
+func snapshotMatches(featureDir string, snapshot Snapshot) bool {
+	names, err := snapshotArtifacts(featureDir)
+	if err != nil || strings.Join(names, "\n") != strings.Join(snapshot.Files, "\n") {
+		return false
+	}
+	for _, name := range names {
+		current, err := os.ReadFile(filepath.Join(featureDir, name))
+		if err != nil {
+			return false
+		}
+		stored, err := os.ReadFile(filepath.Join(featureDir, SnapshotsDirName, snapshot.ID, name))
+		if err != nil || !bytes.Equal(current, stored) {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotOnTransition snapshots a feature when its status changes, unless
+// the artifacts are unchanged since the latest snapshot. Failures are
+// written to out as warnings and never block the transition.
+func snapshotOnTransition(featureDir, shortName, from, to string, out io.Writer) {
+	if from == "" || stepsEqual(from, to) {
+		return
+	}
+	snapshots, err := readSnapshots(featureDir)
+	if err == nil && len(snapshots) > 0 && snapshotMatches(featureDir, snapshots[len(snapshots)-1]) {
+		return
+	}
+	if err == nil {
+		_, err = createSnapshot(featureDir, fmt.Sprintf("status changed from '%s' to '%s'", from, to), from)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "Warning: failed to snapshot feature %s: %v\n", shortName, err)
+	}
+}
+
+// findSnapshot returns the snapshot with the given ID or unique ID prefix,
+// or the latest snapshot when id is empty
+func findSnapshot(snapshots []Snapshot, shortName, id string) (Snapshot, error) {
+	if len(snapshots) == 0 {
+		return Snapshot{}, fmt.Errorf("feature %s has no snapshots; create one with 'specware feature snapshot %s'", shortName, shortName)
+	}
+	if id == "" {
+		return snapshots[len(snapshots)-1], nil
+	}
+	var matches []Snapshot
+	for _, s := range snapshots {
+		if s.ID == id {
+			return s, nil
+		}
+		if strings.HasPrefix(s.ID, id) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Snapshot{}, fmt.Errorf("snapshot %s not found for feature %s", id, shortName)
+	case 1:
+		return matches[0], nil
+	default:
+		return Snapshot{}, fmt.Errorf("snapshot %s is ambiguous: it matches %d snapshots", id, len(matches))
+	}
+}
+
+// DiffFeature compares requirements.md and implementation-plan.md with a
+// snapshot, section by section. The latest snapshot is used when snapshotID
+// is empty; otherwise it may be an ID or a unique prefix of one. Only changed
+// files and sections are returned.
+func DiffFeature(targetDir, shortName, snapshotID string) (FeatureDiff, error) {
+	var diff FeatureDiff
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return diff, err
+	}
+	snapshots, err := readSnapshots(featureDir)
+	if err != nil {
+		return diff, err
+	}
+	snapshot, err := findSnapshot(snapshots, shortName, snapshotID)
+	if err != nil {
+		return diff, err
+	}
+	diff.Feature = filepath.Base(featureDir)
+	diff.Snapshot = snapshot
+
+	for _, name := range diffedArtifacts {
+		before, err := readOptional(filepath.Join(featureDir, SnapshotsDirName, snapshot.ID, name))
+		if err != nil {
+			return diff, err
+		}
+		after, err := readOptional(filepath.Join(featureDir, name))
+		if err != nil {
+			return diff, err
+		}
+		if sections := diffSections(before, after); len(sections) > 0 {
+			diff.Files = append(diff.Files, FileDiff{Name: name, Sections: sections})
+		}
+	}
+	return diff, nil
+}
+
+// readOptional reads a file, returning nil content if it does not exist
+func readOptional(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return content, nil
+}
+
+// markdownSection is the text between a heading and the next heading
+type markdownSection struct {
+	path  string
+	lines []string
+}
+
+// splitSections splits a markdown document at every heading. Text before the
+// first heading belongs to a section with an empty path, and repeated paths
+// are numbered so each section can be matched between versions.
+func splitSections(content []byte) []markdownSection {
+	text := string(content)
+	headings := ParseHeadings(content)
+
+	var sections []markdownSection
+	add := func(path, body string) {
+		body = strings.Trim(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+		var lines []string
+		if strings.TrimSpace(body) != "" {
+			lines = strings.Split(body, "\n")
+		}
+		sections = append(sections, markdownSection{path: path, lines: lines})
+	}
+
+	preambleEnd := len(text)
+	if len(headings) > 0 {
+		preambleEnd = headings[0].Start
+	}
+	if strings.TrimSpace(text[:preambleEnd]) != "" {
+		add("", text[:preambleEnd])
+	}
+
+	seen := make(map[string]int)
+	paths := headingPaths(headings)
+	for i, h := range headings {
+		path := paths[i]
+		seen[path]++
+		if seen[path] > 1 {
+			path = fmt.Sprintf("%s (%d)", path, seen[path])
+		}
+
+		end := len(text)
+		if i+1 < len(headings) {
+			end = headings[i+1].Start
+		}
+		add(path, text[h.BodyStart:end])
+	}
+	return sections
+}
+
+// diffSections compares two versions of a markdown document section by
+// section, in the order of the new version followed by removed sections
+func diffSections(before, after []byte) []SectionDiff {
+	old := splitSections(before)
+	oldByPath := make(map[string]markdownSection)
+	for _, s := range old {
+		oldByPath[s.path] = s
+	}
+
+	var diffs []SectionDiff
+	matched := make(map[string]bool)
+	for _, s := range splitSections(after) {
+		previous, ok := oldByPath[s.path]
+		if !ok {
+			diffs = append(diffs, SectionDiff{Path: s.path, Change: SectionAdded, Lines: diffLines(nil, s.lines)})
+			continue
+		}
+		matched[s.path] = true
+		if strings.Join(previous.lines, "\n") != strings.Join(s.lines, "\n") {
+			diffs = append(diffs, SectionDiff{Path: s.path, Change: SectionModified, Lines: diffLines(previous.lines, s.lines)})
+		}
+	}
+	for _, s := range old {
+		if !matched[s.path] {
+			diffs = append(diffs, SectionDiff{Path: s.path, Change: SectionRemoved, Lines: diffLines(s.lines, nil)})
+		}
+	}
+	return diffs
+}
+
+// diffLines returns a line diff based on the longest common subsequence,
+// keeping diffContextLines unchanged lines around each change
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var all []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			all = append(all, DiffLine{Op: " ", Text: a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			all = append(all, DiffLine{Op: "-", Text: a[i]})
+			i++
+		default:
+			all = append(all, DiffLine{Op: "+", Text: b[j]})
+			j++
+		}
+	}
+
+	// Keep only the context around changes
+	keep := make([]bool, len(all))
+	for k, line := range all {
+		if line.Op == " " {
+			continue
+		}
+		for c := max(0, k-diffContextLines); c <= min(len(all)-1, k+diffContextLines); c++ {
+			keep[c] = true
+		}
+	}
+	var lines []DiffLine
+	for k, line := range all {
+		if keep[k] {
+			lines = append(lines, line)
+		} else if len(lines) == 0 || lines[len(lines)-1].Op != "..." {
+			lines = append(lines, DiffLine{Op: "...", Text: ""})
+		}
+	}
+	return lines
+}
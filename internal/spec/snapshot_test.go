@@ -0,0 +1,163 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Feature snapshots", func() {
+	var (
+		tempDir          string
+		featureDir       string
+		requirementsPath string
+	)
+
+	const requirements = `# Requirements Specification: Password Reset
+
+## Problem Statement
+Users forget their passwords.
+
+## Functional Requirements
+- Users can request a reset link
+- Links expire
+
+## Out of Scope
+- SMS resets
+`
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-snapshot-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+
+		featureDir = filepath.Join(tempDir, ".spec", "001-reset")
+		requirementsPath = filepath.Join(featureDir, "requirements.md")
+		Expect(os.WriteFile(requirementsPath, []byte(requirements), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("copies the artifacts into a timestamped directory", func() {
+		snapshot, err := spec.CreateSnapshot(tempDir, "reset", "before review")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.ID).To(MatchRegexp(`^\d{8}T\d{6}Z$`))
+		Expect(snapshot.Files).To(ConsistOf("context-requirements.md", "requirements.md"))
+		Expect(snapshot.Status).To(Equal("requirements-gathering"))
+
+		stored, err := os.ReadFile(filepath.Join(featureDir, ".snapshots", snapshot.ID, "requirements.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(stored)).To(Equal(requirements))
+
+		second, err := spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.ID).NotTo(Equal(snapshot.ID))
+
+		snapshots, err := spec.ListSnapshots(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(2))
+		Expect(snapshots[0].Reason).To(Equal("before review"))
+	})
+
+	It("does not list snapshots as artifacts", func() {
+		_, err := spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		artifacts, err := spec.ListArtifacts(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).To(HaveLen(2))
+	})
+
+	It("snapshots on status transitions when the artifacts changed", func() {
+		Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "reset", "Requirements Complete", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+		snapshots, err := spec.ListSnapshots(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(1))
+		Expect(snapshots[0].Status).To(Equal("requirements-gathering"))
+		Expect(snapshots[0].Reason).To(ContainSubstring("'Requirements Complete'"))
+
+		Expect(spec.UpdateFeatureStatus(tempDir, "reset", "Requirements Interactive Review")).To(Succeed())
+		snapshots, err = spec.ListSnapshots(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(1))
+	})
+
+	It("diffs requirements by section against the latest snapshot", func() {
+		_, err := spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := strings.Replace(requirements, "- Links expire", "- Links expire after 24 hours", 1)
+		updated = strings.Replace(updated, "## Out of Scope\n- SMS resets\n", "## Assumptions\n- Users have email\n", 1)
+		Expect(os.WriteFile(requirementsPath, []byte(updated), 0644)).To(Succeed())
+
+		diff, err := spec.DiffFeature(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Files).To(HaveLen(1))
+		Expect(diff.Files[0].Name).To(Equal("requirements.md"))
+
+		sections := diff.Files[0].Sections
+		Expect(sections).To(HaveLen(3))
+		Expect(sections[0].Path).To(Equal("Requirements Specification: Password Reset > Functional Requirements"))
+		Expect(sections[0].Change).To(Equal(spec.SectionModified))
+		Expect(sections[0].Lines).To(Equal([]spec.DiffLine{
+			{Op: " ", Text: "- Users can request a reset link"},
+			{Op: "-", Text: "- Links expire"},
+			{Op: "+", Text: "- Links expire after 24 hours"},
+		}))
+		Expect(sections[1].Path).To(HaveSuffix("Assumptions"))
+		Expect(sections[1].Change).To(Equal(spec.SectionAdded))
+		Expect(sections[2].Path).To(HaveSuffix("Out of Scope"))
+		Expect(sections[2].Change).To(Equal(spec.SectionRemoved))
+	})
+
+	It("diffs a newly created implementation plan", func() {
+		snapshot, err := spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+
+		diff, err := spec.DiffFeature(tempDir, "reset", snapshot.ID[:8])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(diff.Snapshot.ID).To(Equal(snapshot.ID))
+		Expect(diff.Files).To(HaveLen(1))
+		Expect(diff.Files[0].Name).To(Equal("implementation-plan.md"))
+		for _, section := range diff.Files[0].Sections {
+			Expect(section.Change).To(Equal(spec.SectionAdded))
+		}
+	})
+
+	It("elides unchanged lines far from changes", func() {
+		long := "## Problem Statement\n" + strings.Repeat("same\n", 10) + "old\n"
+		Expect(os.WriteFile(requirementsPath, []byte(long), 0644)).To(Succeed())
+		_, err := spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(requirementsPath, []byte(strings.Replace(long, "old", "new", 1)), 0644)).To(Succeed())
+
+		diff, err := spec.DiffFeature(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		lines := diff.Files[0].Sections[0].Lines
+		Expect(lines).To(HaveLen(5))
+		Expect(lines[0].Op).To(Equal("..."))
+	})
+
+	It("reports missing and unknown snapshots", func() {
+		_, err := spec.DiffFeature(tempDir, "reset", "")
+		Expect(err).To(MatchError(ContainSubstring("has no snapshots")))
+
+		_, err = spec.CreateSnapshot(tempDir, "reset", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.DiffFeature(tempDir, "reset", "1999")
+		Expect(err).To(MatchError(ContainSubstring("not found")))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -19,9 +20,44 @@ const (
 	SpecwareAllowlistEntry = "Bash(specware:*)"
 )
 
+// InitOptions controls the git tracking choice recorded by InitProjectWithOptions
+type InitOptions struct {
+	// TrackSpecs commits .spec/ and .claude/ when true and ignores them when
+	// false. If nil, the choice already recorded in config.json is kept, and
+	// specs are ignored for new projects.
+	TrackSpecs *bool
+	// IgnoreFile is .gitignore or .git/info/exclude. If empty, the recorded
+	// choice is kept, defaulting to .gitignore.
+	IgnoreFile string
+}
+
 // InitProject initializes a project with spec-driven workflow support
 func InitProject(targetDir string) ([]string, error) {
+	return InitProjectWithOptions(targetDir, InitOptions{})
+}
+
+// InitProjectWithOptions initializes a project with spec-driven workflow
+// support, recording the git tracking choice in config.json and writing the
+// matching ignore rules. An existing config.json is kept.
+func InitProjectWithOptions(targetDir string, opts InitOptions) ([]string, error) {
 	var createdFiles []string
+
+	// Keep the tracking choice of a previous init unless a new one is given
+	previous, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	gitConfig := previous.Git
+	if opts.TrackSpecs != nil {
+		gitConfig.TrackSpecs = *opts.TrackSpecs
+	}
+	if opts.IgnoreFile != "" {
+		gitConfig.IgnoreFile = opts.IgnoreFile
+	}
+	if gitConfig.IgnoreFile == IgnoreFileExclude && !IsGitRepository(targetDir) {
+		return nil, fmt.Errorf("%s requires %s to be a git repository", IgnoreFileExclude, targetDir)
+	}
+
 	// Create .claude/commands directory
 	claudeDir := filepath.Join(targetDir, ".claude", "commands")
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
@@ -41,7 +77,7 @@ func InitProject(targetDir string) ([]string, error) {
 	}
 
 	// Copy commands from embedded assets
-	err := fs.WalkDir(assets.CommandsFS, "commands", func(path string, d fs.DirEntry, err error) error {
+	err = fs.WalkDir(assets.CommandsFS, "commands", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -102,7 +138,8 @@ func InitProject(targetDir string) ([]string, error) {
 		return nil, fmt.Errorf("failed to create .spec/README.md: %w", err)
 	}
 
-	// Copy config from embedded assets
+	// Copy config from embedded assets, keeping the configuration of a
+	// previous init
 	err = fs.WalkDir(assets.ConfigFS, "config", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -111,6 +148,12 @@ func InitProject(targetDir string) ([]string, error) {
 			return nil
 		}
 
+		relPath := strings.TrimPrefix(path, "config/")
+		targetPath := filepath.Join(specDir, relPath)
+		if _, err := os.Stat(targetPath); err == nil {
+			return nil
+		}
+
 		// Read file from embedded FS
 		content, err := fs.ReadFile(assets.ConfigFS, path)
 		if err != nil {
@@ -118,8 +161,6 @@ func InitProject(targetDir string) ([]string, error) {
 		}
 
 		// Write to target directory
-		relPath := strings.TrimPrefix(path, "config/")
-		targetPath := filepath.Join(specDir, relPath)
 		createdFiles = append(createdFiles, filepath.Join(".spec", relPath))
 		return os.WriteFile(targetPath, content, 0644)
 	})
@@ -127,6 +168,22 @@ func InitProject(targetDir string) ([]string, error) {
 		return nil, fmt.Errorf("failed to copy config: %w", err)
 	}
 
+	// Record the git tracking choice and write the matching ignore rules
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	config.Git.TrackSpecs = gitConfig.TrackSpecs
+	config.Git.IgnoreFile = gitConfig.IgnoreFile
+	if err := SaveConfig(targetDir, config); err != nil {
+		return nil, err
+	}
+	ignoreFile, err := ApplyGitIgnore(targetDir, config.Git)
+	if err != nil {
+		return nil, err
+	}
+	createdFiles = append(createdFiles, ignoreFile)
+
 	// Create example spec directory
 	exampleDir := filepath.Join(specDir, "000-example-spec")
 	if err := os.MkdirAll(exampleDir, 0755); err != nil {
@@ -182,6 +239,9 @@ func LocalizeTemplates(targetDir string) ([]string, error) {
 			fmt.Printf("Warning: Template file %s already exists, overwriting\n", relPath)
 		}
 
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return err
+		}
 		createdFiles = append(createdFiles, filepath.Join(".spec", "templates", relPath))
 		return os.WriteFile(targetPath, content, 0644)
 	})
@@ -238,28 +298,41 @@ func ValidateFeatureName(shortName string) error {
 	return nil
 }
 
-// getTemplate returns template content, preferring localized over embedded
+// getTemplate returns template content, preferring localized over embedded.
+// Localized templates that extend another template are rendered.
 func getTemplate(targetDir, templateName string) ([]byte, error) {
-	// Try localized template first
-	localPath := filepath.Join(targetDir, ".spec", "templates", templateName)
-	if content, err := os.ReadFile(localPath); err == nil {
-		return content, nil
-	}
+	return renderTemplate(targetDir, filepath.ToSlash(templateName), map[string]bool{})
+}
 
-	// Fall back to embedded template
-	return fs.ReadFile(assets.TemplatesFS, filepath.Join("templates", templateName))
+// RequirementsOptions controls how a new feature specification is created
+type RequirementsOptions struct {
+	// Type selects the template set, defaulting to DefaultTemplateSet
+	Type string
+	// Output receives warnings and progress messages; defaults to os.Stdout
+	Output io.Writer
 }
 
 // CreateNewRequirements creates a new feature requirements specification
 func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
-	var createdFiles []string
-	if err := ValidateFeatureName(shortName); err != nil {
-		return nil, err
-	}
+	return CreateNewRequirementsWithOptions(targetDir, shortName, RequirementsOptions{})
+}
+
+// CreateNewRequirementsWithOptions creates a new feature requirements
+// specification from the template set of the given type, and records the type
+// in .spec-status.json
+func CreateNewRequirementsWithOptions(targetDir, shortName string, opts RequirementsOptions) ([]string, error) {
+	return CreateArtifact(targetDir, ArtifactTypeRequirements, shortName, ArtifactOptions{Type: opts.Type, Output: opts.Output})
+}
 
+// createRequirements creates a new feature directory with the document of the
+// requirements artifact type
+func createRequirements(targetDir, shortName string, t ArtifactType, opts ArtifactOptions) ([]string, error) {
+	var createdFiles []string
 	specDir := filepath.Join(targetDir, ".spec")
-	if _, err := os.Stat(specDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf(".spec directory not found. Run 'specware init' first")
+
+	featureType, err := resolveTemplateSet(targetDir, opts.Type)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get next feature number
@@ -268,6 +341,13 @@ func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
 		return nil, fmt.Errorf("failed to get next feature number: %w", err)
 	}
 
+	// Render the requirements template before creating anything, so a broken
+	// template override does not leave an empty feature behind
+	requirementsContent, err := getSetTemplate(targetDir, featureType, t.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirements template: %w", err)
+	}
+
 	// Create feature directory
 	featureName := fmt.Sprintf("%03d-%s", featureNum, shortName)
 	featureDir := filepath.Join(specDir, featureName)
@@ -275,12 +355,6 @@ func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
 		return nil, fmt.Errorf("failed to create feature directory: %w", err)
 	}
 
-	// Copy requirements template
-	requirementsContent, err := getTemplate(targetDir, "requirements.md")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get requirements template: %w", err)
-	}
-
 	requirementsPath := filepath.Join(featureDir, "requirements.md")
 	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, "requirements.md"))
 	if err := os.WriteFile(requirementsPath, requirementsContent, 0644); err != nil {
@@ -288,13 +362,13 @@ func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
 	}
 
 	// Create context file from template
-	contextTemplate, err := getTemplate(targetDir, "context.md")
+	contextTemplate, err := getSetTemplate(targetDir, featureType, "context.md")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get context template: %w", err)
 	}
 
 	// Replace placeholder with appropriate title
-	contextContent := strings.Replace(string(contextTemplate), "[Feature Name]", "Requirements", 1)
+	contextContent := strings.Replace(string(contextTemplate), "[Feature Name]", t.Title, 1)
 	contextPath := filepath.Join(featureDir, "context-requirements.md")
 	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, "context-requirements.md"))
 	if err := os.WriteFile(contextPath, []byte(contextContent), 0644); err != nil {
@@ -306,6 +380,10 @@ func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
 	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, ".spec-status.json"))
 	statusData := FeatureStatus{
 		CurrentStep: "requirements-gathering",
+		Type:        featureType,
+	}
+	if t.Status != "" {
+		statusData.CurrentStep = t.Status
 	}
 	jsonData, err := json.MarshalIndent(statusData, "", "  ")
 	if err != nil {
@@ -315,61 +393,14 @@ func CreateNewRequirements(targetDir, shortName string) ([]string, error) {
 		return nil, fmt.Errorf("failed to create .spec-status.json: %w", err)
 	}
 
+	createConfiguredBranch(targetDir, featureDir, BranchOnNewRequirements, opts.output())
+
 	return createdFiles, nil
 }
 
 // CreateNewImplementationPlan creates an implementation plan for an existing feature
 func CreateNewImplementationPlan(targetDir, shortName string) ([]string, error) {
-	var createdFiles []string
-	if err := ValidateFeatureName(shortName); err != nil {
-		return nil, err
-	}
-
-	specDir := filepath.Join(targetDir, ".spec")
-	if _, err := os.Stat(specDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf(".spec directory not found. Run 'specware init' first")
-	}
-
-	// Find the feature directory
-	featureDir, err := findFeatureDirectory(specDir, shortName)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if implementation plan already exists
-	planPath := filepath.Join(featureDir, "implementation-plan.md")
-	if _, err := os.Stat(planPath); err == nil {
-		return nil, fmt.Errorf("implementation plan already exists for feature %s", shortName)
-	}
-
-	// Copy implementation plan template
-	planContent, err := getTemplate(targetDir, "implementation-plan.md")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get implementation plan template: %w", err)
-	}
-
-	// Extract feature name from directory path for relative path
-	featureName := filepath.Base(featureDir)
-	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, "implementation-plan.md"))
-	if err := os.WriteFile(planPath, planContent, 0644); err != nil {
-		return nil, fmt.Errorf("failed to create implementation-plan.md: %w", err)
-	}
-
-	// Create context file from template
-	contextTemplate, err := getTemplate(targetDir, "context.md")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get context template: %w", err)
-	}
-
-	// Replace placeholder with appropriate title
-	contextContent := strings.Replace(string(contextTemplate), "[Feature Name]", "Implementation Plan", 1)
-	contextPath := filepath.Join(featureDir, "context-implementation-plan.md")
-	createdFiles = append(createdFiles, filepath.Join(".spec", featureName, "context-implementation-plan.md"))
-	if err := os.WriteFile(contextPath, []byte(contextContent), 0644); err != nil {
-		return nil, fmt.Errorf("failed to create context-implementation-plan.md: %w", err)
-	}
-
-	return createdFiles, nil
+	return CreateArtifact(targetDir, ArtifactTypeImplementationPlan, shortName, ArtifactOptions{})
 }
 
 // findFeatureDirectory finds a feature directory by short name
@@ -397,6 +428,12 @@ func findFeatureDirectory(specDir, shortName string) (string, error) {
 // FeatureStatus represents the status information stored in .spec-status.json
 type FeatureStatus struct {
 	CurrentStep string `json:"current-step"`
+	Branch      string `json:"branch,omitempty"`
+	BaseCommit  string `json:"base-commit,omitempty"`
+	// Type is the template set the feature was created from
+	Type string `json:"type,omitempty"`
+	// Source records the document an imported feature was created from
+	Source *FeatureSource `json:"source,omitempty"`
 }
 
 // ClaudeSettings represents the structure of .claude/settings.local.json
@@ -523,37 +560,62 @@ func UpdateClaudeSettings(targetDir string, autoYes bool) error {
 	return nil
 }
 
+// StatusUpdateOptions controls the checks performed by UpdateFeatureStatusWithOptions
+type StatusUpdateOptions struct {
+	// Force skips checks that would otherwise block the transition
+	Force bool
+	// Output receives warnings and progress messages; defaults to os.Stdout
+	Output io.Writer
+}
+
 // UpdateFeatureStatus updates the status of a feature specification
 func UpdateFeatureStatus(targetDir, shortName, status string) error {
-	if err := ValidateFeatureName(shortName); err != nil {
+	return UpdateFeatureStatusWithOptions(targetDir, shortName, status, StatusUpdateOptions{})
+}
+
+// UpdateFeatureStatusWithOptions updates the status of a feature specification,
+// running the configured transition checks
+func UpdateFeatureStatusWithOptions(targetDir, shortName, status string, opts StatusUpdateOptions) error {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
 		return err
 	}
 
-	specDir := filepath.Join(targetDir, ".spec")
-	if _, err := os.Stat(specDir); os.IsNotExist(err) {
-		return fmt.Errorf(".spec directory not found. Run 'specware init' first")
+	// Preserve any other fields already recorded in the status file
+	statusData, err := readFeatureStatus(featureDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
-	// Find the feature directory
-	featureDir, err := findFeatureDirectory(specDir, shortName)
-	if err != nil {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	if err := checkQAPhaseExit(targetDir, featureDir, shortName, statusData.CurrentStep, status, opts.Force, out); err != nil {
 		return err
 	}
 
-	// Update status file
-	statusPath := filepath.Join(featureDir, ".spec-status.json")
-	statusData := FeatureStatus{
-		CurrentStep: status,
+	if err := checkSpecsOnPlanning(targetDir, featureDir, shortName, statusData.CurrentStep, status, opts.Force, out); err != nil {
+		return err
 	}
 
-	jsonData, err := json.MarshalIndent(statusData, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal status data: %w", err)
+	if err := checkApprovals(targetDir, featureDir, shortName, statusData.CurrentStep, status, opts.Force, out); err != nil {
+		return err
 	}
 
-	if err := os.WriteFile(statusPath, jsonData, 0644); err != nil {
-		return fmt.Errorf("failed to write status file: %w", err)
+	warnIncompleteDependencies(targetDir, shortName, status, out)
+
+	previous := statusData.CurrentStep
+	snapshotOnTransition(featureDir, shortName, previous, status, out)
+
+	statusData.CurrentStep = status
+	if err := writeFeatureStatus(featureDir, statusData); err != nil {
+		return err
 	}
 
+	if enteringImplementation(previous, status) {
+		createConfiguredBranch(targetDir, featureDir, BranchOnImplementation, out)
+	}
 	return nil
 }
@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// planTaskPattern matches checkbox list items such as "- [ ] Step 1: ..."
+var planTaskPattern = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// PlanTask is a checkbox item from implementation-plan.md
+type PlanTask struct {
+	Index   int    `json:"index"`
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Done    bool   `json:"done"`
+	Section string `json:"section,omitempty"`
+}
+
+// TaskProgress summarizes the checkbox items of an implementation plan
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// ListPlanTasks returns the checkbox items of a feature's implementation plan
+func ListPlanTasks(targetDir, shortName string) ([]PlanTask, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	return readPlanTasks(featureDir)
+}
+
+// readPlanTasks reads the checkbox items from implementation-plan.md. A
+// missing plan has no tasks.
+func readPlanTasks(featureDir string) ([]PlanTask, error) {
+	content, err := os.ReadFile(filepath.Join(featureDir, "implementation-plan.md"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read implementation-plan.md: %w", err)
+	}
+	return parsePlanTasks(content), nil
+}
+
+// parsePlanTasks extracts checkbox items outside of code blocks, noting the
+// heading each one appears under
+func parsePlanTasks(content []byte) []PlanTask {
+	headings := ParseHeadings(content)
+	var tasks []PlanTask
+	inFence := false
+
+	offset := 0
+	for i, line := range strings.Split(string(content), "\n") {
+		lineOffset := offset
+		offset += len(line) + 1
+
+		if fenceOf(strings.TrimSpace(line)) != "" {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		m := planTaskPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		task := PlanTask{
+			Index: len(tasks) + 1,
+			Line:  i + 1,
+			Text:  strings.TrimSpace(m[4]),
+			Done:  m[2] != " ",
+		}
+		if h, ok := headingAt(headings, lineOffset); ok {
+			task.Section = h.Title
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// PlanProgress returns how many implementation plan tasks are checked off
+func PlanProgress(tasks []PlanTask) TaskProgress {
+	progress := TaskProgress{Total: len(tasks)}
+	for _, task := range tasks {
+		if task.Done {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// SetPlanTaskDone checks or unchecks the implementation plan task with the
+// given 1-based index, leaving the rest of the file untouched
+func SetPlanTaskDone(targetDir, shortName string, index int, done bool) (PlanTask, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return PlanTask{}, err
+	}
+
+	planPath := filepath.Join(featureDir, "implementation-plan.md")
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return PlanTask{}, fmt.Errorf("implementation-plan.md not found for feature %s", shortName)
+		}
+		return PlanTask{}, fmt.Errorf("failed to read implementation-plan.md: %w", err)
+	}
+
+	tasks := parsePlanTasks(content)
+	if index < 1 || index > len(tasks) {
+		return PlanTask{}, fmt.Errorf("task %d not found for feature %s (plan has %d tasks)", index, shortName, len(tasks))
+	}
+	task := tasks[index-1]
+
+	mark := " "
+	if done {
+		mark = "x"
+	}
+	lines := strings.Split(string(content), "\n")
+	lines[task.Line-1] = planTaskPattern.ReplaceAllString(lines[task.Line-1], "${1}"+mark+"${3}${4}")
+	if err := os.WriteFile(planPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return PlanTask{}, fmt.Errorf("failed to write implementation-plan.md: %w", err)
+	}
+
+	task.Done = done
+	return task, nil
+}
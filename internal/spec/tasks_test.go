@@ -0,0 +1,69 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Plan tasks", func() {
+	var (
+		tempDir  string
+		planPath string
+	)
+
+	const plan = "# Implementation Plan\n\n## Phase 1\n\n- [x] Step 1: Schema\n- [ ] Step 2: Handlers\n\n```markdown\n- [ ] not a task\n```\n\n## Phase 2\n\n* [ ] Step 3: Docs\r\n"
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-tasks-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		planPath = filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md")
+		Expect(os.WriteFile(planPath, []byte(plan), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("lists checkbox items outside code blocks with their section", func() {
+		tasks, err := spec.ListPlanTasks(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(3))
+		Expect(tasks[0]).To(Equal(spec.PlanTask{Index: 1, Line: 5, Text: "Step 1: Schema", Done: true, Section: "Phase 1"}))
+		Expect(tasks[2].Section).To(Equal("Phase 2"))
+		Expect(spec.PlanProgress(tasks)).To(Equal(spec.TaskProgress{Done: 1, Total: 3}))
+	})
+
+	It("toggles a task in place", func() {
+		task, err := spec.SetPlanTaskDone(tempDir, "user-auth", 2, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(task.Text).To(Equal("Step 2: Handlers"))
+
+		_, err = spec.SetPlanTaskDone(tempDir, "user-auth", 3, true)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.SetPlanTaskDone(tempDir, "user-auth", 1, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := os.ReadFile(planPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Implementation Plan\n\n## Phase 1\n\n- [ ] Step 1: Schema\n- [x] Step 2: Handlers\n\n```markdown\n- [ ] not a task\n```\n\n## Phase 2\n\n* [x] Step 3: Docs\r\n"))
+	})
+
+	It("rejects unknown task numbers", func() {
+		_, err := spec.SetPlanTaskDone(tempDir, "user-auth", 4, true)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("task 4 not found"))
+	})
+})
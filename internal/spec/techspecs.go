@@ -0,0 +1,201 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SpecManifestFileName is the per-feature file registering technical specs
+const SpecManifestFileName = "specs.json"
+
+// TechSpecType describes a kind of technical specification that add-spec can
+// create. Templates live under templates/specs/ and can be localized.
+type TechSpecType struct {
+	Name        string
+	Template    string
+	DefaultName string
+	Description string
+}
+
+// TechSpecTypes lists the technical specification types in display order
+var TechSpecTypes = []TechSpecType{
+	{Name: "openapi", Template: "specs/openapi.yaml", DefaultName: "openapi.yaml", Description: "OpenAPI 3 description of HTTP endpoints"},
+	{Name: "json-schema", Template: "specs/json-schema.json", DefaultName: "schema.json", Description: "JSON Schema for a data structure"},
+	{Name: "mermaid", Template: "specs/mermaid.md", DefaultName: "diagram.md", Description: "Mermaid diagram, e.g. a sequence or flow chart"},
+	{Name: "erd", Template: "specs/erd.md", DefaultName: "erd.md", Description: "Entity relationship diagram of the data model"},
+	{Name: "cli-reference", Template: "specs/cli-reference.md", DefaultName: "cli-reference.md", Description: "Reference for commands, flags and output"},
+}
+
+// TechSpec is a technical specification registered in specs.json
+type TechSpec struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Created string `json:"created"`
+}
+
+// SpecManifest represents the contents of specs.json
+type SpecManifest struct {
+	Specs []TechSpec `json:"specs"`
+}
+
+// TechSpecEntry is a technical specification of a feature as listed by
+// ListTechSpecs. Unregistered entries are spec files that were written
+// without add-spec; registered entries may refer to files that were removed.
+type TechSpecEntry struct {
+	TechSpec
+	Registered bool `json:"registered"`
+	Exists     bool `json:"exists"`
+}
+
+// FindTechSpecType returns the technical specification type with the given name
+func FindTechSpecType(name string) (TechSpecType, error) {
+	var names []string
+	for _, t := range TechSpecTypes {
+		if t.Name == name {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return TechSpecType{}, fmt.Errorf("unknown spec type %q (expected one of: %s)", name, strings.Join(names, ", "))
+}
+
+// readSpecManifest reads specs.json from a feature directory, returning an
+// empty manifest if the file does not exist
+func readSpecManifest(featureDir string) (SpecManifest, error) {
+	var manifest SpecManifest
+	data, err := os.ReadFile(filepath.Join(featureDir, SpecManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return manifest, fmt.Errorf("failed to read %s: %w", SpecManifestFileName, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse %s in %s: %w", SpecManifestFileName, filepath.Base(featureDir), err)
+	}
+	return manifest, nil
+}
+
+// writeSpecManifest writes specs.json to a feature directory
+func writeSpecManifest(featureDir string, manifest SpecManifest) error {
+	jsonData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal spec manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, SpecManifestFileName), jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SpecManifestFileName, err)
+	}
+	return nil
+}
+
+// AddTechSpec creates a technical specification of the given type in a
+// feature directory from its template and registers it in specs.json. The
+// name defaults to the type's default file name, and the template's extension
+// is added when the name has none. It returns the created file relative to
+// targetDir.
+func AddTechSpec(targetDir, shortName, specType, name string) (string, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return "", err
+	}
+	t, err := FindTechSpecType(specType)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		name = t.DefaultName
+	} else if filepath.Ext(name) == "" {
+		name += filepath.Ext(t.Template)
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return "", err
+	}
+	if name != filepath.Base(name) || artifactKind(name, artifactDocuments(config)) != ArtifactSpec {
+		return "", fmt.Errorf("%s cannot be used as a technical spec name", name)
+	}
+
+	specPath := filepath.Join(featureDir, name)
+	if _, err := os.Stat(specPath); err == nil {
+		return "", fmt.Errorf("%s already exists for feature %s", name, shortName)
+	}
+
+	manifest, err := readSpecManifest(featureDir)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := getTemplate(targetDir, t.Template)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s template: %w", t.Name, err)
+	}
+	if err := os.WriteFile(specPath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	registered := TechSpec{Name: name, Type: t.Name, Created: time.Now().UTC().Format(time.RFC3339)}
+	replaced := false
+	for i, existing := range manifest.Specs {
+		// A registration left behind by a deleted file is reused
+		if existing.Name == name {
+			manifest.Specs[i] = registered
+			replaced = true
+		}
+	}
+	if !replaced {
+		manifest.Specs = append(manifest.Specs, registered)
+	}
+	if err := writeSpecManifest(featureDir, manifest); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(".spec", filepath.Base(featureDir), name), nil
+}
+
+// ListTechSpecs returns the registered technical specifications of a feature
+// in registration order, followed by any unregistered spec files by name
+func ListTechSpecs(targetDir, shortName string) ([]TechSpecEntry, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := readSpecManifest(featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TechSpecEntry
+	registered := make(map[string]bool)
+	for _, s := range manifest.Specs {
+		_, statErr := os.Stat(filepath.Join(featureDir, s.Name))
+		entries = append(entries, TechSpecEntry{TechSpec: s, Registered: true, Exists: statErr == nil})
+		registered[s.Name] = true
+	}
+
+	files, err := os.ReadDir(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature directory: %w", err)
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	documents := artifactDocuments(config)
+	var unregistered []TechSpecEntry
+	for _, file := range files {
+		if file.IsDir() || registered[file.Name()] || artifactKind(file.Name(), documents) != ArtifactSpec {
+			continue
+		}
+		unregistered = append(unregistered, TechSpecEntry{TechSpec: TechSpec{Name: file.Name()}, Exists: true})
+	}
+	sort.Slice(unregistered, func(i, j int) bool {
+		return unregistered[i].Name < unregistered[j].Name
+	})
+	return append(entries, unregistered...), nil
+}
@@ -0,0 +1,108 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Technical specs", func() {
+	var (
+		tempDir    string
+		featureDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-techspecs-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		featureDir = filepath.Join(tempDir, ".spec", "001-user-auth")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("creates a spec from its template and registers it", func() {
+		created, err := spec.AddTechSpec(tempDir, "user-auth", "openapi", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(Equal(filepath.Join(".spec", "001-user-auth", "openapi.yaml")))
+
+		content, err := os.ReadFile(filepath.Join(featureDir, "openapi.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("openapi: 3.0.3\n"))
+
+		data, err := os.ReadFile(filepath.Join(featureDir, spec.SpecManifestFileName))
+		Expect(err).NotTo(HaveOccurred())
+		var manifest spec.SpecManifest
+		Expect(json.Unmarshal(data, &manifest)).To(Succeed())
+		Expect(manifest.Specs).To(HaveLen(1))
+		Expect(manifest.Specs[0].Name).To(Equal("openapi.yaml"))
+		Expect(manifest.Specs[0].Type).To(Equal("openapi"))
+	})
+
+	It("adds the template extension to names without one", func() {
+		created, err := spec.AddTechSpec(tempDir, "user-auth", "json-schema", "session")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Base(created)).To(Equal("session.json"))
+	})
+
+	It("prefers localized templates", func() {
+		_, err := spec.LocalizeTemplates(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		localized := filepath.Join(tempDir, ".spec", "templates", "specs", "erd.md")
+		Expect(os.WriteFile(localized, []byte("# Our ERD\n"), 0644)).To(Succeed())
+
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "erd", "")
+		Expect(err).NotTo(HaveOccurred())
+		content, err := os.ReadFile(filepath.Join(featureDir, "erd.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("# Our ERD\n"))
+	})
+
+	It("rejects unknown types, reserved names and existing files", func() {
+		_, err := spec.AddTechSpec(tempDir, "user-auth", "uml", "")
+		Expect(err).To(MatchError(ContainSubstring("unknown spec type")))
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "mermaid", "requirements.md")
+		Expect(err).To(MatchError(ContainSubstring("cannot be used")))
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "mermaid", "../flows")
+		Expect(err).To(HaveOccurred())
+
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "mermaid", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "mermaid", "diagram")
+		Expect(err).To(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("lists registered, missing and unregistered specs", func() {
+		_, err := spec.AddTechSpec(tempDir, "user-auth", "cli-reference", "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.AddTechSpec(tempDir, "user-auth", "mermaid", "login-flow")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Remove(filepath.Join(featureDir, "login-flow.md"))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(featureDir, "notes.yaml"), []byte("a: b\n"), 0644)).To(Succeed())
+
+		entries, err := spec.ListTechSpecs(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(3))
+		Expect(entries[0].Name).To(Equal("cli-reference.md"))
+		Expect(entries[0].Exists).To(BeTrue())
+		Expect(entries[1].Name).To(Equal("login-flow.md"))
+		Expect(entries[1].Exists).To(BeFalse())
+		Expect(entries[2].Name).To(Equal("notes.yaml"))
+		Expect(entries[2].Registered).To(BeFalse())
+
+		artifacts, err := spec.ListArtifacts(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(artifacts).NotTo(ContainElement(HaveField("Name", spec.SpecManifestFileName)))
+	})
+})
@@ -0,0 +1,598 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tiwillia/specware/assets"
+)
+
+// DefaultTemplateSet is the template set used when no type is given. Its
+// templates are the top-level ones in .spec/templates and the embedded assets.
+const DefaultTemplateSet = "feature"
+
+// reservedTemplateDirs are directories below templates that are not sets
+var reservedTemplateDirs = map[string]bool{
+	"specs": true,
+}
+
+// TemplateSets returns the names of the available template sets: the default
+// set, the embedded sets, and any directories in .spec/templates or the
+// templates of active packs
+func TemplateSets(targetDir string) ([]string, error) {
+	names := map[string]bool{DefaultTemplateSet: true}
+
+	entries, err := fs.ReadDir(assets.TemplatesFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded templates: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !reservedTemplateDirs[entry.Name()] {
+			names[entry.Name()] = true
+		}
+	}
+
+	for _, source := range templateSources(targetDir) {
+		entries, err = os.ReadDir(source)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !reservedTemplateDirs[entry.Name()] {
+				names[entry.Name()] = true
+			}
+		}
+	}
+
+	var sets []string
+	for name := range names {
+		sets = append(sets, name)
+	}
+	sort.Strings(sets)
+	return sets, nil
+}
+
+// resolveTemplateSet returns the template set for a feature type, defaulting
+// to DefaultTemplateSet, and fails if no such set exists
+func resolveTemplateSet(targetDir, set string) (string, error) {
+	if set == "" {
+		return DefaultTemplateSet, nil
+	}
+	sets, err := TemplateSets(targetDir)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range sets {
+		if s == set {
+			return set, nil
+		}
+	}
+	return "", fmt.Errorf("unknown template set %q (available: %s)", set, strings.Join(sets, ", "))
+}
+
+// getSetTemplate returns a template from a template set. The set's localized
+// and embedded templates are tried first; templates the set does not define
+// fall back to the top-level ones, so sets only contain what differs.
+func getSetTemplate(targetDir, set, templateName string) ([]byte, error) {
+	if set == "" {
+		set = DefaultTemplateSet
+	}
+	return renderTemplate(targetDir, path.Join(set, templateName), map[string]bool{})
+}
+
+// RenderTemplate returns the effective content of a template in a template
+// set, with any localized templates that extend others applied. Names without
+// an extension are taken to be markdown templates.
+func RenderTemplate(targetDir, set, templateName string) ([]byte, error) {
+	set, err := resolveTemplateSet(targetDir, set)
+	if err != nil {
+		return nil, err
+	}
+	templateName, err = templateFileName(templateName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := getSetTemplate(targetDir, set, templateName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template %s not found", templateName)
+	}
+	return content, err
+}
+
+// Template inheritance directives are HTML comments on their own line. A
+// localized template starting with "<!-- specware:extends [base] -->" is
+// applied on top of its base template, which defaults to the template it
+// overrides. Each following directive applies to the content up to the next:
+//
+//	<!-- specware:add [after|before <section>] -->  insert the content
+//	<!-- specware:replace <section> -->               replace a section
+//	<!-- specware:remove <section> -->                remove a section
+var templateDirectivePattern = regexp.MustCompile(`^<!--\s*specware:(\S+)\s*(.*?)\s*-->\s*$`)
+
+// templateDirective is one change a template makes to the template it extends
+type templateDirective struct {
+	Op       string
+	Position string
+	Section  string
+	Line     int
+	Content  string
+}
+
+// templateSources returns the directories templates are looked up in before
+// the embedded ones: .spec/templates, then the templates of active packs
+func templateSources(targetDir string) []string {
+	sources := []string{filepath.Join(targetDir, ".spec", "templates")}
+	for _, pack := range activePacks(targetDir) {
+		sources = append(sources, filepath.Join(pack.Dir, "templates"))
+	}
+	return sources
+}
+
+// renderTemplate returns a template by its path below the templates
+// directory, preferring the localized copy, then active packs. Templates
+// already being rendered are skipped, so a template extending its own name
+// extends the next source and chains of extends cannot loop.
+func renderTemplate(targetDir, templatePath string, rendering map[string]bool) ([]byte, error) {
+	for _, source := range templateSources(targetDir) {
+		key := filepath.Join(source, filepath.FromSlash(templatePath))
+		content, err := os.ReadFile(key)
+		if err != nil || rendering[key] {
+			continue
+		}
+		base, extends, directives, err := parseTemplateOverride(templatePath, content)
+		if err != nil || !extends {
+			return content, err
+		}
+		if base == "" {
+			base = templatePath
+		}
+		rendering[key] = true
+		baseContent, err := renderTemplate(targetDir, base, rendering)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s extends %s, which was not found", templatePath, base)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return applyTemplateDirectives(templatePath, base, baseContent, directives)
+	}
+	content, err := fs.ReadFile(assets.TemplatesFS, path.Join("templates", templatePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Templates a set does not define come from the top-level templates
+		if set, name, ok := strings.Cut(templatePath, "/"); ok && !reservedTemplateDirs[set] {
+			return renderTemplate(targetDir, name, rendering)
+		}
+	}
+	return content, err
+}
+
+// parseTemplateOverride reads the directives of a template extending another.
+// extends is false for ordinary templates, which are used as they are.
+func parseTemplateOverride(name string, content []byte) (string, bool, []templateDirective, error) {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first == len(lines) {
+		return "", false, nil, nil
+	}
+	match := templateDirectivePattern.FindStringSubmatch(strings.TrimSpace(lines[first]))
+	if match == nil || match[1] != "extends" {
+		return "", false, nil, nil
+	}
+	base := unquoteSection(match[2])
+
+	var directives []templateDirective
+	var body []string
+	fence := ""
+	flush := func() {
+		if len(directives) > 0 {
+			directives[len(directives)-1].Content = strings.Trim(strings.Join(body, "\n"), "\n")
+		}
+		body = nil
+	}
+	for i := first + 1; i < len(lines); i++ {
+		line := lines[i]
+		if marker := fenceOf(strings.TrimSpace(line)); marker != "" {
+			if fence == "" {
+				fence = marker
+			} else if marker == fence {
+				fence = ""
+			}
+		}
+		if fence == "" {
+			if match := templateDirectivePattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				flush()
+				directive, err := parseTemplateDirective(match[1], match[2])
+				if err != nil {
+					return "", false, nil, fmt.Errorf("%s:%d: %w", name, i+1, err)
+				}
+				directive.Line = i + 1
+				directives = append(directives, directive)
+				continue
+			}
+		}
+		if len(directives) == 0 && strings.TrimSpace(line) != "" {
+			return "", false, nil, fmt.Errorf("%s:%d: content must follow an add or replace directive", name, i+1)
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	for _, d := range directives {
+		if d.Op == "remove" && d.Content != "" {
+			return "", false, nil, fmt.Errorf("%s:%d: remove does not take content", name, d.Line)
+		}
+		if d.Op != "remove" && d.Content == "" {
+			return "", false, nil, fmt.Errorf("%s:%d: %s has no content", name, d.Line, d.Op)
+		}
+	}
+	return base, true, directives, nil
+}
+
+// parseTemplateDirective parses the operation and arguments of a directive
+func parseTemplateDirective(op, args string) (templateDirective, error) {
+	directive := templateDirective{Op: op}
+	switch op {
+	case "add":
+		if args == "" {
+			return directive, nil
+		}
+		position, section, _ := strings.Cut(args, " ")
+		if position != "after" && position != "before" {
+			return directive, fmt.Errorf("add expects \"after <section>\" or \"before <section>\", got %q", args)
+		}
+		directive.Position = position
+		directive.Section = unquoteSection(section)
+	case "replace", "remove":
+		directive.Section = unquoteSection(args)
+	case "extends":
+		return directive, fmt.Errorf("extends must be the first line of the template")
+	default:
+		return directive, fmt.Errorf("unknown directive %q (expected add, replace or remove)", op)
+	}
+	if directive.Section == "" {
+		return directive, fmt.Errorf("%s needs a section", op)
+	}
+	return directive, nil
+}
+
+// unquoteSection strips optional quotes around a section name
+func unquoteSection(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return strings.TrimSpace(s)
+}
+
+// applyTemplateDirectives applies the directives of a template in order to
+// the content of the template it extends
+func applyTemplateDirectives(name, baseName string, base []byte, directives []templateDirective) ([]byte, error) {
+	content := string(base)
+	for _, d := range directives {
+		if d.Op == "add" && d.Position == "" {
+			content = insertTemplateBlock(content, len(content), d.Content)
+			continue
+		}
+		section, err := findDocumentSection([]byte(content), baseName, d.Section)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, d.Line, err)
+		}
+		switch {
+		case d.Op == "add" && d.Position == "before":
+			content = insertTemplateBlock(content, section.Start, d.Content)
+		case d.Op == "add":
+			content = insertTemplateBlock(content, section.End, d.Content)
+		case d.Op == "remove":
+			content = content[:section.Start] + content[section.End:]
+			if strings.TrimSpace(content[section.Start:]) == "" {
+				content = strings.TrimRight(content, "\n") + "\n"
+			}
+		default:
+			// Content without a heading replaces the section body only
+			start := section.Start
+			if _, _, ok := parseHeadingLine(strings.SplitN(d.Content, "\n", 2)[0]); !ok {
+				start = section.BodyStart
+			}
+			region := content[start:section.End]
+			trailing := region[len(strings.TrimRight(region, "\n")):]
+			if trailing == "" && section.End < len(content) {
+				trailing = "\n"
+			}
+			content = content[:start] + d.Content + trailing + content[section.End:]
+		}
+	}
+	return []byte(content), nil
+}
+
+// insertTemplateBlock inserts a block at an offset, keeping a blank line
+// between it and the surrounding sections
+func insertTemplateBlock(content string, pos int, block string) string {
+	before, after := content[:pos], content[pos:]
+	if strings.TrimSpace(after) == "" {
+		before = strings.TrimRight(before, "\n")
+		if before != "" {
+			before += "\n\n"
+		}
+		return before + block + "\n"
+	}
+	if before != "" && !strings.HasSuffix(before, "\n\n") {
+		before = strings.TrimRight(before, "\n") + "\n\n"
+	}
+	return before + block + "\n\n" + after
+}
+
+// Template states reported by ListTemplates
+const (
+	// TemplateEmbedded is a built-in template without a localized copy
+	TemplateEmbedded = "embedded"
+	// TemplateLocalized is a localized copy identical to the built-in template
+	TemplateLocalized = "localized"
+	// TemplateOverridden is a localized template that differs from, or
+	// extends, the built-in template
+	TemplateOverridden = "overridden"
+	// TemplateCustom is a localized template with no built-in counterpart
+	TemplateCustom = "custom"
+	// TemplatePack is provided by an active pack and not localized
+	TemplatePack = "pack"
+)
+
+// TemplateInfo describes a template and where its content comes from
+type TemplateInfo struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Extends bool   `json:"extends,omitempty"`
+	// Pack names the pack providing a TemplatePack template
+	Pack string `json:"pack,omitempty"`
+}
+
+// TemplateDiff is the difference between the effective and the built-in
+// content of a template
+type TemplateDiff struct {
+	Name  string     `json:"name"`
+	State string     `json:"state"`
+	Lines []DiffLine `json:"lines"`
+}
+
+// templateFileName normalizes a template name to a path below the templates
+// directory, adding .md when there is no extension
+func templateFileName(name string) (string, error) {
+	name = path.Clean(filepath.ToSlash(strings.TrimSpace(name)))
+	if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+	if path.Ext(name) == "" {
+		name += ".md"
+	}
+	return name, nil
+}
+
+// embeddedTemplate returns the built-in content of a template. Set templates
+// the embedded set does not define resolve to the top-level template.
+func embeddedTemplate(templatePath string) ([]byte, error) {
+	content, err := fs.ReadFile(assets.TemplatesFS, path.Join("templates", templatePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		if set, name, ok := strings.Cut(templatePath, "/"); ok && !reservedTemplateDirs[set] {
+			return embeddedTemplate(name)
+		}
+	}
+	return content, err
+}
+
+// templateInfo returns the state of a template
+func templateInfo(targetDir, templatePath string) (TemplateInfo, error) {
+	info := TemplateInfo{Name: templatePath, State: TemplateEmbedded}
+	_, embeddedErr := fs.Stat(assets.TemplatesFS, path.Join("templates", templatePath))
+	local, err := os.ReadFile(filepath.Join(targetDir, ".spec", "templates", filepath.FromSlash(templatePath)))
+	if os.IsNotExist(err) {
+		for _, pack := range activePacks(targetDir) {
+			content, err := os.ReadFile(filepath.Join(pack.Dir, "templates", filepath.FromSlash(templatePath)))
+			if err == nil {
+				info.State = TemplatePack
+				info.Pack = pack.Name
+				_, info.Extends, _, _ = parseTemplateOverride(templatePath, content)
+				return info, nil
+			}
+		}
+		if embeddedErr != nil {
+			return info, fmt.Errorf("template %s not found", templatePath)
+		}
+		return info, nil
+	}
+	if err != nil {
+		return info, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+
+	_, info.Extends, _, _ = parseTemplateOverride(templatePath, local)
+	switch {
+	case embeddedErr != nil:
+		info.State = TemplateCustom
+	default:
+		embedded, err := embeddedTemplate(templatePath)
+		if err != nil {
+			return info, err
+		}
+		info.State = TemplateOverridden
+		if string(embedded) == string(local) {
+			info.State = TemplateLocalized
+		}
+	}
+	return info, nil
+}
+
+// ListTemplates returns the built-in, localized and pack templates with their
+// state
+func ListTemplates(targetDir string) ([]TemplateInfo, error) {
+	names := make(map[string]bool)
+	err := fs.WalkDir(assets.TemplatesFS, "templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names[strings.TrimPrefix(p, "templates/")] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded templates: %w", err)
+	}
+
+	for _, source := range templateSources(targetDir) {
+		if err := collectTemplateNames(source, names); err != nil {
+			return nil, err
+		}
+	}
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var templates []TemplateInfo
+	for _, name := range sorted {
+		info, err := templateInfo(targetDir, name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, info)
+	}
+	return templates, nil
+}
+
+// collectTemplateNames adds the paths of the templates below a directory,
+// skipping hidden files
+func collectTemplateNames(dir string, names map[string]bool) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			names[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	return nil
+}
+
+// DiffTemplate compares the effective content of a template, with any
+// inheritance applied, against its built-in version
+func DiffTemplate(targetDir, name string) (TemplateDiff, error) {
+	templatePath, err := templateFileName(name)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+	info, err := templateInfo(targetDir, templatePath)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+	diff := TemplateDiff{Name: templatePath, State: info.State}
+	if info.State == TemplateEmbedded || info.State == TemplateLocalized {
+		return diff, nil
+	}
+
+	effective, err := renderTemplate(targetDir, templatePath, map[string]bool{})
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+	embedded, err := embeddedTemplate(templatePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return TemplateDiff{}, err
+	}
+	if string(embedded) != string(effective) {
+		diff.Lines = diffLines(templateLines(embedded), templateLines(effective))
+	}
+	return diff, nil
+}
+
+// templateLines splits template content into lines for diffing
+func templateLines(content []byte) []string {
+	text := strings.TrimRight(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// LocalizeTemplate copies a single built-in template to .spec/templates,
+// leaving other localized templates alone. With extends, a stub extending the
+// built-in template is written instead of a full copy. An existing localized
+// template is never overwritten.
+func LocalizeTemplate(targetDir, name string, extends bool) (string, error) {
+	templatePath, err := templateFileName(name)
+	if err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(targetDir, ".spec", "templates", filepath.FromSlash(templatePath))
+	if _, err := os.Stat(localPath); err == nil {
+		return "", fmt.Errorf("template %s is already localized; run 'specware templates reset %s' first", templatePath, templatePath)
+	}
+
+	content, err := embeddedTemplate(templatePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("no built-in template %s", templatePath)
+	}
+	if err != nil {
+		return "", err
+	}
+	if extends {
+		if path.Ext(templatePath) != ".md" {
+			return "", fmt.Errorf("only markdown templates can extend another template")
+		}
+		content = []byte("<!-- specware:extends -->\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(localPath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	return filepath.Join(".spec", "templates", filepath.FromSlash(templatePath)), nil
+}
+
+// ResetTemplate removes a localized template so the built-in one is used
+// again. Set directories left empty are removed as well.
+func ResetTemplate(targetDir, name string) (string, error) {
+	templatePath, err := templateFileName(name)
+	if err != nil {
+		return "", err
+	}
+	templatesDir := filepath.Join(targetDir, ".spec", "templates")
+	localPath := filepath.Join(templatesDir, filepath.FromSlash(templatePath))
+	if err := os.Remove(localPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("template %s is not localized", templatePath)
+		}
+		return "", fmt.Errorf("failed to remove template: %w", err)
+	}
+	for dir := filepath.Dir(localPath); dir != templatesDir && strings.HasPrefix(dir, templatesDir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return filepath.Join(".spec", "templates", filepath.FromSlash(templatePath)), nil
+}
@@ -0,0 +1,255 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Template sets", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-templates-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	readFeatureFile := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(tempDir, ".spec", "001-login-crash", name))
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("lists the embedded sets and local directories", func() {
+		Expect(os.MkdirAll(filepath.Join(tempDir, ".spec", "templates", "migration"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(tempDir, ".spec", "templates", "specs"), 0755)).To(Succeed())
+
+		sets, err := spec.TemplateSets(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sets).To(Equal([]string{"bugfix", "feature", "migration", "spike"}))
+	})
+
+	It("creates features from a set and records the type", func() {
+		_, err := spec.CreateNewRequirementsWithOptions(tempDir, "login-crash", spec.RequirementsOptions{Type: "bugfix"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readFeatureFile("requirements.md")).To(ContainSubstring("## Steps to Reproduce"))
+		Expect(readFeatureFile(".spec-status.json")).To(ContainSubstring(`"type": "bugfix"`))
+
+		_, err = spec.CreateNewImplementationPlan(tempDir, "login-crash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readFeatureFile("implementation-plan.md")).To(ContainSubstring("### Phase 1: Reproduce"))
+		Expect(readFeatureFile("context-implementation-plan.md")).To(ContainSubstring("Implementation Plan"))
+	})
+
+	It("records the default type", func() {
+		_, err := spec.CreateNewRequirements(tempDir, "login-crash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readFeatureFile(".spec-status.json")).To(ContainSubstring(`"type": "feature"`))
+		Expect(readFeatureFile("requirements.md")).To(ContainSubstring("# Requirements Specification"))
+	})
+
+	It("prefers local set templates and falls back to the top-level ones", func() {
+		setDir := filepath.Join(tempDir, ".spec", "templates", "migration")
+		Expect(os.MkdirAll(setDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(setDir, "requirements.md"), []byte("# Migration: [Feature Name]\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, ".spec", "templates", "implementation-plan.md"), []byte("# Local plan\n"), 0644)).To(Succeed())
+
+		_, err := spec.CreateNewRequirementsWithOptions(tempDir, "login-crash", spec.RequirementsOptions{Type: "migration"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readFeatureFile("requirements.md")).To(Equal("# Migration: [Feature Name]\n"))
+
+		_, err = spec.CreateNewImplementationPlan(tempDir, "login-crash")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readFeatureFile("implementation-plan.md")).To(Equal("# Local plan\n"))
+	})
+
+	It("rejects unknown types without creating the feature", func() {
+		_, err := spec.CreateNewRequirementsWithOptions(tempDir, "login-crash", spec.RequirementsOptions{Type: "epic"})
+		Expect(err).To(MatchError(ContainSubstring(`unknown template set "epic"`)))
+		Expect(filepath.Join(tempDir, ".spec", "001-login-crash")).NotTo(BeADirectory())
+
+		_, err = spec.CreateNewRequirementsWithOptions(tempDir, "login-crash", spec.RequirementsOptions{Type: "specs"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("inheritance", func() {
+		writeTemplate := func(name, content string) {
+			templatePath := filepath.Join(tempDir, ".spec", "templates", name)
+			Expect(os.MkdirAll(filepath.Dir(templatePath), 0755)).To(Succeed())
+			Expect(os.WriteFile(templatePath, []byte(content), 0644)).To(Succeed())
+		}
+
+		It("adds, replaces and removes sections of the embedded template", func() {
+			writeTemplate("requirements.md", `<!-- specware:extends -->
+
+<!-- specware:add after "Technical Requirements" -->
+## Security Review
+Threat model.
+
+<!-- specware:remove Dependencies -->
+
+<!-- specware:replace "Constraints" -->
+Budget only.
+
+<!-- specware:replace "Problem Statement" -->
+## Background
+Why now.
+
+<!-- specware:add -->
+## Open Questions
+`)
+			content, err := spec.RenderTemplate(tempDir, "", "requirements")
+			Expect(err).NotTo(HaveOccurred())
+			rendered := string(content)
+			Expect(rendered).To(HavePrefix("# Requirements Specification: [Feature Name]\n\n## Background\nWhy now.\n\n## Solution Overview\n"))
+			Expect(rendered).To(ContainSubstring("quality requirements.\n\n## Security Review\nThreat model.\n\n## Acceptance Criteria\n"))
+			Expect(rendered).To(HaveSuffix("## Constraints\nBudget only.\n\n## Open Questions\n"))
+			Expect(rendered).NotTo(ContainSubstring("Dependencies"))
+			Expect(rendered).NotTo(ContainSubstring("## Problem Statement"))
+
+			_, err = spec.CreateNewRequirements(tempDir, "login-crash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readFeatureFile("requirements.md")).To(Equal(rendered))
+		})
+
+		It("extends set templates and other local templates", func() {
+			writeTemplate("bugfix/requirements.md", "<!-- specware:extends -->\n<!-- specware:add before \"Root Cause\" -->\n## Logs\n")
+			content, err := spec.RenderTemplate(tempDir, "bugfix", "requirements.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("## Logs\n\n## Root Cause\n"))
+
+			writeTemplate("requirements.md", "<!-- specware:extends -->\n<!-- specware:add -->\n## Security Review\n")
+			writeTemplate("migration/requirements.md", "<!-- specware:extends requirements.md -->\n<!-- specware:add -->\n## Rollback\n")
+			content, err = spec.RenderTemplate(tempDir, "migration", "requirements")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(HavePrefix("# Requirements Specification"))
+			Expect(string(content)).To(HaveSuffix("## Security Review\n\n## Rollback\n"))
+		})
+
+		It("leaves templates without an extends directive unchanged", func() {
+			writeTemplate("requirements.md", "# Ours\n<!-- specware:remove Title -->\n")
+			content, err := spec.RenderTemplate(tempDir, "", "requirements")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("# Ours\n<!-- specware:remove Title -->\n"))
+		})
+
+		It("reports invalid overrides with their line", func() {
+			writeTemplate("requirements.md", "<!-- specware:extends -->\n\n<!-- specware:remove \"Missing\" -->\n")
+			_, err := spec.RenderTemplate(tempDir, "", "requirements")
+			Expect(err).To(MatchError(ContainSubstring(`requirements.md:3: section "Missing" not found`)))
+
+			writeTemplate("requirements.md", "<!-- specware:extends -->\nstray text\n")
+			_, err = spec.CreateNewRequirements(tempDir, "login-crash")
+			Expect(err).To(MatchError(ContainSubstring("requirements.md:2: content must follow")))
+			Expect(filepath.Join(tempDir, ".spec", "001-login-crash")).NotTo(BeADirectory())
+
+			writeTemplate("requirements.md", "<!-- specware:extends missing.md -->\n<!-- specware:add -->\n## A\n")
+			_, err = spec.RenderTemplate(tempDir, "", "requirements")
+			Expect(err).To(MatchError(ContainSubstring("extends missing.md, which was not found")))
+
+			_, err = spec.RenderTemplate(tempDir, "", "nope")
+			Expect(err).To(MatchError(ContainSubstring("template nope.md not found")))
+		})
+	})
+
+	Describe("managing localized templates", func() {
+		stateOf := func(name string) string {
+			templates, err := spec.ListTemplates(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			for _, t := range templates {
+				if t.Name == name {
+					return t.State
+				}
+			}
+			return ""
+		}
+
+		It("lists templates with their state", func() {
+			Expect(stateOf("requirements.md")).To(Equal(spec.TemplateEmbedded))
+			Expect(stateOf("bugfix/requirements.md")).To(Equal(spec.TemplateEmbedded))
+			Expect(stateOf("specs/openapi.yaml")).To(Equal(spec.TemplateEmbedded))
+
+			_, err := spec.LocalizeTemplate(tempDir, "requirements", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stateOf("requirements.md")).To(Equal(spec.TemplateLocalized))
+
+			localPath := filepath.Join(tempDir, ".spec", "templates", "requirements.md")
+			Expect(os.WriteFile(localPath, []byte("# Ours\n"), 0644)).To(Succeed())
+			Expect(stateOf("requirements.md")).To(Equal(spec.TemplateOverridden))
+
+			Expect(os.MkdirAll(filepath.Join(tempDir, ".spec", "templates", "migration"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, ".spec", "templates", "migration", "requirements.md"), []byte("# Migration\n"), 0644)).To(Succeed())
+			Expect(stateOf("migration/requirements.md")).To(Equal(spec.TemplateCustom))
+		})
+
+		It("localizes a single template without overwriting", func() {
+			file, err := spec.LocalizeTemplate(tempDir, "bugfix/requirements", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file).To(Equal(filepath.Join(".spec", "templates", "bugfix", "requirements.md")))
+			Expect(filepath.Join(tempDir, ".spec", "templates", "requirements.md")).NotTo(BeAnExistingFile())
+
+			_, err = spec.LocalizeTemplate(tempDir, "bugfix/requirements.md", false)
+			Expect(err).To(MatchError(ContainSubstring("already localized")))
+
+			_, err = spec.LocalizeTemplate(tempDir, "epic", false)
+			Expect(err).To(MatchError(ContainSubstring("no built-in template epic.md")))
+		})
+
+		It("creates extending stubs", func() {
+			_, err := spec.LocalizeTemplate(tempDir, "implementation-plan", true)
+			Expect(err).NotTo(HaveOccurred())
+			templates, err := spec.ListTemplates(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(ContainElement(spec.TemplateInfo{Name: "implementation-plan.md", State: spec.TemplateOverridden, Extends: true}))
+
+			rendered, err := spec.RenderTemplate(tempDir, "", "implementation-plan")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(rendered)).To(HavePrefix("# Implementation Plan: [Feature Name]"))
+
+			_, err = spec.LocalizeTemplate(tempDir, "specs/openapi.yaml", true)
+			Expect(err).To(MatchError(ContainSubstring("only markdown templates")))
+		})
+
+		It("diffs the effective template against the built-in one", func() {
+			diff, err := spec.DiffTemplate(tempDir, "requirements")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.State).To(Equal(spec.TemplateEmbedded))
+			Expect(diff.Lines).To(BeEmpty())
+
+			localPath := filepath.Join(tempDir, ".spec", "templates", "requirements.md")
+			Expect(os.MkdirAll(filepath.Dir(localPath), 0755)).To(Succeed())
+			Expect(os.WriteFile(localPath, []byte("<!-- specware:extends -->\n<!-- specware:add -->\n## Security Review\n"), 0644)).To(Succeed())
+
+			diff, err = spec.DiffTemplate(tempDir, "requirements.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.State).To(Equal(spec.TemplateOverridden))
+			Expect(diff.Lines).To(ContainElement(spec.DiffLine{Op: "+", Text: "## Security Review"}))
+			Expect(diff.Lines).NotTo(ContainElement(HaveField("Op", "-")))
+		})
+
+		It("resets localized templates", func() {
+			_, err := spec.LocalizeTemplate(tempDir, "spike/requirements", false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = spec.ResetTemplate(tempDir, "spike/requirements")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(tempDir, ".spec", "templates", "spike")).NotTo(BeADirectory())
+			Expect(filepath.Join(tempDir, ".spec", "templates")).To(BeADirectory())
+
+			_, err = spec.ResetTemplate(tempDir, "spike/requirements")
+			Expect(err).To(MatchError(ContainSubstring("is not localized")))
+			_, err = spec.ResetTemplate(tempDir, "../config.json")
+			Expect(err).To(MatchError(ContainSubstring("invalid template name")))
+		})
+	})
+})
@@ -0,0 +1,279 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RequirementSection is a requirements.md section whose bullets get IDs
+type RequirementSection struct {
+	Title  string
+	Prefix string
+}
+
+// RequirementSections lists the sections numbered by AssignRequirementIDs
+var RequirementSections = []RequirementSection{
+	{Title: "Functional Requirements", Prefix: "FR"},
+	{Title: "Technical Requirements", Prefix: "TR"},
+	{Title: "Acceptance Criteria", Prefix: "AC"},
+}
+
+var (
+	// requirementBulletPattern matches a top-level bullet or numbered list
+	// item, with an optional checkbox, capturing the marker and the text
+	requirementBulletPattern = regexp.MustCompile(`^((?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?)(.*)$`)
+	// requirementIDPrefixPattern matches an ID at the start of a bullet, e.g.
+	// "FR-1: ", "**AC-2**: " or "TR-3 - "
+	requirementIDPrefixPattern = regexp.MustCompile(`^(?:\*\*)?((?:FR|TR|AC)-(\d+))(?:\*\*)?(?:\s*[:.\-]\s*|\s+|$)`)
+	// requirementRefPattern finds requirement IDs referenced anywhere in text
+	requirementRefPattern = regexp.MustCompile(`\b(?:FR|TR|AC)-\d+\b`)
+)
+
+// Requirement is a bullet under one of the RequirementSections. ID is empty
+// until IDs have been assigned.
+type Requirement struct {
+	ID      string `json:"id"`
+	Section string `json:"section"`
+	Text    string `json:"text"`
+	Line    int    `json:"line"`
+}
+
+// requirementSectionPrefix returns the ID prefix for a section title
+func requirementSectionPrefix(title string) (string, bool) {
+	for _, section := range RequirementSections {
+		if strings.EqualFold(strings.TrimSpace(title), section.Title) {
+			return section.Prefix, true
+		}
+	}
+	return "", false
+}
+
+// parseRequirements returns the top-level bullets of the requirement sections
+// in document order. Bullets in nested sub-sections are included; indented
+// sub-bullets and fenced code are not.
+func parseRequirements(content []byte) []Requirement {
+	type span struct {
+		start, end int
+		section    string
+	}
+	var spans []span
+	for _, h := range ParseHeadings(content) {
+		if _, ok := requirementSectionPrefix(h.Title); ok {
+			startLine := h.Line + 1
+			endLine := h.Line + 1 + strings.Count(string(content[h.BodyStart:h.End]), "\n")
+			spans = append(spans, span{startLine, endLine, h.Title})
+		}
+	}
+
+	var requirements []Requirement
+	inFence := false
+	for i, line := range strings.Split(string(content), "\n") {
+		lineNum := i + 1
+		line = strings.TrimRight(line, "\r")
+		if fenceOf(strings.TrimLeft(line, " ")) != "" {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := requirementBulletPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		for _, s := range spans {
+			if lineNum < s.start || lineNum >= s.end {
+				continue
+			}
+			prefix, _ := requirementSectionPrefix(s.section)
+			req := Requirement{Section: s.section, Text: strings.TrimSpace(m[2]), Line: lineNum}
+			if id := requirementIDPrefixPattern.FindStringSubmatch(m[2]); id != nil && strings.HasPrefix(id[1], prefix+"-") {
+				req.ID = id[1]
+				req.Text = strings.TrimSpace(m[2][len(id[0]):])
+			}
+			requirements = append(requirements, req)
+			break
+		}
+	}
+	return requirements
+}
+
+// readRequirements reads requirements.md from a feature directory
+func readRequirements(featureDir string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(featureDir, "requirements.md"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("requirements.md not found in %s", filepath.Base(featureDir))
+		}
+		return nil, fmt.Errorf("failed to read requirements.md: %w", err)
+	}
+	return content, nil
+}
+
+// ListRequirements returns the bullets of the Functional Requirements,
+// Technical Requirements and Acceptance Criteria sections of a feature
+func ListRequirements(targetDir, shortName string) ([]Requirement, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readRequirements(featureDir)
+	if err != nil {
+		return nil, err
+	}
+	return parseRequirements(content), nil
+}
+
+// AssignRequirementIDs gives every bullet of the requirement sections of
+// requirements.md an ID such as FR-1, TR-2 or AC-3. Existing IDs are kept, so
+// IDs stay stable as requirements are added or reordered; new bullets are
+// numbered after the highest ID of their section. It returns all requirements
+// and the number of IDs assigned.
+func AssignRequirementIDs(targetDir, shortName string) ([]Requirement, int, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, 0, err
+	}
+	content, err := readRequirements(featureDir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	requirements := parseRequirements(content)
+	next := make(map[string]int)
+	for _, req := range requirements {
+		if req.ID == "" {
+			continue
+		}
+		prefix, number, _ := strings.Cut(req.ID, "-")
+		if n, err := strconv.Atoi(number); err == nil && n >= next[prefix] {
+			next[prefix] = n + 1
+		}
+	}
+
+	lines := strings.Split(string(content), "\n")
+	assigned := 0
+	for i, req := range requirements {
+		if req.ID != "" {
+			continue
+		}
+		prefix, _ := requirementSectionPrefix(req.Section)
+		if next[prefix] == 0 {
+			next[prefix] = 1
+		}
+		req.ID = fmt.Sprintf("%s-%d", prefix, next[prefix])
+		next[prefix]++
+
+		line := lines[req.Line-1]
+		m := requirementBulletPattern.FindStringSubmatchIndex(strings.TrimRight(line, "\r"))
+		lines[req.Line-1] = line[:m[4]] + req.ID + ": " + line[m[4]:]
+		requirements[i] = req
+		assigned++
+	}
+
+	if assigned > 0 {
+		if err := os.WriteFile(filepath.Join(featureDir, "requirements.md"), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			return nil, 0, fmt.Errorf("failed to write requirements.md: %w", err)
+		}
+	}
+	return requirements, assigned, nil
+}
+
+// RequirementTrace is a requirement with the plan items that reference it
+type RequirementTrace struct {
+	Requirement
+	CoveredBy []string `json:"covered-by"`
+}
+
+// UnknownReference is a requirement ID referenced by the plan that does not
+// exist in requirements.md
+type UnknownReference struct {
+	ID           string   `json:"id"`
+	ReferencedBy []string `json:"referenced-by"`
+}
+
+// TraceReport maps requirements to the implementation plan items that
+// reference them
+type TraceReport struct {
+	Requirements []RequirementTrace `json:"requirements"`
+	Unknown      []UnknownReference `json:"unknown,omitempty"`
+	// Unnumbered counts requirements without an ID, which cannot be traced
+	Unnumbered int  `json:"unnumbered"`
+	HasPlan    bool `json:"has-plan"`
+}
+
+// Uncovered returns the numbered requirements no plan item references
+func (r TraceReport) Uncovered() []RequirementTrace {
+	var uncovered []RequirementTrace
+	for _, req := range r.Requirements {
+		if req.ID != "" && len(req.CoveredBy) == 0 {
+			uncovered = append(uncovered, req)
+		}
+	}
+	return uncovered
+}
+
+// TraceRequirements builds a traceability report for a feature. A plan item
+// (milestone, phase or step) covers a requirement when its title or body
+// mentions the requirement's ID.
+func TraceRequirements(targetDir, shortName string) (TraceReport, error) {
+	var report TraceReport
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return report, err
+	}
+	content, err := readRequirements(featureDir)
+	if err != nil {
+		return report, err
+	}
+
+	known := make(map[string]int)
+	for _, req := range parseRequirements(content) {
+		if req.ID == "" {
+			report.Unnumbered++
+		} else {
+			known[req.ID] = len(report.Requirements)
+		}
+		report.Requirements = append(report.Requirements, RequirementTrace{Requirement: req})
+	}
+
+	plan, err := os.ReadFile(filepath.Join(featureDir, "implementation-plan.md"))
+	if err != nil && !os.IsNotExist(err) {
+		return report, fmt.Errorf("failed to read implementation-plan.md: %w", err)
+	}
+	report.HasPlan = err == nil
+
+	unknown := make(map[string][]string)
+	var visit func(items []*PlanItem)
+	visit = func(items []*PlanItem) {
+		for _, item := range items {
+			seen := make(map[string]bool)
+			for _, id := range requirementRefPattern.FindAllString(item.Title+"\n"+item.Body, -1) {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				if i, ok := known[id]; ok {
+					report.Requirements[i].CoveredBy = append(report.Requirements[i].CoveredBy, item.Title)
+				} else {
+					unknown[id] = append(unknown[id], item.Title)
+				}
+			}
+			visit(item.Children)
+		}
+	}
+	visit(ParsePlanItems(plan))
+
+	for id, items := range unknown {
+		report.Unknown = append(report.Unknown, UnknownReference{ID: id, ReferencedBy: items})
+	}
+	sort.Slice(report.Unknown, func(i, j int) bool {
+		return report.Unknown[i].ID < report.Unknown[j].ID
+	})
+	return report, nil
+}
@@ -0,0 +1,114 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Requirement traceability", func() {
+	var (
+		tempDir          string
+		requirementsPath string
+	)
+
+	const requirements = `# Requirements Specification: Password Reset
+
+## Problem Statement
+- not a requirement
+
+## Functional Requirements
+- Users can request a reset link
+  - sub-bullets are details, not requirements
+- FR-7: Admins can lock accounts
+
+` + "```" + `
+- code is ignored
+` + "```" + `
+
+## Technical Requirements
+### Security
+* Tokens are single use
+
+## Acceptance Criteria
+1. A reset link expires after 24 hours
+- [ ] Locked users cannot sign in
+`
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-trace-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+
+		requirementsPath = filepath.Join(tempDir, ".spec", "001-reset", "requirements.md")
+		Expect(os.WriteFile(requirementsPath, []byte(requirements), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("assigns stable IDs after the highest existing ID of each section", func() {
+		reqs, assigned, err := spec.AssignRequirementIDs(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assigned).To(Equal(4))
+
+		var ids []string
+		for _, req := range reqs {
+			ids = append(ids, req.ID)
+		}
+		Expect(ids).To(Equal([]string{"FR-8", "FR-7", "TR-1", "AC-1", "AC-2"}))
+		Expect(reqs[0].Text).To(Equal("Users can request a reset link"))
+
+		content, err := os.ReadFile(requirementsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("- FR-8: Users can request a reset link\n  - sub-bullets"))
+		Expect(string(content)).To(ContainSubstring("* TR-1: Tokens are single use"))
+		Expect(string(content)).To(ContainSubstring("1. AC-1: A reset link expires after 24 hours\n- [ ] AC-2: Locked users cannot sign in"))
+		Expect(string(content)).To(ContainSubstring("- not a requirement\n"))
+		Expect(string(content)).To(ContainSubstring("- code is ignored\n"))
+
+		_, assigned, err = spec.AssignRequirementIDs(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assigned).To(BeZero())
+		again, err := os.ReadFile(requirementsPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(Equal(content))
+	})
+
+	It("reports coverage by plan items, unnumbered requirements and unknown IDs", func() {
+		report, err := spec.TraceRequirements(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.HasPlan).To(BeFalse())
+		Expect(report.Unnumbered).To(Equal(4))
+
+		_, _, err = spec.AssignRequirementIDs(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		plan := "# Plan\n\n### Milestone 1: Reset (FR-8)\n\n#### Phase 1: API\n\n- [ ] Step 1: Endpoint\n\nCovers AC-1 and FR-8.\n\n- [ ] Step 2: Lockout (FR-7, AC-9)\n"
+		Expect(os.WriteFile(filepath.Join(tempDir, ".spec", "001-reset", "implementation-plan.md"), []byte(plan), 0644)).To(Succeed())
+
+		report, err = spec.TraceRequirements(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.HasPlan).To(BeTrue())
+		Expect(report.Unnumbered).To(BeZero())
+		Expect(report.Requirements[0].CoveredBy).To(Equal([]string{"Milestone 1: Reset (FR-8)", "Step 1: Endpoint"}))
+		Expect(report.Requirements[1].CoveredBy).To(Equal([]string{"Step 2: Lockout (FR-7, AC-9)"}))
+
+		var uncovered []string
+		for _, req := range report.Uncovered() {
+			uncovered = append(uncovered, req.ID)
+		}
+		Expect(uncovered).To(Equal([]string{"TR-1", "AC-2"}))
+		Expect(report.Unknown).To(Equal([]spec.UnknownReference{{ID: "AC-9", ReferencedBy: []string{"Step 2: Lockout (FR-7, AC-9)"}}}))
+	})
+})
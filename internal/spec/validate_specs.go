@@ -0,0 +1,857 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Kinds of technical spec checked by ValidateTechSpecs
+const (
+	SpecKindOpenAPI    = "openapi"
+	SpecKindJSONSchema = "json-schema"
+	SpecKindMermaid    = "mermaid"
+	SpecKindYAML       = "yaml"
+	SpecKindJSON       = "json"
+)
+
+// SpecProblem is a single issue found in a technical spec. Line is 0 when the
+// problem is not tied to a line.
+type SpecProblem struct {
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the problem with its line number, if any
+func (p SpecProblem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// SpecValidation is the result of validating one technical spec file
+type SpecValidation struct {
+	File     string        `json:"file"`
+	Kind     string        `json:"kind"`
+	Problems []SpecProblem `json:"problems,omitempty"`
+}
+
+// Valid reports whether no problems were found
+func (v SpecValidation) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+// ValidateTechSpecs checks the technical specs of a feature offline. YAML and
+// JSON files are checked for syntax, OpenAPI documents for OpenAPI 3.x
+// structure and resolvable local references, JSON Schemas for well-formed
+// keywords, and Mermaid diagrams (.mmd files or mermaid code blocks in
+// markdown) for a known diagram type and balanced blocks. The kind comes from
+// specs.json when the file was registered with add-spec, and otherwise from
+// the extension and content. Files of other kinds are not reported.
+func ValidateTechSpecs(targetDir, shortName string) ([]SpecValidation, error) {
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	return validateFeatureSpecs(featureDir, artifactDocuments(config))
+}
+
+// validateFeatureSpecs validates the technical specs in a feature directory.
+// documents are the configured artifact documents, which are not specs.
+func validateFeatureSpecs(featureDir string, documents map[string]bool) ([]SpecValidation, error) {
+	manifest, err := readSpecManifest(featureDir)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]string)
+	for _, s := range manifest.Specs {
+		registered[s.Name] = s.Type
+	}
+
+	entries, err := os.ReadDir(featureDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature directory: %w", err)
+	}
+	var results []SpecValidation
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || artifactKind(name, documents) != ArtifactSpec {
+			continue
+		}
+		present[name] = true
+		content, err := os.ReadFile(filepath.Join(featureDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if result, ok := validateSpecFile(name, content, registered[name]); ok {
+			results = append(results, result)
+		}
+	}
+
+	for _, s := range manifest.Specs {
+		if !present[s.Name] {
+			results = append(results, SpecValidation{
+				File:     s.Name,
+				Kind:     s.Type,
+				Problems: []SpecProblem{{Message: fmt.Sprintf("registered in %s but the file is missing", SpecManifestFileName)}},
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].File < results[j].File
+	})
+	return results, nil
+}
+
+// validateSpecFile validates one file. It returns false for files that are
+// not of a kind that can be checked.
+func validateSpecFile(name string, content []byte, registeredType string) (SpecValidation, bool) {
+	result := SpecValidation{File: name}
+	ext := strings.ToLower(filepath.Ext(name))
+
+	switch {
+	case ext == ".yaml" || ext == ".yml" || ext == ".json":
+		doc, problem := decodeStructured(content, ext == ".json")
+		result.Kind = SpecKindYAML
+		if ext == ".json" {
+			result.Kind = SpecKindJSON
+		}
+		root, _ := doc.(map[string]interface{})
+		switch {
+		case registeredType == SpecKindOpenAPI || hasKey(root, "openapi") || hasKey(root, "swagger"):
+			result.Kind = SpecKindOpenAPI
+		case registeredType == SpecKindJSONSchema || hasKey(root, "$schema"):
+			result.Kind = SpecKindJSONSchema
+		}
+		if problem != nil {
+			result.Problems = append(result.Problems, *problem)
+			return result, true
+		}
+		switch result.Kind {
+		case SpecKindOpenAPI:
+			result.Problems = append(result.Problems, checkOpenAPI(doc)...)
+		case SpecKindJSONSchema:
+			result.Problems = append(result.Problems, checkJSONSchema(doc, "#")...)
+			result.Problems = append(result.Problems, checkLocalRefs(doc, "#", doc)...)
+		}
+		return result, true
+
+	case ext == ".mmd" || ext == ".mermaid":
+		result.Kind = SpecKindMermaid
+		result.Problems = checkMermaid(string(content), 1)
+		return result, true
+
+	case ext == ".md" || ext == ".markdown":
+		blocks := mermaidBlocks(content)
+		expectDiagram := registeredType == "mermaid" || registeredType == "erd"
+		if len(blocks) == 0 && !expectDiagram {
+			return result, false
+		}
+		result.Kind = SpecKindMermaid
+		if len(blocks) == 0 {
+			result.Problems = append(result.Problems, SpecProblem{Message: "no mermaid code block found"})
+		}
+		for _, block := range blocks {
+			result.Problems = append(result.Problems, checkMermaid(block.text, block.line)...)
+		}
+		return result, true
+	}
+	return result, false
+}
+
+// hasKey reports whether a decoded object has the given key
+func hasKey(object map[string]interface{}, key string) bool {
+	_, ok := object[key]
+	return ok
+}
+
+// decodeStructured parses JSON or YAML into generic values, reporting syntax
+// errors with their line number
+func decodeStructured(content []byte, isJSON bool) (interface{}, *SpecProblem) {
+	var doc interface{}
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			problem := SpecProblem{Message: "invalid JSON: " + err.Error()}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				problem.Line = bytes.Count(content[:syntaxErr.Offset], []byte("\n")) + 1
+			}
+			return nil, &problem
+		}
+		if decoder.More() {
+			return nil, &SpecProblem{Message: "invalid JSON: unexpected content after the top-level value"}
+		}
+		return doc, nil
+	}
+
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		problem := SpecProblem{Message: "invalid YAML: " + err.Error()}
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			fmt.Sscanf(m[1], "%d", &problem.Line)
+			problem.Message = "invalid YAML: " + strings.TrimSpace(yamlLinePattern.ReplaceAllString(strings.TrimPrefix(err.Error(), "yaml: "), ""))
+		}
+		return nil, &problem
+	}
+	return normalizeYAML(doc), nil
+}
+
+// normalizeYAML converts YAML mappings with non-string keys, such as the
+// unquoted status codes of OpenAPI responses, to string-keyed maps
+func normalizeYAML(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			node[key] = normalizeYAML(value)
+		}
+		return node
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			converted[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range node {
+			node[i] = normalizeYAML(item)
+		}
+		return node
+	}
+	return v
+}
+
+// yamlLinePattern extracts the line number from a YAML error message
+var yamlLinePattern = regexp.MustCompile(`line (\d+):\s*`)
+
+// numberValue returns a decoded JSON or YAML number as a float64
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// pointerEscaper escapes a key for use as a JSON pointer token
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// resolveLocalRef resolves a "#/a/b" JSON pointer against a document
+func resolveLocalRef(root interface{}, ref string) bool {
+	if ref == "#" {
+		return true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return false
+	}
+	current := root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return false
+			}
+			current = next
+		case []interface{}:
+			var index int
+			if _, err := fmt.Sscanf(token, "%d", &index); err != nil || index < 0 || index >= len(node) {
+				return false
+			}
+			current = node[index]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkLocalRefs reports every local $ref in a document that does not resolve
+func checkLocalRefs(node interface{}, path string, root interface{}) []SpecProblem {
+	var problems []SpecProblem
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok && strings.HasPrefix(ref, "#") && !resolveLocalRef(root, ref) {
+			problems = append(problems, SpecProblem{Message: fmt.Sprintf("%s: $ref %s does not resolve", path, ref)})
+		}
+		keys := make([]string, 0, len(n))
+		for key := range n {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			problems = append(problems, checkLocalRefs(n[key], path+"/"+pointerEscaper.Replace(key), root)...)
+		}
+	case []interface{}:
+		for i, item := range n {
+			problems = append(problems, checkLocalRefs(item, fmt.Sprintf("%s/%d", path, i), root)...)
+		}
+	}
+	return problems
+}
+
+var (
+	openAPIVersionPattern = regexp.MustCompile(`^3\.\d+(\.\d+)?$`)
+	responseCodePattern   = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+	pathParamPattern      = regexp.MustCompile(`\{([^}/]+)\}`)
+	openAPIMethods        = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+)
+
+// checkOpenAPI checks the structure required by OpenAPI 3.x
+func checkOpenAPI(doc interface{}) []SpecProblem {
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return []SpecProblem{{Message: "OpenAPI document must be an object"}}
+	}
+	if _, ok := root["swagger"]; ok {
+		return []SpecProblem{{Message: "Swagger 2.0 documents are not supported; expected openapi: 3.x"}}
+	}
+
+	var problems []SpecProblem
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, SpecProblem{Message: fmt.Sprintf(format, args...)})
+	}
+
+	version, _ := root["openapi"].(string)
+	if !openAPIVersionPattern.MatchString(version) {
+		add("openapi must be a 3.x version string such as \"3.0.3\", got %v", root["openapi"])
+	}
+	is30 := strings.HasPrefix(version, "3.0")
+
+	if info, ok := root["info"].(map[string]interface{}); !ok {
+		add("info object is required")
+	} else {
+		if title, _ := info["title"].(string); strings.TrimSpace(title) == "" {
+			add("info.title is required")
+		}
+		if _, ok := info["version"].(string); !ok {
+			add("info.version is required and must be a string (quote numbers such as \"1.0\")")
+		}
+	}
+
+	paths, hasPaths := root["paths"]
+	if !hasPaths {
+		if is30 || (!hasKey(root, "components") && !hasKey(root, "webhooks")) {
+			add("paths object is required")
+		}
+	}
+	if hasPaths {
+		pathItems, ok := paths.(map[string]interface{})
+		if !ok {
+			add("paths must be an object")
+		}
+		pathNames := make([]string, 0, len(pathItems))
+		for name := range pathItems {
+			pathNames = append(pathNames, name)
+		}
+		sort.Strings(pathNames)
+
+		operationIDs := make(map[string]string)
+		for _, name := range pathNames {
+			if !strings.HasPrefix(name, "/") {
+				add("paths: %q must start with /", name)
+			}
+			item, ok := pathItems[name].(map[string]interface{})
+			if !ok {
+				if pathItems[name] != nil {
+					add("paths.%s must be an object", name)
+				}
+				continue
+			}
+
+			for _, method := range openAPIMethods {
+				raw, ok := item[method]
+				if !ok {
+					continue
+				}
+				where := fmt.Sprintf("paths.%s.%s", name, method)
+				operation, ok := raw.(map[string]interface{})
+				if !ok {
+					add("%s must be an object", where)
+					continue
+				}
+
+				if id, ok := operation["operationId"].(string); ok {
+					if other, dup := operationIDs[id]; dup {
+						add("%s: operationId %q is also used by %s", where, id, other)
+					}
+					operationIDs[id] = where
+				}
+
+				responses, hasResponses := operation["responses"]
+				if !hasResponses {
+					if is30 {
+						add("%s.responses is required", where)
+					}
+				} else if codes, ok := responses.(map[string]interface{}); !ok || len(codes) == 0 {
+					add("%s.responses must define at least one response", where)
+				} else {
+					for code := range codes {
+						if !responseCodePattern.MatchString(code) {
+							add("%s.responses: %q is not a status code", where, code)
+						}
+					}
+				}
+
+				// Every templated path segment must be declared as a path parameter
+				declared, complete := declaredPathParams(item["parameters"])
+				opDeclared, opComplete := declaredPathParams(operation["parameters"])
+				if !complete || !opComplete {
+					continue
+				}
+				for _, m := range pathParamPattern.FindAllStringSubmatch(name, -1) {
+					if !declared[m[1]] && !opDeclared[m[1]] {
+						add("%s: path parameter %q is not declared", where, m[1])
+					}
+				}
+			}
+		}
+	}
+
+	if components, ok := root["components"].(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			names := make([]string, 0, len(schemas))
+			for name := range schemas {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				problems = append(problems, checkJSONSchema(schemas[name], "#/components/schemas/"+pointerEscaper.Replace(name))...)
+			}
+		}
+	}
+
+	return append(problems, checkLocalRefs(doc, "#", doc)...)
+}
+
+// declaredPathParams returns the names of parameters declared "in: path". It
+// reports false when a parameter is a $ref, whose location is not followed.
+func declaredPathParams(raw interface{}) (map[string]bool, bool) {
+	declared := make(map[string]bool)
+	params, _ := raw.([]interface{})
+	for _, p := range params {
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, isRef := param["$ref"]; isRef {
+			return declared, false
+		}
+		if in, _ := param["in"].(string); in == "path" {
+			if name, ok := param["name"].(string); ok {
+				declared[name] = true
+			}
+		}
+	}
+	return declared, true
+}
+
+// JSON Schema keywords grouped by the kind of value they take
+var (
+	schemaTypes            = map[string]bool{"null": true, "boolean": true, "object": true, "array": true, "number": true, "string": true, "integer": true}
+	schemaMapKeywords      = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+	schemaSubKeywords      = []string{"additionalProperties", "not", "if", "then", "else", "contains", "propertyNames", "unevaluatedProperties", "unevaluatedItems", "additionalItems"}
+	schemaListKeywords     = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaCountKeywords    = []string{"minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties", "minContains", "maxContains"}
+	schemaNumberKeywords   = []string{"minimum", "maximum", "multipleOf"}
+	schemaExclusiveKeyword = []string{"exclusiveMinimum", "exclusiveMaximum"}
+)
+
+// checkJSONSchema checks that a schema and its subschemas use well-formed
+// keywords, which is what validating against the meta-schema verifies for
+// the common vocabulary
+func checkJSONSchema(node interface{}, path string) []SpecProblem {
+	if _, ok := node.(bool); ok {
+		return nil
+	}
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return []SpecProblem{{Message: fmt.Sprintf("%s: schema must be an object or a boolean", path)}}
+	}
+
+	var problems []SpecProblem
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, SpecProblem{Message: path + ": " + fmt.Sprintf(format, args...)})
+	}
+
+	if v, ok := schema["$schema"]; ok {
+		if s, ok := v.(string); !ok || s == "" {
+			add("$schema must be a URI string")
+		}
+	}
+	if v, ok := schema["$ref"]; ok {
+		if _, isString := v.(string); !isString {
+			add("$ref must be a string")
+		}
+	}
+
+	if v, ok := schema["type"]; ok {
+		switch t := v.(type) {
+		case string:
+			if !schemaTypes[t] {
+				add("unknown type %q", t)
+			}
+		case []interface{}:
+			seen := make(map[string]bool)
+			for _, item := range t {
+				name, _ := item.(string)
+				if !schemaTypes[name] {
+					add("unknown type %v", item)
+				} else if seen[name] {
+					add("type %q is listed twice", name)
+				}
+				seen[name] = true
+			}
+		default:
+			add("type must be a string or an array of strings")
+		}
+	}
+
+	if v, ok := schema["required"]; ok {
+		list, isList := v.([]interface{})
+		if !isList {
+			add("required must be an array of property names")
+		}
+		seen := make(map[string]bool)
+		for _, item := range list {
+			name, isString := item.(string)
+			if !isString {
+				add("required entries must be strings")
+			} else if seen[name] {
+				add("required lists %q twice", name)
+			}
+			seen[name] = true
+		}
+	}
+	if v, ok := schema["enum"]; ok {
+		if list, isList := v.([]interface{}); !isList || len(list) == 0 {
+			add("enum must be a non-empty array")
+		}
+	}
+	for _, keyword := range schemaCountKeywords {
+		if v, ok := schema[keyword]; ok {
+			if n, isNumber := numberValue(v); !isNumber || n < 0 || n != float64(int64(n)) {
+				add("%s must be a non-negative integer", keyword)
+			}
+		}
+	}
+	for _, keyword := range schemaNumberKeywords {
+		if v, ok := schema[keyword]; ok {
+			if _, isNumber := numberValue(v); !isNumber {
+				add("%s must be a number", keyword)
+			}
+		}
+	}
+	if v, ok := schema["multipleOf"]; ok {
+		if n, isNumber := numberValue(v); isNumber && n <= 0 {
+			add("multipleOf must be greater than 0")
+		}
+	}
+	for _, keyword := range schemaExclusiveKeyword {
+		if v, ok := schema[keyword]; ok {
+			// Draft 4 and OpenAPI 3.0 use booleans, later drafts numbers
+			if _, isBool := v.(bool); !isBool {
+				if _, isNumber := numberValue(v); !isNumber {
+					add("%s must be a number", keyword)
+				}
+			}
+		}
+	}
+	if v, ok := schema["pattern"]; ok {
+		if _, isString := v.(string); !isString {
+			add("pattern must be a string")
+		}
+	}
+
+	for _, keyword := range schemaMapKeywords {
+		v, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		children, isMap := v.(map[string]interface{})
+		if !isMap {
+			add("%s must be an object of schemas", keyword)
+			continue
+		}
+		names := make([]string, 0, len(children))
+		for name := range children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			problems = append(problems, checkJSONSchema(children[name], path+"/"+keyword+"/"+pointerEscaper.Replace(name))...)
+		}
+	}
+	for _, keyword := range schemaSubKeywords {
+		if v, ok := schema[keyword]; ok {
+			problems = append(problems, checkJSONSchema(v, path+"/"+keyword)...)
+		}
+	}
+	for _, keyword := range schemaListKeywords {
+		v, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		list, isList := v.([]interface{})
+		if !isList || len(list) == 0 {
+			add("%s must be a non-empty array of schemas", keyword)
+			continue
+		}
+		for i, item := range list {
+			problems = append(problems, checkJSONSchema(item, fmt.Sprintf("%s/%s/%d", path, keyword, i))...)
+		}
+	}
+	if v, ok := schema["items"]; ok {
+		// Before draft 2020-12, items may also be an array of schemas
+		if list, isList := v.([]interface{}); isList {
+			for i, item := range list {
+				problems = append(problems, checkJSONSchema(item, fmt.Sprintf("%s/items/%d", path, i))...)
+			}
+		} else {
+			problems = append(problems, checkJSONSchema(v, path+"/items")...)
+		}
+	}
+	return problems
+}
+
+// mermaidBlock is a mermaid code block and the line its content starts on
+type mermaidBlock struct {
+	text string
+	line int
+}
+
+// mermaidBlocks returns the ```mermaid code blocks of a markdown document
+func mermaidBlocks(content []byte) []mermaidBlock {
+	var blocks []mermaidBlock
+	var current *mermaidBlock
+	var lines []string
+	for i, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil {
+			if strings.HasPrefix(trimmed, "```") && strings.TrimSpace(strings.TrimLeft(trimmed, "`")) == "mermaid" {
+				current = &mermaidBlock{line: i + 2}
+				lines = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			current.text = strings.Join(lines, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if current != nil {
+		// An unterminated block is still checked
+		current.text = strings.Join(lines, "\n")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// mermaidDiagramTypes are the diagram declarations mermaid accepts
+var mermaidDiagramTypes = map[string]bool{
+	"flowchart": true, "graph": true, "sequenceDiagram": true, "classDiagram": true,
+	"classDiagram-v2": true, "stateDiagram": true, "stateDiagram-v2": true, "erDiagram": true,
+	"journey": true, "gantt": true, "pie": true, "quadrantChart": true, "requirementDiagram": true,
+	"gitGraph": true, "mindmap": true, "timeline": true, "sankey-beta": true, "xychart-beta": true,
+	"block-beta": true, "packet-beta": true, "architecture-beta": true, "kanban": true,
+	"C4Context": true, "C4Container": true, "C4Component": true, "C4Dynamic": true, "C4Deployment": true,
+}
+
+// sequenceBlockOpeners are sequence diagram statements closed by "end"
+var sequenceBlockOpeners = map[string]bool{
+	"loop": true, "alt": true, "opt": true, "par": true, "critical": true, "break": true, "rect": true, "box": true,
+}
+
+var (
+	quotedTextPattern      = regexp.MustCompile(`"[^"]*"`)
+	asymmetricShapePattern = regexp.MustCompile(`(\w)>[^\[\]]*\]`)
+)
+
+// checkMermaid performs basic syntax checks on a mermaid diagram: a known
+// diagram type, "end" closing every block, balanced braces around entity,
+// class and state bodies, and balanced node shape brackets in flowcharts.
+// firstLine is the file line the diagram starts on.
+func checkMermaid(text string, firstLine int) []SpecProblem {
+	lines := strings.Split(text, "\n")
+	var problems []SpecProblem
+
+	diagram := ""
+	declLine := 0
+	inFrontMatter := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if i == 0 && trimmed == "---" {
+			inFrontMatter = true
+			continue
+		}
+		if inFrontMatter {
+			inFrontMatter = trimmed != "---"
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "%%") {
+			continue
+		}
+		diagram = strings.Fields(trimmed)[0]
+		declLine = i
+		break
+	}
+	if diagram == "" {
+		return []SpecProblem{{Line: firstLine, Message: "empty mermaid diagram"}}
+	}
+	if !mermaidDiagramTypes[diagram] {
+		return []SpecProblem{{Line: firstLine + declLine, Message: fmt.Sprintf("unknown mermaid diagram type %q", diagram)}}
+	}
+
+	type open struct {
+		keyword string
+		line    int
+	}
+	var blocks []open
+	braces := 0
+	braceLine := 0
+	for i := declLine + 1; i < len(lines); i++ {
+		lineNum := firstLine + i
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "%%") {
+			continue
+		}
+		keyword := strings.Fields(trimmed)[0]
+
+		switch diagram {
+		case "flowchart", "graph":
+			switch {
+			case keyword == "subgraph":
+				blocks = append(blocks, open{keyword, lineNum})
+			case trimmed == "end":
+				if len(blocks) == 0 {
+					problems = append(problems, SpecProblem{Line: lineNum, Message: "end without a matching subgraph"})
+				} else {
+					blocks = blocks[:len(blocks)-1]
+				}
+			default:
+				if msg := unbalancedShapes(trimmed); msg != "" {
+					problems = append(problems, SpecProblem{Line: lineNum, Message: msg})
+				}
+			}
+		case "sequenceDiagram":
+			switch {
+			case sequenceBlockOpeners[keyword]:
+				blocks = append(blocks, open{keyword, lineNum})
+			case trimmed == "end":
+				if len(blocks) == 0 {
+					problems = append(problems, SpecProblem{Line: lineNum, Message: "end without a matching loop, alt, opt, par, critical, break, rect or box"})
+				} else {
+					blocks = blocks[:len(blocks)-1]
+				}
+			}
+		case "erDiagram", "classDiagram", "classDiagram-v2", "stateDiagram", "stateDiagram-v2":
+			if strings.HasSuffix(trimmed, "{") {
+				if braces == 0 {
+					braceLine = lineNum
+				}
+				braces++
+			} else if trimmed == "}" {
+				if braces == 0 {
+					problems = append(problems, SpecProblem{Line: lineNum, Message: "} without a matching {"})
+				} else {
+					braces--
+				}
+			}
+		}
+	}
+
+	for _, b := range blocks {
+		problems = append(problems, SpecProblem{Line: b.line, Message: fmt.Sprintf("%s is not closed with end", b.keyword)})
+	}
+	if braces > 0 {
+		problems = append(problems, SpecProblem{Line: braceLine, Message: "{ is not closed with }"})
+	}
+	return problems
+}
+
+// unbalancedShapes checks the brackets of flowchart node shapes on one line,
+// ignoring quoted labels and the asymmetric ">text]" shape
+func unbalancedShapes(line string) string {
+	line = quotedTextPattern.ReplaceAllString(line, `""`)
+	line = asymmetricShapePattern.ReplaceAllString(line, "$1")
+	pairs := map[rune]rune{')': '(', ']': '[', '}': '{'}
+	var stack []rune
+	for _, r := range line {
+		switch r {
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != pairs[r] {
+				return fmt.Sprintf("unexpected %q in node shape", r)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) > 0 {
+		return fmt.Sprintf("unclosed %q in node shape", stack[len(stack)-1])
+	}
+	return ""
+}
+
+// checkSpecsOnPlanning validates a feature's technical specs when it enters
+// implementation planning. Problems are written to out as warnings unless
+// validation.enforce_valid_specs is set.
+func checkSpecsOnPlanning(targetDir, featureDir, shortName, from, to string, force bool, out io.Writer) error {
+	if !enteringStep("Implementation Planning", from, to) {
+		return nil
+	}
+	config, err := LoadConfig(targetDir)
+	if err != nil {
+		return err
+	}
+	results, err := validateFeatureSpecs(featureDir, artifactDocuments(config))
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, result := range results {
+		for _, problem := range result.Problems {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.File, problem))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	if config.Validation.EnforceValidSpecs && !force {
+		return fmt.Errorf("feature %s cannot enter '%s' until its technical specs are valid:\n  %s",
+			shortName, to, strings.Join(failures, "\n  "))
+	}
+	fmt.Fprintf(out, "Warning: feature %s is entering '%s' with invalid technical specs:\n  %s\n",
+		shortName, to, strings.Join(failures, "\n  "))
+	return nil
+}
@@ -0,0 +1,176 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Technical spec validation", func() {
+	var (
+		tempDir    string
+		featureDir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-validate-specs-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		featureDir = filepath.Join(tempDir, ".spec", "001-user-auth")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	writeSpec := func(name, content string) {
+		Expect(os.WriteFile(filepath.Join(featureDir, name), []byte(content), 0644)).To(Succeed())
+	}
+
+	validate := func() map[string]spec.SpecValidation {
+		results, err := spec.ValidateTechSpecs(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		byFile := make(map[string]spec.SpecValidation)
+		for _, result := range results {
+			byFile[result.File] = result
+		}
+		return byFile
+	}
+
+	messages := func(result spec.SpecValidation) []string {
+		var out []string
+		for _, problem := range result.Problems {
+			out = append(out, problem.String())
+		}
+		return out
+	}
+
+	It("accepts every embedded spec template", func() {
+		for _, t := range spec.TechSpecTypes {
+			_, err := spec.AddTechSpec(tempDir, "user-auth", t.Name, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		results := validate()
+		Expect(results).To(HaveLen(4))
+		for _, result := range results {
+			Expect(result.Problems).To(BeEmpty(), result.File)
+		}
+		Expect(results["openapi.yaml"].Kind).To(Equal(spec.SpecKindOpenAPI))
+		Expect(results["schema.json"].Kind).To(Equal(spec.SpecKindJSONSchema))
+		Expect(results["erd.md"].Kind).To(Equal(spec.SpecKindMermaid))
+	})
+
+	It("reports YAML and JSON syntax errors with their line", func() {
+		writeSpec("api.yaml", "openapi: 3.0.3\ninfo:\n  title: x\n   version: 1\n")
+		writeSpec("model.json", "{\n  \"a\": 1,\n}\n")
+
+		results := validate()
+		Expect(results["api.yaml"].Problems).To(HaveLen(1))
+		Expect(results["api.yaml"].Problems[0].Line).To(Equal(4))
+		Expect(results["model.json"].Kind).To(Equal(spec.SpecKindJSON))
+		Expect(results["model.json"].Problems[0].Line).To(Equal(3))
+	})
+
+	It("checks OpenAPI structure and references", func() {
+		writeSpec("api.yaml", `openapi: 3.0.3
+info:
+  title: Users
+  version: 1.0
+paths:
+  users/{id}:
+    get:
+      operationId: getUser
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+    delete:
+      operationId: getUser
+`)
+		Expect(messages(validate()["api.yaml"])).To(ConsistOf(
+			ContainSubstring("info.version is required"),
+			ContainSubstring(`"users/{id}" must start with /`),
+			ContainSubstring(`path parameter "id" is not declared`),
+			ContainSubstring(`operationId "getUser" is also used`),
+			ContainSubstring("responses is required"),
+			ContainSubstring("$ref #/components/schemas/User does not resolve"),
+			ContainSubstring(`path parameter "id" is not declared`),
+		))
+
+		writeSpec("api.yaml", "swagger: \"2.0\"\n")
+		Expect(messages(validate()["api.yaml"])).To(ConsistOf(ContainSubstring("Swagger 2.0")))
+	})
+
+	It("checks JSON Schema keywords", func() {
+		writeSpec("user.schema.json", `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "objekt",
+  "required": ["id", "id"],
+  "properties": {
+    "id": {"type": "string", "minLength": -1},
+    "tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+  },
+  "anyOf": []
+}`)
+		Expect(messages(validate()["user.schema.json"])).To(ConsistOf(
+			`#: unknown type "objekt"`,
+			`#: required lists "id" twice`,
+			`#: anyOf must be a non-empty array of schemas`,
+			`#/properties/id: minLength must be a non-negative integer`,
+			`#/properties/tags/items: $ref #/$defs/tag does not resolve`,
+		))
+	})
+
+	It("checks mermaid diagrams in markdown and .mmd files", func() {
+		writeSpec("flows.md", "# Flows\n\n```mermaid\nsequenceDiagram\n    loop Every minute\n        A->>B: ping\n```\n\n```mermaid\nflowhcart TD\n```\n")
+		writeSpec("states.mmd", "flowchart LR\n  A[Start] --> B{Done?\n  C>Note] --> D((End))\n")
+		writeSpec("notes.md", "# Notes\n\nNo diagrams here.\n")
+
+		results := validate()
+		Expect(results).NotTo(HaveKey("notes.md"))
+		Expect(messages(results["flows.md"])).To(ConsistOf(
+			"line 5: loop is not closed with end",
+			`line 10: unknown mermaid diagram type "flowhcart"`,
+		))
+		Expect(messages(results["states.mmd"])).To(ConsistOf(`line 2: unclosed '{' in node shape`))
+	})
+
+	It("reports registered specs whose file is missing", func() {
+		_, err := spec.AddTechSpec(tempDir, "user-auth", "mermaid", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Remove(filepath.Join(featureDir, "diagram.md"))).To(Succeed())
+		Expect(messages(validate()["diagram.md"])).To(ConsistOf(ContainSubstring("file is missing")))
+	})
+
+	Describe("entering implementation planning", func() {
+		BeforeEach(func() {
+			writeSpec("api.yaml", "openapi: 3.0.3\n")
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Requirements Complete")).To(Succeed())
+		})
+
+		It("only warns by default", func() {
+			Expect(spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Planning")).To(Succeed())
+		})
+
+		It("is refused when validation.enforce_valid_specs is set unless forced", func() {
+			_, err := spec.SetConfigValue(tempDir, "validation.enforce-valid-specs", "true")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = spec.UpdateFeatureStatus(tempDir, "user-auth", "Implementation Planning")
+			Expect(err).To(MatchError(ContainSubstring("api.yaml: info object is required")))
+
+			Expect(spec.UpdateFeatureStatusWithOptions(tempDir, "user-auth", "Implementation Planning", spec.StatusUpdateOptions{Force: true})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,442 @@
+package spec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Coverage states reported by VerifyFeature
+const (
+	CoverageUncovered = "uncovered"
+	CoverageCovered   = "covered"
+	CoveragePassed    = "passed"
+	CoverageFailed    = "failed"
+)
+
+// maxVerifyFileSize skips large files, which are unlikely to be tests
+const maxVerifyFileSize = 1 << 20
+
+// verifySkipDirs are directories never scanned for test references
+var verifySkipDirs = map[string]bool{
+	".git": true, ".spec": true, ".claude": true, "node_modules": true, "vendor": true,
+}
+
+var (
+	// specMarkerPattern matches "spec: 007-user-auth AC-3, AC-4" in any
+	// comment style; without criteria it scopes the file to the feature
+	specMarkerPattern = regexp.MustCompile(`(?i)\bspec:\s*([A-Za-z0-9_-]+)((?:[\s,]+AC-\d+\b)*)`)
+	// ginkgoLabelPattern matches the arguments of a Ginkgo Label(...) call
+	ginkgoLabelPattern     = regexp.MustCompile(`\bLabel\(([^)]*)\)`)
+	quotedStringPattern    = regexp.MustCompile(`"([^"\\]*)"`)
+	acceptanceIDPattern    = regexp.MustCompile(`(?i)\bAC-\d+\b`)
+	acceptanceLabelPattern = regexp.MustCompile(`(?i)^AC-\d+$`)
+)
+
+// TestReference is a place in the codebase that references a criterion
+type TestReference struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// CriterionCoverage is an acceptance criterion with the tests referencing it
+type CriterionCoverage struct {
+	ID         string          `json:"id"`
+	Text       string          `json:"text"`
+	Status     string          `json:"status"`
+	References []TestReference `json:"references"`
+}
+
+// VerifyOptions controls VerifyFeature
+type VerifyOptions struct {
+	// RunTests runs TestCommand, or verify.test_command from the config
+	RunTests bool
+	// TestCommand overrides verify.test_command
+	TestCommand string
+}
+
+// TestRun is one execution of the test command
+type TestRun struct {
+	Command  string   `json:"command"`
+	Criteria []string `json:"criteria"`
+	Passed   bool     `json:"passed"`
+	Output   string   `json:"output,omitempty"`
+}
+
+// AmbiguousReference is a Ginkgo Label("AC-n") that does not name a
+// feature, for a criterion ID that several features have
+type AmbiguousReference struct {
+	ID string `json:"id"`
+	TestReference
+}
+
+// VerifyReport is the acceptance criteria coverage of a feature
+type VerifyReport struct {
+	Feature  string              `json:"feature"`
+	Criteria []CriterionCoverage `json:"criteria"`
+	Runs     []TestRun           `json:"runs,omitempty"`
+	// Skipped are the files and directories that could not be scanned
+	Skipped []string `json:"skipped,omitempty"`
+	// Ambiguous are unscoped labels that are not counted because other
+	// features have the same criterion
+	Ambiguous []AmbiguousReference `json:"ambiguous,omitempty"`
+}
+
+// Complete reports whether every criterion is covered and no test run failed
+func (r VerifyReport) Complete() bool {
+	for _, c := range r.Criteria {
+		if c.Status == CoverageUncovered || c.Status == CoverageFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptanceCriteria returns the bullets of the Acceptance Criteria section.
+// Bullets without an explicit AC-n ID are identified by their position, or
+// the next number not used by an explicit ID.
+func acceptanceCriteria(content []byte) []CriterionCoverage {
+	var requirements []Requirement
+	taken := make(map[string]bool)
+	for _, req := range parseRequirements(content) {
+		if prefix, _ := requirementSectionPrefix(req.Section); prefix != "AC" {
+			continue
+		}
+		requirements = append(requirements, req)
+		if req.ID != "" {
+			taken[req.ID] = true
+		}
+	}
+
+	var criteria []CriterionCoverage
+	next := 1
+	for i, req := range requirements {
+		id := req.ID
+		if id == "" {
+			if next < i+1 {
+				next = i + 1
+			}
+			for taken[fmt.Sprintf("AC-%d", next)] {
+				next++
+			}
+			id = fmt.Sprintf("AC-%d", next)
+			taken[id] = true
+		}
+		criteria = append(criteria, CriterionCoverage{ID: id, Text: req.Text, Status: CoverageUncovered})
+	}
+	return criteria
+}
+
+// matchesFeature reports whether a reference names the feature by its
+// directory name or short name
+func matchesFeature(name string, feature Feature) bool {
+	return strings.EqualFold(name, feature.Name) || strings.EqualFold(name, feature.ShortName) ||
+		strings.EqualFold(name, "spec:"+feature.Name) || strings.EqualFold(name, "spec:"+feature.ShortName)
+}
+
+// matchesAnyFeature reports whether a reference names one of the features
+func matchesAnyFeature(name string, features []Feature) bool {
+	for _, feature := range features {
+		if matchesFeature(name, feature) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanTestReferences finds references to a feature's acceptance criteria in
+// one file. "spec: <feature> AC-n" markers always count. Ginkgo Label("AC-n")
+// calls count when the same Label names the feature or the file carries a
+// "spec: <feature>" marker, unless they name one of the other features.
+// Labels that name no feature, in a file not scoped to any feature, are
+// returned separately as unscoped, since they do not say which feature they
+// belong to.
+func scanTestReferences(content []byte, feature Feature, others []Feature) (refs, unscoped map[string][]int, err error) {
+	refs = make(map[string][]int)
+	unscoped = make(map[string][]int)
+	type label struct {
+		line int
+		ids  []string
+	}
+	var labels []label
+	scoped, scopedToOther := false, false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxVerifyFileSize)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		for _, m := range specMarkerPattern.FindAllStringSubmatch(line, -1) {
+			if !matchesFeature(m[1], feature) {
+				scopedToOther = scopedToOther || matchesAnyFeature(m[1], others)
+				continue
+			}
+			scoped = true
+			for _, id := range acceptanceIDPattern.FindAllString(m[2], -1) {
+				refs[strings.ToUpper(id)] = append(refs[strings.ToUpper(id)], lineNum)
+			}
+		}
+
+		for _, m := range ginkgoLabelPattern.FindAllStringSubmatch(line, -1) {
+			var ids []string
+			namesFeature, namesOther := false, false
+			for _, s := range quotedStringPattern.FindAllStringSubmatch(m[1], -1) {
+				switch {
+				case acceptanceLabelPattern.MatchString(s[1]):
+					ids = append(ids, strings.ToUpper(s[1]))
+				case matchesFeature(s[1], feature):
+					namesFeature = true
+				case matchesAnyFeature(s[1], others):
+					namesOther = true
+				}
+			}
+			if len(ids) == 0 || (namesOther && !namesFeature) {
+				continue
+			}
+			if namesFeature {
+				for _, id := range ids {
+					refs[id] = append(refs[id], lineNum)
+				}
+			} else {
+				labels = append(labels, label{lineNum, ids})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	target := unscoped
+	switch {
+	case scoped:
+		target = refs
+	case scopedToOther:
+		labels = nil
+	}
+	for _, l := range labels {
+		for _, id := range l.ids {
+			target[id] = append(target[id], l.line)
+		}
+	}
+	for id := range refs {
+		sort.Ints(refs[id])
+	}
+	return refs, unscoped, nil
+}
+
+// findTestReferences walks the project for references to a feature's
+// acceptance criteria, skipping .spec/, VCS and dependency directories,
+// hidden directories and binary or large files. References are returned with
+// the unscoped labels found (see scanTestReferences). Directories and files
+// that cannot be read are skipped and returned.
+func findTestReferences(targetDir string, feature Feature, others []Feature) (found, unscoped map[string][]TestReference, skipped []string, err error) {
+	found = make(map[string][]TestReference)
+	unscoped = make(map[string][]TestReference)
+	relPath := func(path string) string {
+		rel, err := filepath.Rel(targetDir, path)
+		if err != nil {
+			return path
+		}
+		return filepath.ToSlash(rel)
+	}
+	err = filepath.WalkDir(targetDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == targetDir {
+				return err
+			}
+			skipped = append(skipped, relPath(path))
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != targetDir && (verifySkipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxVerifyFileSize {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			skipped = append(skipped, relPath(path))
+			return nil
+		}
+		head := content
+		if len(head) > 8000 {
+			head = head[:8000]
+		}
+		if bytes.IndexByte(head, 0) >= 0 {
+			return nil
+		}
+
+		refs, labels, err := scanTestReferences(content, feature, others)
+		if err != nil {
+			skipped = append(skipped, relPath(path))
+			return nil
+		}
+		for id, lines := range refs {
+			for _, line := range lines {
+				found[id] = append(found[id], TestReference{File: relPath(path), Line: line})
+			}
+		}
+		for id, lines := range labels {
+			for _, line := range lines {
+				unscoped[id] = append(unscoped[id], TestReference{File: relPath(path), Line: line})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to scan project: %w", err)
+	}
+	return found, unscoped, skipped, nil
+}
+
+// VerifyFeature reports which acceptance criteria of a feature are referenced
+// by tests. With RunTests, the configured test command is run and its result
+// is mapped onto the covered criteria: a command containing {ac} runs once per
+// criterion with {ac} replaced by its ID, otherwise it runs once for all of
+// them. {feature} is replaced by the feature's directory name.
+func VerifyFeature(targetDir, shortName string, opts VerifyOptions) (VerifyReport, error) {
+	var report VerifyReport
+	featureDir, err := resolveFeatureDir(targetDir, shortName)
+	if err != nil {
+		return report, err
+	}
+	content, err := readRequirements(featureDir)
+	if err != nil {
+		return report, err
+	}
+
+	featureName := filepath.Base(featureDir)
+	_, short, _ := parseFeatureDirName(featureName)
+	feature := Feature{Name: featureName, ShortName: short}
+	report.Feature = featureName
+	report.Criteria = acceptanceCriteria(content)
+	if len(report.Criteria) == 0 {
+		return report, nil
+	}
+
+	// Unscoped labels count for a criterion only this feature has
+	features, err := ListFeatures(targetDir)
+	if err != nil {
+		return report, err
+	}
+	var others []Feature
+	shared := make(map[string]bool)
+	for _, other := range features {
+		if other.Name == featureName {
+			continue
+		}
+		others = append(others, other)
+		if otherContent, err := readRequirements(other.Dir); err == nil {
+			for _, c := range acceptanceCriteria(otherContent) {
+				shared[c.ID] = true
+			}
+		}
+	}
+
+	found, unscoped, skipped, err := findTestReferences(targetDir, feature, others)
+	if err != nil {
+		return report, err
+	}
+	report.Skipped = skipped
+	var covered []string
+	for i := range report.Criteria {
+		c := &report.Criteria[i]
+		c.References = found[c.ID]
+		if shared[c.ID] {
+			for _, ref := range unscoped[c.ID] {
+				report.Ambiguous = append(report.Ambiguous, AmbiguousReference{ID: c.ID, TestReference: ref})
+			}
+		} else {
+			c.References = append(c.References, unscoped[c.ID]...)
+			sort.SliceStable(c.References, func(a, b int) bool {
+				if c.References[a].File != c.References[b].File {
+					return c.References[a].File < c.References[b].File
+				}
+				return c.References[a].Line < c.References[b].Line
+			})
+		}
+		if len(c.References) > 0 {
+			c.Status = CoverageCovered
+			covered = append(covered, c.ID)
+		}
+	}
+
+	if !opts.RunTests || len(covered) == 0 {
+		return report, nil
+	}
+	command := opts.TestCommand
+	if command == "" {
+		config, err := LoadConfig(targetDir)
+		if err != nil {
+			return report, err
+		}
+		command = config.Verify.TestCommand
+	}
+	if strings.TrimSpace(command) == "" {
+		return report, fmt.Errorf("no test command configured; set verify.test_command or use --test-command")
+	}
+
+	results := make(map[string]bool)
+	command = strings.ReplaceAll(command, "{feature}", featureName)
+	if strings.Contains(command, "{ac}") {
+		for _, id := range covered {
+			run := runTestCommand(targetDir, strings.ReplaceAll(command, "{ac}", id), []string{id})
+			results[id] = run.Passed
+			report.Runs = append(report.Runs, run)
+		}
+	} else {
+		run := runTestCommand(targetDir, command, covered)
+		for _, id := range covered {
+			results[id] = run.Passed
+		}
+		report.Runs = append(report.Runs, run)
+	}
+
+	for i := range report.Criteria {
+		c := &report.Criteria[i]
+		if passed, ok := results[c.ID]; ok {
+			c.Status = CoverageFailed
+			if passed {
+				c.Status = CoveragePassed
+			}
+		}
+	}
+	return report, nil
+}
+
+// runTestCommand runs a shell command in the project directory, keeping the
+// end of the output of failed runs
+func runTestCommand(targetDir, command string, criteria []string) TestRun {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = targetDir
+	output, err := cmd.CombinedOutput()
+	run := TestRun{Command: command, Criteria: criteria, Passed: err == nil}
+	if !run.Passed {
+		lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+		if len(lines) > 20 {
+			lines = lines[len(lines)-20:]
+		}
+		run.Output = strings.Join(lines, "\n")
+	}
+	return run
+}
@@ -0,0 +1,172 @@
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+var _ = Describe("Acceptance criteria verification", func() {
+	var tempDir string
+
+	const requirements = `# Requirements Specification: Password Reset
+
+## Functional Requirements
+- Users can request a reset link
+
+## Acceptance Criteria
+- A reset link expires after 24 hours
+- AC-5: Locked users cannot sign in
+- Reset emails are rate limited
+`
+
+	writeFile := func(rel, content string) {
+		path := filepath.Join(tempDir, rel)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-verify-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "reset")
+		Expect(err).NotTo(HaveOccurred())
+		writeFile(".spec/001-reset/requirements.md", requirements)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("identifies criteria by explicit ID or position", func() {
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Feature).To(Equal("001-reset"))
+		Expect(report.Criteria).To(HaveLen(3))
+		Expect(report.Criteria[0].ID).To(Equal("AC-1"))
+		Expect(report.Criteria[1].ID).To(Equal("AC-5"))
+		Expect(report.Criteria[1].Text).To(Equal("Locked users cannot sign in"))
+		Expect(report.Criteria[2].ID).To(Equal("AC-3"))
+		Expect(report.Complete()).To(BeFalse())
+	})
+
+	It("does not reuse explicit IDs for unnumbered criteria", func() {
+		writeFile(".spec/001-reset/requirements.md", "## Acceptance Criteria\n- Expires\n- AC-1: Locks\n- AC-3: Limits\n- Notifies\n- Logs\n")
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var ids []string
+		for _, c := range report.Criteria {
+			ids = append(ids, c.ID)
+		}
+		Expect(ids).To(Equal([]string{"AC-2", "AC-1", "AC-3", "AC-4", "AC-5"}))
+	})
+
+	It("skips directories that cannot be read", func() {
+		if os.Geteuid() == 0 {
+			Skip("directory permissions do not apply to root")
+		}
+		writeFile("a_test.go", "// spec: 001-reset AC-1\n")
+		writeFile("locked/b_test.go", "// spec: 001-reset AC-5\n")
+		Expect(os.Chmod(filepath.Join(tempDir, "locked"), 0)).To(Succeed())
+		DeferCleanup(os.Chmod, filepath.Join(tempDir, "locked"), os.FileMode(0755))
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Criteria[0].Status).To(Equal(spec.CoverageCovered))
+		Expect(report.Skipped).To(Equal([]string{"locked"}))
+	})
+
+	It("finds spec comments naming the feature", func() {
+		writeFile("auth/reset_test.go", "package auth\n\n// spec: 001-reset AC-1, AC-5\nfunc TestReset() {}\n")
+		writeFile("web/reset.test.js", "# spec: reset ac-3\n")
+		writeFile("other/other_test.go", "// spec: 002-other AC-1\n")
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Criteria[0].Status).To(Equal(spec.CoverageCovered))
+		Expect(report.Criteria[0].References).To(Equal([]spec.TestReference{{File: "auth/reset_test.go", Line: 3}}))
+		Expect(report.Criteria[1].Status).To(Equal(spec.CoverageCovered))
+		Expect(report.Criteria[2].References).To(Equal([]spec.TestReference{{File: "web/reset.test.js", Line: 1}}))
+		Expect(report.Complete()).To(BeTrue())
+	})
+
+	It("counts Ginkgo labels scoped to the feature", func() {
+		writeFile("a_test.go", `It("expires links", Label("reset", "AC-1"), func() {})`+"\n")
+		writeFile("b_test.go", "// spec: 001-reset\n"+`It("locks", Label("AC-5"), func() {})`+"\n")
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Criteria[0].References).To(Equal([]spec.TestReference{{File: "a_test.go", Line: 1}}))
+		Expect(report.Criteria[1].References).To(Equal([]spec.TestReference{{File: "b_test.go", Line: 2}}))
+		Expect(report.Criteria[2].Status).To(Equal(spec.CoverageUncovered))
+	})
+
+	It("counts unscoped labels only for criteria no other feature has", func() {
+		_, err := spec.CreateNewRequirements(tempDir, "billing")
+		Expect(err).NotTo(HaveOccurred())
+		writeFile(".spec/002-billing/requirements.md", "## Acceptance Criteria\n- AC-3: Invoices are sent monthly\n")
+		writeFile("a_test.go", `It("expires links", Label("AC-1"), func() {})`+"\n")
+		writeFile("c_test.go", `It("rate limits", Label("slow", "AC-3"), func() {})`+"\n")
+		writeFile("d_test.go", "// spec: billing\n"+`It("sends invoices", Label("AC-1"), func() {})`+"\n")
+		writeFile("e_test.go", `It("charges", Label("billing", "AC-5"), func() {})`+"\n")
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Criteria[0].References).To(Equal([]spec.TestReference{{File: "a_test.go", Line: 1}}))
+		Expect(report.Criteria[1].Status).To(Equal(spec.CoverageUncovered))
+		Expect(report.Criteria[2].Status).To(Equal(spec.CoverageUncovered))
+		Expect(report.Ambiguous).To(Equal([]spec.AmbiguousReference{
+			{ID: "AC-3", TestReference: spec.TestReference{File: "c_test.go", Line: 1}},
+		}))
+	})
+
+	It("does not scan the .spec directory or dependencies", func() {
+		writeFile(".spec/001-reset/notes.md", "// spec: 001-reset AC-1\n")
+		writeFile("node_modules/x/test.js", "// spec: 001-reset AC-1\n")
+		writeFile("vendor/x/x_test.go", "// spec: 001-reset AC-1\n")
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Criteria[0].Status).To(Equal(spec.CoverageUncovered))
+	})
+
+	It("maps test command results onto covered criteria", func() {
+		writeFile("reset_test.go", "// spec: 001-reset AC-1 AC-5\n")
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{
+			RunTests:    true,
+			TestCommand: `test "{ac}" = AC-1`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Runs).To(HaveLen(2))
+		Expect(report.Criteria[0].Status).To(Equal(spec.CoveragePassed))
+		Expect(report.Criteria[1].Status).To(Equal(spec.CoverageFailed))
+		Expect(report.Criteria[2].Status).To(Equal(spec.CoverageUncovered))
+		Expect(report.Runs[1].Command).To(Equal(`test "AC-5" = AC-1`))
+	})
+
+	It("uses verify.test_command and runs it once without {ac}", func() {
+		writeFile("reset_test.go", "// spec: 001-reset AC-1\n")
+		_, err := spec.SetConfigValue(tempDir, "verify.test_command", `test "{feature}" = 001-reset`)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{RunTests: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Runs).To(HaveLen(1))
+		Expect(report.Runs[0].Criteria).To(Equal([]string{"AC-1"}))
+		Expect(report.Criteria[0].Status).To(Equal(spec.CoveragePassed))
+	})
+
+	It("requires a test command to run tests", func() {
+		writeFile("reset_test.go", "// spec: 001-reset AC-1\n")
+		_, err := spec.VerifyFeature(tempDir, "reset", spec.VerifyOptions{RunTests: true})
+		Expect(err).To(MatchError(ContainSubstring("no test command configured")))
+	})
+})
@@ -0,0 +1,85 @@
+package tui
+
+import "unicode/utf8"
+
+// Key identifies a key press the terminal UI reacts to
+type Key int
+
+// Recognized keys. Printable characters are reported as KeyRune.
+const (
+	KeyRune Key = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyTab
+	KeyPageUp
+	KeyPageDown
+	KeyInterrupt
+)
+
+// KeyEvent is a single decoded key press
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+}
+
+// escapeSequences maps the terminal escape sequences for navigation keys
+var escapeSequences = map[string]Key{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1bOC":  KeyRight,
+	"\x1bOD":  KeyLeft,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+}
+
+// DecodeKeys splits raw terminal input into key events. Unrecognized escape
+// sequences are dropped.
+func DecodeKeys(input []byte) []KeyEvent {
+	var events []KeyEvent
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b:
+			if len(input) == 1 || (input[1] != '[' && input[1] != 'O') {
+				events = append(events, KeyEvent{Key: KeyEscape})
+				input = input[1:]
+				continue
+			}
+			// CSI sequences end with a byte in the range 0x40-0x7e
+			end := 2
+			for end < len(input) && (input[end] < 0x40 || input[end] > 0x7e) {
+				end++
+			}
+			if end < len(input) {
+				end++
+			}
+			if key, ok := escapeSequences[string(input[:end])]; ok {
+				events = append(events, KeyEvent{Key: key})
+			}
+			input = input[end:]
+		case b == '\r' || b == '\n':
+			events = append(events, KeyEvent{Key: KeyEnter})
+			input = input[1:]
+		case b == '\t':
+			events = append(events, KeyEvent{Key: KeyTab})
+			input = input[1:]
+		case b == 0x03:
+			events = append(events, KeyEvent{Key: KeyInterrupt})
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r >= 0x20 && r != 0x7f {
+				events = append(events, KeyEvent{Key: KeyRune, Rune: r})
+			}
+			input = input[size:]
+		}
+	}
+	return events
+}
@@ -0,0 +1,451 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+// view is a screen of the terminal UI
+type view int
+
+const (
+	viewList view = iota
+	viewFeature
+	viewTasks
+	viewStatus
+)
+
+// Model holds the state of the terminal UI. It is driven by key events and
+// rendered to a string, keeping terminal handling out of the UI logic.
+type Model struct {
+	targetDir string
+	width     int
+	height    int
+
+	view    view
+	rows    []featureRow
+	cursor  int
+	message string
+	quit    bool
+
+	artifacts []spec.Artifact
+	artifact  int
+	lines     []string
+	scroll    int
+
+	tasks      []spec.PlanTask
+	taskCursor int
+
+	statusCursor int
+	statusReturn view
+
+	// updateStatus changes a feature's status and returns any warnings printed
+	updateStatus func(targetDir, shortName, status string) (string, error)
+}
+
+// NewModel loads the features of the project in targetDir
+func NewModel(targetDir string) (*Model, error) {
+	m := &Model{
+		targetDir:    targetDir,
+		width:        80,
+		height:       24,
+		updateStatus: updateStatusQuietly,
+	}
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SetSize sets the terminal dimensions used for rendering
+func (m *Model) SetSize(width, height int) {
+	if width > 0 && height > 0 {
+		m.width, m.height = width, height
+	}
+}
+
+// Done reports whether the user has quit
+func (m *Model) Done() bool {
+	return m.quit
+}
+
+// selected returns the feature under the cursor
+func (m *Model) selected() (spec.Feature, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return spec.Feature{}, false
+	}
+	return m.rows[m.cursor].Feature, true
+}
+
+// reload re-reads the feature list, keeping the cursor on the same feature
+func (m *Model) reload() error {
+	current, _ := m.selected()
+	rows, err := loadFeatureRows(m.targetDir)
+	if err != nil {
+		return err
+	}
+	m.rows = rows
+	m.cursor = 0
+	for i, row := range rows {
+		if row.Feature.ShortName == current.ShortName {
+			m.cursor = i
+		}
+	}
+	return nil
+}
+
+// contentHeight is the number of lines available between header and footer
+func (m *Model) contentHeight() int {
+	if h := m.height - 5; h > 0 {
+		return h
+	}
+	return 1
+}
+
+// Update applies a key press to the model
+func (m *Model) Update(ev KeyEvent) {
+	if ev.Key == KeyInterrupt {
+		m.quit = true
+		return
+	}
+	m.message = ""
+
+	switch m.view {
+	case viewList:
+		m.updateList(ev)
+	case viewFeature:
+		m.updateFeature(ev)
+	case viewTasks:
+		m.updateTasks(ev)
+	case viewStatus:
+		m.updateStatusPicker(ev)
+	}
+}
+
+// isKey reports whether the event is the given key or one of the given runes
+func isKey(ev KeyEvent, key Key, runes string) bool {
+	if ev.Key == key {
+		return true
+	}
+	return ev.Key == KeyRune && strings.ContainsRune(runes, ev.Rune)
+}
+
+func (m *Model) updateList(ev KeyEvent) {
+	switch {
+	case isKey(ev, KeyUp, "k"):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case isKey(ev, KeyDown, "j"):
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case isKey(ev, KeyEnter, "l") || ev.Key == KeyRight:
+		m.openFeature()
+	case isKey(ev, KeyEscape, "q"):
+		m.quit = true
+	case isKey(ev, -1, "s"):
+		m.openStatusPicker(viewList)
+	case isKey(ev, -1, "r"):
+		if err := m.reload(); err != nil {
+			m.message = "Error: " + err.Error()
+		}
+	}
+}
+
+// openFeature shows the artifacts of the selected feature
+func (m *Model) openFeature() {
+	feature, ok := m.selected()
+	if !ok {
+		return
+	}
+	artifacts, err := spec.ListArtifacts(m.targetDir, feature.ShortName)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return
+	}
+	m.artifacts = artifacts
+	m.artifact = 0
+	m.view = viewFeature
+	m.loadArtifact()
+}
+
+// loadArtifact reads the current artifact into display lines
+func (m *Model) loadArtifact() {
+	m.lines, m.scroll = nil, 0
+	if len(m.artifacts) == 0 {
+		m.lines = []string{"No artifacts."}
+		return
+	}
+	feature, _ := m.selected()
+	content, err := spec.ReadArtifact(m.targetDir, feature.ShortName, m.artifacts[m.artifact].Name)
+	if err != nil {
+		m.lines = []string{"Error: " + err.Error()}
+		return
+	}
+	text := strings.ReplaceAll(strings.ReplaceAll(string(content), "\r", ""), "\t", "    ")
+	m.lines = strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+func (m *Model) updateFeature(ev KeyEvent) {
+	maxScroll := len(m.lines) - m.contentHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	switch {
+	case isKey(ev, KeyUp, "k"):
+		m.scroll--
+	case isKey(ev, KeyDown, "j"):
+		m.scroll++
+	case ev.Key == KeyPageUp:
+		m.scroll -= m.contentHeight()
+	case ev.Key == KeyPageDown || isKey(ev, -1, " "):
+		m.scroll += m.contentHeight()
+	case isKey(ev, KeyLeft, "h"):
+		if len(m.artifacts) > 0 {
+			m.artifact = (m.artifact + len(m.artifacts) - 1) % len(m.artifacts)
+			m.loadArtifact()
+		}
+	case isKey(ev, KeyRight, "l") || ev.Key == KeyTab:
+		if len(m.artifacts) > 0 {
+			m.artifact = (m.artifact + 1) % len(m.artifacts)
+			m.loadArtifact()
+		}
+	case isKey(ev, -1, "t"):
+		m.openTasks()
+	case isKey(ev, -1, "s"):
+		m.openStatusPicker(viewFeature)
+	case isKey(ev, KeyEscape, "q"):
+		m.view = viewList
+	}
+
+	if m.scroll > maxScroll {
+		m.scroll = maxScroll
+	}
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+}
+
+// openTasks shows the implementation plan checkboxes of the selected feature
+func (m *Model) openTasks() {
+	feature, _ := m.selected()
+	tasks, err := spec.ListPlanTasks(m.targetDir, feature.ShortName)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return
+	}
+	if len(tasks) == 0 {
+		m.message = "No implementation plan tasks found"
+		return
+	}
+	m.tasks = tasks
+	if m.taskCursor >= len(tasks) {
+		m.taskCursor = 0
+	}
+	m.view = viewTasks
+}
+
+func (m *Model) updateTasks(ev KeyEvent) {
+	switch {
+	case isKey(ev, KeyUp, "k"):
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+	case isKey(ev, KeyDown, "j"):
+		if m.taskCursor < len(m.tasks)-1 {
+			m.taskCursor++
+		}
+	case isKey(ev, KeyEnter, " x"):
+		feature, _ := m.selected()
+		task := m.tasks[m.taskCursor]
+		if _, err := spec.SetPlanTaskDone(m.targetDir, feature.ShortName, task.Index, !task.Done); err != nil {
+			m.message = "Error: " + err.Error()
+			return
+		}
+		m.tasks[m.taskCursor].Done = !task.Done
+		m.rows[m.cursor].Tasks = spec.PlanProgress(m.tasks)
+		if len(m.artifacts) > 0 && m.artifacts[m.artifact].Kind == spec.ArtifactPlan {
+			m.loadArtifact()
+		}
+	case isKey(ev, KeyEscape, "q"):
+		m.view = viewFeature
+	}
+}
+
+// openStatusPicker lists the workflow steps with the current one selected
+func (m *Model) openStatusPicker(from view) {
+	feature, ok := m.selected()
+	if !ok {
+		return
+	}
+	m.statusCursor = 0
+	if i := spec.StepIndex(feature.Status); i >= 0 {
+		m.statusCursor = i
+	}
+	m.statusReturn = from
+	m.view = viewStatus
+}
+
+func (m *Model) updateStatusPicker(ev KeyEvent) {
+	switch {
+	case isKey(ev, KeyUp, "k"):
+		if m.statusCursor > 0 {
+			m.statusCursor--
+		}
+	case isKey(ev, KeyDown, "j"):
+		if m.statusCursor < len(spec.WorkflowSteps)-1 {
+			m.statusCursor++
+		}
+	case ev.Key == KeyEnter:
+		feature, _ := m.selected()
+		status := spec.WorkflowSteps[m.statusCursor]
+		warnings, err := m.updateStatus(m.targetDir, feature.ShortName, status)
+		m.view = m.statusReturn
+		if err != nil {
+			m.message = "Error: " + firstLine(err.Error())
+			return
+		}
+		m.message = fmt.Sprintf("Status of %s updated to '%s'", feature.ShortName, status)
+		if warnings != "" {
+			m.message += " - " + firstLine(warnings)
+		}
+		if err := m.reload(); err != nil {
+			m.message = "Error: " + err.Error()
+		}
+	case isKey(ev, KeyEscape, "q"):
+		m.view = m.statusReturn
+	}
+}
+
+// firstLine returns the first line of a possibly multi-line message
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i]) + " ..."
+	}
+	return s
+}
+
+// View renders the current screen as newline separated lines
+func (m *Model) View() string {
+	var header, body []string
+	var help string
+
+	switch m.view {
+	case viewList:
+		table := formatTable(m.rows)
+		header = []string{"specware - features", "  " + table[0]}
+		help = "j/k move  enter open  s status  r reload  q quit"
+		for i, line := range table[1:] {
+			marker := "  "
+			if i == m.cursor {
+				marker = "> "
+			}
+			body = append(body, marker+line)
+		}
+		body = window(body, m.cursor, m.contentHeight())
+	case viewFeature:
+		header = []string{m.featureTitle(), m.artifactTabs()}
+		help = "h/l artifact  j/k scroll  t tasks  s status  esc back"
+		end := m.scroll + m.contentHeight()
+		if end > len(m.lines) {
+			end = len(m.lines)
+		}
+		body = m.lines[m.scroll:end]
+	case viewTasks:
+		progress := m.rows[m.cursor].Tasks
+		header = []string{m.featureTitle(), fmt.Sprintf("Implementation plan tasks (%d/%d done)", progress.Done, progress.Total)}
+		help = "j/k move  space toggle  esc back"
+		section := ""
+		selectedLine := 0
+		for i, task := range m.tasks {
+			if task.Section != section {
+				section = task.Section
+				body = append(body, "", section)
+			}
+			marker := "  "
+			if i == m.taskCursor {
+				marker = "> "
+				selectedLine = len(body)
+			}
+			check := " "
+			if task.Done {
+				check = "x"
+			}
+			body = append(body, fmt.Sprintf("%s[%s] %s", marker, check, task.Text))
+		}
+		body = window(body, selectedLine, m.contentHeight())
+	case viewStatus:
+		header = []string{m.featureTitle(), "Change status to:"}
+		help = "j/k move  enter apply  esc cancel"
+		for i, step := range spec.WorkflowSteps {
+			marker := "  "
+			if i == m.statusCursor {
+				marker = "> "
+			}
+			body = append(body, marker+step)
+		}
+		body = window(body, m.statusCursor, m.contentHeight())
+	}
+
+	lines := append(header, "")
+	lines = append(lines, body...)
+	for len(lines) < m.height-2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.message, help)
+	for i, line := range lines {
+		lines[i] = truncate(line, m.width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// featureTitle describes the selected feature and its status
+func (m *Model) featureTitle() string {
+	feature, _ := m.selected()
+	status := feature.Status
+	if status == "" {
+		status = "no status"
+	}
+	return fmt.Sprintf("%s [%s]", feature.Name, status)
+}
+
+// artifactTabs lists the feature's artifacts, bracketing the open one
+func (m *Model) artifactTabs() string {
+	var tabs []string
+	for i, artifact := range m.artifacts {
+		if i == m.artifact {
+			tabs = append(tabs, "["+artifact.Name+"]")
+		} else {
+			tabs = append(tabs, " "+artifact.Name+" ")
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+// window returns at most height lines of body, scrolled so that line
+// selected is visible
+func window(body []string, selected, height int) []string {
+	if len(body) <= height {
+		return body
+	}
+	start := 0
+	if selected >= height {
+		start = selected - height + 1
+	}
+	return body[start : start+height]
+}
+
+// truncate shortens a line to fit the terminal width
+func truncate(line string, width int) string {
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:width])
+}
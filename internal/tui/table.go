@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+// featureRow is a feature with the implementation plan progress shown in tables
+type featureRow struct {
+	Feature spec.Feature
+	Tasks   spec.TaskProgress
+}
+
+// loadFeatureRows lists the features of a project with their task progress
+func loadFeatureRows(targetDir string) ([]featureRow, error) {
+	features, err := spec.ListFeatures(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]featureRow, 0, len(features))
+	for _, feature := range features {
+		tasks, err := spec.ListPlanTasks(targetDir, feature.ShortName)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, featureRow{Feature: feature, Tasks: spec.PlanProgress(tasks)})
+	}
+	return rows, nil
+}
+
+// formatTable aligns the feature rows into columns, returning the header
+// followed by one line per feature
+func formatTable(rows []featureRow) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NUM\tFEATURE\tCURRENT STEP\tTASKS")
+	for _, row := range rows {
+		status := row.Feature.Status
+		if status == "" {
+			status = "-"
+		}
+		tasks := "-"
+		if row.Tasks.Total > 0 {
+			tasks = fmt.Sprintf("%d/%d", row.Tasks.Done, row.Tasks.Total)
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", row.Feature.Number, row.Feature.ShortName, status, tasks)
+	}
+	w.Flush()
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+// WriteTable writes the features of a project as a plain text table. It is
+// used in place of the interactive UI when output is not a terminal.
+func WriteTable(w io.Writer, targetDir string) error {
+	rows, err := loadFeatureRows(targetDir)
+	if err != nil {
+		return err
+	}
+	for _, line := range formatTable(rows) {
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
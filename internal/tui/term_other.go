@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package tui
+
+import "errors"
+
+// IsTerminal always reports false on platforms without termios support, so
+// the plain text table is used instead of the interactive UI
+func IsTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, errors.New("interactive terminal UI is not supported on this platform")
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errors.New("interactive terminal UI is not supported on this platform")
+}
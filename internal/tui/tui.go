@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tiwillia/specware/internal/spec"
+)
+
+// Terminal control sequences used to draw the UI on the alternate screen
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+// Run starts the interactive UI for the project in targetDir, reading keys
+// from in and drawing to out until the user quits. Both must be terminals.
+func Run(targetDir string, in, out *os.File) error {
+	model, err := NewModel(targetDir)
+	if err != nil {
+		return err
+	}
+
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer restore()
+
+	fmt.Fprint(out, enterScreen)
+	defer fmt.Fprint(out, leaveScreen)
+
+	buf := make([]byte, 64)
+	for !model.Done() {
+		if width, height, err := terminalSize(int(out.Fd())); err == nil {
+			model.SetSize(width, height)
+		}
+		// Raw mode disables output post-processing, so lines need explicit carriage returns
+		fmt.Fprint(out, clearScreen+strings.ReplaceAll(model.View(), "\n", "\r\n"))
+
+		n, err := in.Read(buf)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		for _, ev := range DecodeKeys(buf[:n]) {
+			model.Update(ev)
+		}
+	}
+	return nil
+}
+
+// updateStatusQuietly updates a feature's status, collecting the warnings
+// UpdateFeatureStatus would print so they do not corrupt the screen
+func updateStatusQuietly(targetDir, shortName, status string) (string, error) {
+	var output bytes.Buffer
+	err := spec.UpdateFeatureStatusWithOptions(targetDir, shortName, status, spec.StatusUpdateOptions{Output: &output})
+	return output.String(), err
+}
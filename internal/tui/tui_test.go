@@ -0,0 +1,116 @@
+package tui_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/tiwillia/specware/internal/spec"
+	"github.com/tiwillia/specware/internal/tui"
+)
+
+var _ = Describe("TUI", func() {
+	var (
+		tempDir  string
+		planPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-tui-test-*")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.InitProject(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewRequirements(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = spec.CreateNewImplementationPlan(tempDir, "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+
+		planPath = filepath.Join(tempDir, ".spec", "001-user-auth", "implementation-plan.md")
+		Expect(os.WriteFile(planPath, []byte("# Plan\n\n- [ ] Step 1: Schema\n- [ ] Step 2: Handlers\n"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	press := func(m *tui.Model, input string) {
+		for _, ev := range tui.DecodeKeys([]byte(input)) {
+			m.Update(ev)
+		}
+	}
+
+	Describe("DecodeKeys", func() {
+		It("decodes navigation sequences and printable keys", func() {
+			Expect(tui.DecodeKeys([]byte("\x1b[Aj\r\x1b\x03"))).To(Equal([]tui.KeyEvent{
+				{Key: tui.KeyUp},
+				{Key: tui.KeyRune, Rune: 'j'},
+				{Key: tui.KeyEnter},
+				{Key: tui.KeyEscape},
+				{Key: tui.KeyInterrupt},
+			}))
+		})
+
+		It("drops unknown escape sequences", func() {
+			Expect(tui.DecodeKeys([]byte("\x1b[15~q"))).To(Equal([]tui.KeyEvent{{Key: tui.KeyRune, Rune: 'q'}}))
+		})
+	})
+
+	Describe("WriteTable", func() {
+		It("lists features with their current step and task progress", func() {
+			var buf bytes.Buffer
+			Expect(tui.WriteTable(&buf, tempDir)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("NUM  FEATURE"))
+			Expect(buf.String()).To(MatchRegexp(`001\s+user-auth\s+requirements-gathering\s+0/2\n`))
+		})
+	})
+
+	Describe("Model", func() {
+		var model *tui.Model
+
+		BeforeEach(func() {
+			var err error
+			model, err = tui.NewModel(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			model.SetSize(100, 30)
+		})
+
+		It("opens a feature and switches between its artifacts", func() {
+			// Features are sorted by number, so the example spec comes first
+			press(model, "j\r")
+			Expect(model.View()).To(ContainSubstring("[requirements.md]"))
+			Expect(model.View()).To(ContainSubstring("# Requirements"))
+
+			press(model, "l")
+			Expect(model.View()).To(ContainSubstring("[context-implementation-plan.md]"))
+
+			press(model, "\x1b")
+			Expect(model.View()).To(ContainSubstring("specware - features"))
+			Expect(model.Done()).To(BeFalse())
+			press(model, "q")
+			Expect(model.Done()).To(BeTrue())
+		})
+
+		It("toggles implementation plan checkboxes", func() {
+			press(model, "j\rtj ")
+			Expect(model.View()).To(ContainSubstring("> [x] Step 2: Handlers"))
+			Expect(model.View()).To(ContainSubstring("(1/2 done)"))
+
+			content, err := os.ReadFile(planPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("- [ ] Step 1: Schema\n- [x] Step 2: Handlers"))
+		})
+
+		It("changes the feature status through the status picker", func() {
+			press(model, "jsj\r")
+			Expect(model.View()).To(ContainSubstring("updated to 'Requirements Context Gathering'"))
+
+			features, err := spec.ListFeatures(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(features[1].Status).To(Equal("Requirements Context Gathering"))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks Integration Tests", func() {
+	var tempDir string
+	var projectDir string
+	var specwareBinary string
+
+	run := func(name string, args ...string) (string, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = projectDir
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	mustRun := func(name string, args ...string) {
+		output, err := run(name, args...)
+		Expect(err).NotTo(HaveOccurred(), output)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "specware-hooks-integration-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		specwareBinary = filepath.Join(tempDir, "specware")
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		cmd := exec.Command("go", "build", "-o", specwareBinary, ".")
+		cmd.Dir = filepath.Dir(wd)
+		Expect(cmd.Run()).To(Succeed())
+
+		projectDir = filepath.Join(tempDir, "project")
+		Expect(os.MkdirAll(projectDir, 0755)).To(Succeed())
+		mustRun("git", "init", "-q", "-b", "main")
+		mustRun("git", "config", "user.name", "Test User")
+		mustRun("git", "config", "user.email", "test@example.com")
+		mustRun(specwareBinary, "init", ".", "-y", "--track-specs")
+		mustRun(specwareBinary, "feature", "new-requirements", "user-auth")
+		mustRun("git", "add", "-A")
+		mustRun("git", "commit", "-q", "-m", "Initial commit")
+		mustRun(specwareBinary, "hooks", "install")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should refuse commits with an unknown key in .spec-status.json", func() {
+		statusPath := filepath.Join(projectDir, ".spec", "001-user-auth", ".spec-status.json")
+		Expect(os.WriteFile(statusPath, []byte(`{"current-step": "Requirements Complete", "owner": "me"}`), 0644)).To(Succeed())
+		mustRun("git", "add", "-A")
+
+		output, err := run("git", "commit", "-m", "Update status")
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("specware pre-commit check failed"))
+		Expect(output).To(ContainSubstring(`unknown field "owner"`))
+
+		Expect(os.WriteFile(statusPath, []byte(`{"current-step": "Requirements Complete"}`), 0644)).To(Succeed())
+		mustRun("git", "add", "-A")
+		mustRun("git", "commit", "-q", "-m", "Update status")
+	})
+
+	It("should require a Spec trailer on feature branches when configured", func() {
+		mustRun(specwareBinary, "config", "set", "git.require-spec-trailer", "true")
+		mustRun("git", "checkout", "-q", "-b", "feature/001-user-auth")
+		Expect(os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)).To(Succeed())
+		mustRun("git", "add", "-A")
+
+		output, err := run("git", "commit", "-m", "Add main")
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("Spec: 001-user-auth"))
+
+		mustRun("git", "commit", "-q", "-m", "Add main\n\nSpec: 001-user-auth")
+		output, err = run(specwareBinary, "feature", "commits", "user-auth")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(ContainSubstring("Add main"))
+	})
+	It("should run the hooks for a project in a subdirectory of the repository", func() {
+		repoDir := filepath.Join(tempDir, "repo")
+		projectDir = filepath.Join(repoDir, "sub")
+		Expect(os.MkdirAll(projectDir, 0755)).To(Succeed())
+		mustRun("git", "init", "-q", "-b", "main", repoDir)
+		mustRun("git", "config", "user.name", "Test User")
+		mustRun("git", "config", "user.email", "test@example.com")
+		mustRun(specwareBinary, "init", ".", "-y", "--track-specs")
+		mustRun(specwareBinary, "feature", "new-requirements", "user-auth")
+		mustRun(specwareBinary, "hooks", "install")
+		Expect(filepath.Join(repoDir, ".git", "hooks", "commit-msg")).To(BeAnExistingFile())
+		mustRun("git", "add", "-A")
+		mustRun("git", "commit", "-q", "-m", "Initial commit")
+
+		mustRun(specwareBinary, "config", "set", "git.require-spec-trailer", "true")
+		mustRun("git", "checkout", "-q", "-b", "feature/001-user-auth")
+		Expect(os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)).To(Succeed())
+		mustRun("git", "add", "-A")
+
+		output, err := run("git", "commit", "-m", "Add main")
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring("Spec: 001-user-auth"))
+		mustRun("git", "commit", "-q", "-m", "Add main\n\nSpec: 001-user-auth")
+	})
+})
@@ -288,5 +288,33 @@ var _ = Describe("Init Integration Tests", func() {
 			Expect(filepath.Join(testProjectDir+"-short", ".spec")).To(BeADirectory())
 			Expect(filepath.Join(testProjectDir+"-long", ".spec")).To(BeADirectory())
 		})
+
+		It("should record --track-specs and allow flipping it with config set", func() {
+			cmd := exec.Command(specwareBinary, "init", testProjectDir, "-y", "--track-specs")
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			Expect(string(output)).To(ContainSubstring("Specs will be tracked by git"))
+
+			gitignore, err := os.ReadFile(filepath.Join(testProjectDir, ".gitignore"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(gitignore)).NotTo(ContainSubstring(".spec/"))
+
+			cmd = exec.Command(specwareBinary, "config", "set", "git.track-specs", "false")
+			cmd.Dir = testProjectDir
+			output, err = cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			gitignore, err = os.ReadFile(filepath.Join(testProjectDir, ".gitignore"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(gitignore)).To(ContainSubstring(".spec/\n.claude/\n"))
+		})
+
+		It("should reject --track-specs together with --ignore-specs", func() {
+			cmd := exec.Command(specwareBinary, "init", testProjectDir, "--track-specs", "--ignore-specs")
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(ContainSubstring("cannot be used together"))
+			Expect(filepath.Join(testProjectDir, ".spec")).NotTo(BeADirectory())
+		})
 	})
 })